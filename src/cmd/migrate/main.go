@@ -0,0 +1,20 @@
+// Command migrate manages versioned database schema using the same configuration as server:
+//
+//	migrate up        apply all pending migrations
+//	migrate down N    roll back N most recently applied migrations
+//	migrate goto V    migrate up or down to version V, zero rolls back everything
+//	migrate status    print every known migration and whether it is applied
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
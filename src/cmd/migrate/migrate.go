@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/config"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/migrator"
+	"go.uber.org/zap"
+	"io"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrUsage       = errors.New("usage: migrate up | down N | goto V | status")
+	ErrBadArgument = errors.New("argument must be non negative integer")
+)
+
+// schema is versioned database schema.
+type schema interface {
+	Up(ctx context.Context) (int, error)
+	Down(ctx context.Context, n int) (int, error)
+	Goto(ctx context.Context, v int) (int, error)
+	Status(ctx context.Context) ([]migrator.Status, error)
+}
+
+// run connects to database and executes command from args.
+func run(ctx context.Context, args []string, w io.Writer) error {
+	if err := validate(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.NewPgConfig()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	cli, err := client.Open(cfg, zap.NewNop())
+	if err != nil {
+		return err
+	}
+	defer cli.P().Close()
+
+	m, err := migrator.New(cli)
+	if err != nil {
+		return err
+	}
+	return execute(ctx, m, args, w)
+}
+
+// validate checks args before connecting to database.
+func validate(args []string) error {
+	if len(args) == 0 {
+		return ErrUsage
+	}
+	switch args[0] {
+	case "up", "status":
+		if len(args) != 1 {
+			return ErrUsage
+		}
+	case "down", "goto":
+		if len(args) != 2 {
+			return ErrUsage
+		}
+		if _, err := argument(args[1]); err != nil {
+			return err
+		}
+	default:
+		return ErrUsage
+	}
+	return nil
+}
+
+func argument(raw string) (int, error) {
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrBadArgument, raw)
+	}
+	return n, nil
+}
+
+// execute runs command from validated args over s and writes its result into w.
+func execute(ctx context.Context, s schema, args []string, w io.Writer) (err error) {
+	if err = validate(args); err != nil {
+		return err
+	}
+
+	var n int
+	switch args[0] {
+	case "status":
+		return printStatus(ctx, s, w)
+	case "up":
+		n, err = s.Up(ctx)
+	case "down":
+		steps, _ := argument(args[1])
+		n, err = s.Down(ctx, steps)
+	case "goto":
+		v, _ := argument(args[1])
+		n, err = s.Goto(ctx, v)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	_, err = fmt.Fprintf(w, "%s: %d migrations done\n", args[0], n)
+	return err
+}
+
+func printStatus(ctx context.Context, s schema, w io.Writer) error {
+	status, err := s.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("status: %w", err)
+	}
+	for _, st := range status {
+		applied := "pending"
+		if st.Applied {
+			applied = "applied at " + st.AppliedAt.Format(time.RFC3339)
+		}
+		if _, err = fmt.Fprintf(w, "%04d_%s\t%s\n", st.Version, st.Name, applied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
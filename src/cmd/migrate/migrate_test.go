@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/migrator"
+	"testing"
+	"time"
+)
+
+var errTest = errors.New("test error")
+
+// fakeSchema records calls and returns n and err.
+type fakeSchema struct {
+	calls []string
+	arg   int
+	n     int
+	err   error
+}
+
+func (f *fakeSchema) Up(context.Context) (int, error) {
+	f.calls = append(f.calls, "up")
+	return f.n, f.err
+}
+
+func (f *fakeSchema) Down(_ context.Context, n int) (int, error) {
+	f.calls, f.arg = append(f.calls, "down"), n
+	return f.n, f.err
+}
+
+func (f *fakeSchema) Goto(_ context.Context, v int) (int, error) {
+	f.calls, f.arg = append(f.calls, "goto"), v
+	return f.n, f.err
+}
+
+func (f *fakeSchema) Status(context.Context) ([]migrator.Status, error) {
+	f.calls = append(f.calls, "status")
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []migrator.Status{
+		{Version: 1, Name: "init", Applied: true, AppliedAt: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Version: 2, Name: "next"},
+	}, nil
+}
+
+func TestExecute(t *testing.T) {
+	tt := []struct {
+		name string
+		args []string
+		arg  int
+		out  string
+	}{
+		{"up", []string{"up"}, 0, "up: 3 migrations done\n"},
+		{"down", []string{"down", "2"}, 2, "down: 3 migrations done\n"},
+		{"goto", []string{"goto", "0"}, 0, "goto: 3 migrations done\n"},
+		{"status", []string{"status"}, 0, "0001_init\tapplied at 2023-01-01T00:00:00Z\n0002_next\tpending\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &fakeSchema{n: 3}
+			var w bytes.Buffer
+			assert.NoError(t, execute(context.Background(), s, tc.args, &w))
+			assert.Equal(t, []string{tc.args[0]}, s.calls)
+			assert.Equal(t, tc.arg, s.arg)
+			assert.Equal(t, tc.out, w.String())
+		})
+	}
+}
+
+func TestExecute_Negative(t *testing.T) {
+	for _, args := range [][]string{{"up"}, {"down", "1"}, {"goto", "1"}, {"status"}} {
+		s := &fakeSchema{err: errTest}
+		assert.ErrorIs(t, execute(context.Background(), s, args, &bytes.Buffer{}), errTest)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tt := []struct {
+		args []string
+		err  error
+	}{
+		{nil, ErrUsage},
+		{[]string{"unknown"}, ErrUsage},
+		{[]string{"up", "1"}, ErrUsage},
+		{[]string{"status", "1"}, ErrUsage},
+		{[]string{"down"}, ErrUsage},
+		{[]string{"goto", "1", "2"}, ErrUsage},
+		{[]string{"down", "-1"}, ErrBadArgument},
+		{[]string{"goto", "v1"}, ErrBadArgument},
+		{[]string{"up"}, nil},
+		{[]string{"down", "1"}, nil},
+		{[]string{"goto", "0"}, nil},
+	}
+	for _, tc := range tt {
+		assert.ErrorIs(t, validate(tc.args), tc.err, "%v", tc.args)
+	}
+}
+
+func TestRun_Negative(t *testing.T) {
+	err := run(context.Background(), []string{"unknown"}, &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUsage)
+}
@@ -5,8 +5,12 @@ import (
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller/http"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/middleware"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/outbox"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/memory"
 	pgxStore "github.com/vlad-marlo/yandex-academy-enrollment/internal/store/pgx"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/stream"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/webhook"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/logger"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
@@ -18,6 +22,11 @@ import (
 //	@title		Yandex Lavka
 //	@version	1.0
 
+//	@securityDefinitions.apikey	ApiKeyAuth
+//	@in							header
+//	@name						Authorization
+//	@description				API key as "Bearer <key>".
+
 func main() {
 	fx.New(CreateApp()).Run()
 }
@@ -26,22 +35,89 @@ func main() {
 //
 // This makes available to test is configuration correct.
 func CreateApp() fx.Option {
+	cfg, err := config.NewStoreConfig()
+	if err != nil {
+		return fx.Error(err)
+	}
+	outboxCfg, err := config.NewOutboxConfig()
+	if err != nil {
+		return fx.Error(err)
+	}
 	return fx.Options(
 		fx.Provide(
 			logger.New,
 			fx.Annotate(http.New, fx.As(new(controller.Server))),
 			fx.Annotate(config.NewRateLimiterConfig, fx.As(new(middleware.RateLimitConfig))),
+			fx.Annotate(config.NewAuthConfig, fx.As(new(middleware.AuthConfig))),
 			fx.Annotate(config.NewControllerConfig, fx.As(new(controller.Config))),
+			fx.Annotate(config.NewAssignConfig, fx.As(new(production.Config))),
+			fx.Annotate(production.New, fx.As(new(controller.Service))),
+			fx.Annotate(config.NewWebhookConfig, fx.As(new(webhook.Config))),
+			webhook.NewDispatcher,
+			fx.Annotate(config.NewStreamConfig, fx.As(new(stream.Config))),
+			stream.NewHub,
+			func(hub *stream.Hub) controller.Broker {
+				return hub
+			},
+		),
+		fx.Invoke(RunServer, RunDispatcher, RunHub),
+		StoreOptions(cfg),
+		OutboxOptions(outboxCfg),
+		fx.NopLogger,
+	)
+}
+
+// StoreOptions provides storage selected by config.
+//
+// Postgres storage is migrated on start, memory storage needs no database at all.
+func StoreOptions(cfg *config.StoreConfig) fx.Option {
+	if cfg.StoreDriver() == config.MemoryStoreDriver {
+		return fx.Provide(memory.New, storeInterfaces[*memory.Store])
+	}
+	return fx.Options(
+		fx.Provide(
 			fx.Annotate(config.NewPgConfig, fx.As(new(client.Config))),
 			fx.Annotate(client.New, fx.As(new(pgx.Client))),
-			fx.Annotate(pgxStore.New, fx.As(new(production.Store))),
-			fx.Annotate(production.New, fx.As(new(controller.Service))),
+			pgxStore.New,
+			storeInterfaces[*pgxStore.Store],
 		),
-		fx.Invoke(
-			RunServer,
-			Migrate,
+		fx.Invoke(Migrate),
+	)
+}
+
+// storeInterfaces provides storage as interfaces of service, of outbox relay, of webhook dispatcher and of event
+// stream.
+func storeInterfaces[S interface {
+	production.Store
+	outbox.Store
+	webhook.Store
+	stream.Store
+}](s S) (production.Store, outbox.Store, webhook.Store, stream.Store) {
+	return s, s, s, s
+}
+
+// OutboxOptions runs relay of domain events to sink selected by config.
+//
+// If no sink is selected then relay is not run and events stay in outbox.
+func OutboxOptions(cfg *config.OutboxConfig) fx.Option {
+	var sink any
+	switch cfg.SinkName() {
+	case config.FileOutboxSink:
+		sink = fx.Annotate(outbox.NewFileSink, fx.As(new(outbox.Sink)))
+	case config.WebhookOutboxSink:
+		sink = fx.Annotate(outbox.NewWebhookSink, fx.As(new(outbox.Sink)))
+	default:
+		return fx.Options()
+	}
+	return fx.Options(
+		fx.Provide(
+			func() (outbox.Config, outbox.FileConfig, outbox.WebhookConfig) {
+				return cfg, cfg, cfg
+			},
+			sink,
+			outbox.NewRelay,
 		),
-		fx.NopLogger,
+		fx.Invoke(RunRelay),
 	)
 }
 
@@ -58,3 +134,29 @@ func RunServer(lc fx.Lifecycle, server controller.Server) {
 		OnStop:  server.Stop,
 	})
 }
+
+// RunRelay is helper function to run relay of domain events together with server.
+func RunRelay(lc fx.Lifecycle, relay *outbox.Relay) {
+	lc.Append(fx.Hook{
+		OnStart: relay.Start,
+		OnStop:  relay.Stop,
+	})
+}
+
+// RunDispatcher is helper function to run dispatcher of webhook deliveries together with server.
+func RunDispatcher(lc fx.Lifecycle, dispatcher *webhook.Dispatcher) {
+	lc.Append(fx.Hook{
+		OnStart: dispatcher.Start,
+		OnStop:  dispatcher.Stop,
+	})
+}
+
+// RunHub is helper function to run stream of order events together with server.
+//
+// Hub is stopped before server, so streams of events are closed and do not block shutdown of server.
+func RunHub(lc fx.Lifecycle, hub *stream.Hub) {
+	lc.Append(fx.Hook{
+		OnStart: hub.Start,
+		OnStop:  hub.Stop,
+	})
+}
@@ -2,6 +2,11 @@ package main
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/config"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/stream"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/webhook"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/logger"
 	"go.uber.org/fx"
 	"testing"
 )
@@ -9,3 +14,41 @@ import (
 func TestCreateApp(t *testing.T) {
 	assert.NoError(t, fx.ValidateApp(CreateApp()))
 }
+
+func TestCreateApp_Memory(t *testing.T) {
+	t.Setenv("STORE_DRIVER", config.MemoryStoreDriver)
+	assert.NoError(t, fx.ValidateApp(CreateApp()))
+}
+
+func TestCreateApp_Negative(t *testing.T) {
+	t.Setenv("STORE_DRIVER", "unknown")
+	assert.Error(t, fx.ValidateApp(CreateApp()))
+}
+
+func TestStoreOptions(t *testing.T) {
+	for _, driver := range []string{config.PostgresStoreDriver, config.MemoryStoreDriver} {
+		assert.NoError(t, fx.ValidateApp(
+			StoreOptions(&config.StoreConfig{Driver: driver}),
+			fx.Provide(logger.New),
+			fx.Invoke(func(production.Store, webhook.Store, stream.Store) {}),
+			fx.NopLogger,
+		), driver)
+	}
+}
+
+func TestCreateApp_Outbox(t *testing.T) {
+	t.Setenv("STORE_DRIVER", config.MemoryStoreDriver)
+	t.Run("file", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", config.FileOutboxSink)
+		assert.NoError(t, fx.ValidateApp(CreateApp()))
+	})
+	t.Run("webhook", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", config.WebhookOutboxSink)
+		t.Setenv("OUTBOX_WEBHOOK_URL", "http://localhost/events")
+		assert.NoError(t, fx.ValidateApp(CreateApp()))
+	})
+	t.Run("unknown", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", "kafka")
+		assert.Error(t, fx.ValidateApp(CreateApp()))
+	})
+}
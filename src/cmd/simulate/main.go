@@ -0,0 +1,20 @@
+// Command simulate distributes orders between couriers fully in memory without database.
+//
+// Couriers and orders are read from JSON files in the same shape as bodies of POST /couriers and POST /orders.
+// Instead of files one of generated datasets can be used. Assignment and its metrics are printed to stdout as JSON:
+//
+//	simulate -couriers couriers.json -orders orders.json -strategy optimal
+//	simulate -dataset medium -seed 7
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/dataset"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/metrics"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"io"
+	"os"
+)
+
+var (
+	ErrNoInput    = errors.New("couriers and orders files or dataset must be provided")
+	ErrBadRequest = errors.New("request didn't pass validation")
+)
+
+// Result is output of simulation.
+type Result struct {
+	Assignment *model.OrderAssignResponse `json:"assignment"`
+	Metrics    metrics.Report             `json:"metrics"`
+}
+
+// run parses args, runs simulation and writes result into w.
+func run(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	var (
+		couriersPath = fs.String("couriers", "", "path to JSON file with couriers in shape of POST /couriers body")
+		ordersPath   = fs.String("orders", "", "path to JSON file with orders in shape of POST /orders body")
+		strategy     = fs.String("strategy", assign.GreedyStrategy, "assignment strategy: greedy or optimal")
+		rawDate      = fs.String("date", "", "date of assignment in YYYY-MM-DD format, today by default")
+		size         = fs.String("dataset", "", "name of generated dataset used instead of files: small, medium or large")
+		seed         = fs.Int64("seed", dataset.DefaultSeed, "seed of generated dataset")
+		budget       = fs.Duration("budget", 0, "time limit of search of assignment, for example 2s, no limit by default")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *size == "" && (*couriersPath == "" || *ordersPath == "") {
+		return ErrNoInput
+	}
+
+	a, ok := assign.Strategies[*strategy]
+	if !ok {
+		return fmt.Errorf("%w: %q", assign.ErrUnknownStrategy, *strategy)
+	}
+
+	date := datetime.Today()
+	if *rawDate != "" {
+		var err error
+		if date, err = datetime.ParseDate(*rawDate); err != nil {
+			return fmt.Errorf("date: %w", err)
+		}
+	}
+
+	var (
+		couriersReq = new(model.CreateCourierRequest)
+		ordersReq   = new(model.CreateOrderRequest)
+	)
+	if *size != "" {
+		s, err := dataset.SizeByName(*size)
+		if err != nil {
+			return err
+		}
+		couriersReq, ordersReq = dataset.Generate(s, *seed).Requests()
+	} else {
+		if err := readJSON(*couriersPath, couriersReq); err != nil {
+			return fmt.Errorf("couriers: %w", err)
+		}
+		if err := readJSON(*ordersPath, ordersReq); err != nil {
+			return fmt.Errorf("orders: %w", err)
+		}
+	}
+	if !couriersReq.Valid() {
+		return fmt.Errorf("couriers: %w", ErrBadRequest)
+	}
+	if !ordersReq.Valid() {
+		return fmt.Errorf("orders: %w", ErrBadRequest)
+	}
+
+	ctx := context.Background()
+	if *budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *budget)
+		defer cancel()
+	}
+
+	res := simulate(ctx, a, date.String(), couriers(couriersReq), orders(ordersReq))
+	res.Assignment.Strategy = *strategy
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(res)
+}
+
+// readJSON decodes content of file into v.
+func readJSON(path string, v any) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return json.NewDecoder(f).Decode(v)
+}
+
+// couriers converts request into couriers with ids starting from 1 as they would be stored.
+func couriers(req *model.CreateCourierRequest) []model.CourierDTO {
+	res := make([]model.CourierDTO, 0, len(req.Couriers))
+	for i, c := range req.Couriers {
+		res = append(res, model.CourierDTO{
+			CourierID:    int64(i + 1),
+			CourierType:  c.CourierType,
+			Regions:      c.Regions,
+			WorkingHours: c.WorkingHours,
+		})
+	}
+	return res
+}
+
+// orders converts request into orders which must be distributed with ids starting from 1.
+//
+// Heavy orders are split into sub-orders as service does, sub-orders get ids right after id of order and only they
+// are distributed.
+func orders(req *model.CreateOrderRequest) []*model.OrderDTO {
+	var (
+		res []*model.OrderDTO
+		id  int64
+	)
+	for _, o := range req.Orders {
+		id++
+		subs := o.SubOrders()
+		if len(subs) == 0 {
+			res = append(res, order(id, 0, o))
+			continue
+		}
+		parent := id
+		for _, sub := range subs {
+			id++
+			res = append(res, order(id, parent, sub))
+		}
+	}
+	return res
+}
+
+// order converts order from request into order with provided id and id of parent.
+func order(id, parent int64, o model.CreateOrderDTO) *model.OrderDTO {
+	return &model.OrderDTO{
+		OrderID:       id,
+		Weight:        o.Weight,
+		Regions:       o.Regions,
+		DeliveryHours: o.DeliveryHours,
+		Cost:          o.Cost,
+		ParentOrderID: parent,
+	}
+}
+
+// simulate distributes orders between couriers and scores result.
+func simulate(ctx context.Context, a assign.Assigner, date string, couriers []model.CourierDTO, orders []*model.OrderDTO) *Result {
+	resp := a.Assign(ctx, date, couriers, orders)
+	return &Result{
+		Assignment: resp,
+		Metrics:    metrics.Score(resp, couriers, orders),
+	}
+}
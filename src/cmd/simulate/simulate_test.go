@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/dataset"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	testCouriers = "testdata/couriers.json"
+	testOrders   = "testdata/orders.json"
+)
+
+func TestRun(t *testing.T) {
+	for _, strategy := range []string{assign.GreedyStrategy, assign.OptimalStrategy} {
+		t.Run(strategy, func(t *testing.T) {
+			var w bytes.Buffer
+			require.NoError(t, run([]string{
+				"-couriers", testCouriers,
+				"-orders", testOrders,
+				"-strategy", strategy,
+				"-date", "2023-05-01",
+				"-budget", "1m",
+			}, &w))
+
+			var res Result
+			require.NoError(t, json.Unmarshal(w.Bytes(), &res))
+			assert.Equal(t, "2023-05-01", res.Assignment.Date)
+			assert.Equal(t, strategy, res.Assignment.Strategy)
+
+			// heavy order is split into two sub-orders.
+			assert.Equal(t, 7, res.Metrics.Orders)
+			assert.Equal(t, 6, res.Metrics.Assigned)
+			assert.Equal(t, []model.UnassignedOrder{{OrderID: 8, Reason: model.UnassignedReasonNoRegion}}, res.Assignment.Unassigned)
+			assert.Equal(t, res.Assignment.Cost(), res.Metrics.TotalCost)
+			assert.Len(t, res.Metrics.Couriers, 3)
+			assert.Greater(t, res.Metrics.Utilisation, 0.0)
+			assert.LessOrEqual(t, res.Metrics.Utilisation, 1.0)
+		})
+	}
+}
+
+func TestRun_Dataset(t *testing.T) {
+	args := []string{"-dataset", "small", "-seed", "7", "-date", "2023-05-01"}
+
+	var first, second bytes.Buffer
+	require.NoError(t, run(args, &first))
+	require.NoError(t, run(args, &second))
+	assert.Equal(t, first.String(), second.String())
+
+	var res Result
+	require.NoError(t, json.Unmarshal(first.Bytes(), &res))
+	assert.Equal(t, dataset.Small.Orders, res.Metrics.Orders)
+	assert.Len(t, res.Metrics.Couriers, dataset.Small.Couriers)
+}
+
+func TestRun_Negative(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte("{"), 0o600))
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"couriers": [], "orders": []}`), 0o600))
+
+	tt := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{"no input", nil, ErrNoInput},
+		{"unknown strategy", []string{"-couriers", testCouriers, "-orders", testOrders, "-strategy", "x"}, assign.ErrUnknownStrategy},
+		{"bad date", []string{"-couriers", testCouriers, "-orders", testOrders, "-date", "2023-13-01"}, nil},
+		{"no file", []string{"-couriers", filepath.Join(dir, "none.json"), "-orders", testOrders}, os.ErrNotExist},
+		{"broken file", []string{"-couriers", testCouriers, "-orders", broken}, nil},
+		{"invalid couriers", []string{"-couriers", invalid, "-orders", testOrders}, ErrBadRequest},
+		{"invalid orders", []string{"-couriers", testCouriers, "-orders", invalid}, ErrBadRequest},
+		{"unknown flag", []string{"-unknown"}, nil},
+		{"unknown dataset", []string{"-dataset", "huge"}, nil},
+		{"bad budget", []string{"-dataset", "small", "-budget", "x"}, nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var w bytes.Buffer
+			err := run(tc.args, &w)
+			if assert.Error(t, err) && tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+			assert.Zero(t, w.Len())
+		})
+	}
+}
+
+func TestOrders(t *testing.T) {
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 720}.TimeInterval()}
+	res := orders(&model.CreateOrderRequest{Orders: []model.CreateOrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 10},
+		{Weight: 60, Regions: 2, DeliveryHours: hours, Cost: 10},
+		{Weight: 2, Regions: 3, DeliveryHours: hours, Cost: 10},
+	}})
+
+	var ids, parents []int64
+	for _, o := range res {
+		ids = append(ids, o.OrderID)
+		parents = append(parents, o.ParentOrderID)
+	}
+	assert.Equal(t, []int64{1, 3, 4, 5}, ids)
+	assert.Equal(t, []int64{0, 2, 2, 0}, parents)
+}
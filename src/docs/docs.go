@@ -28,6 +28,11 @@ const docTemplate = `{
                     "courier-controller"
                 ],
                 "summary": "Получение профилей курьеров",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "parameters": [
                     {
                         "type": "integer",
@@ -40,6 +45,12 @@ const docTemplate = `{
                         "description": "Количество курьеров, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0.",
                         "name": "offset",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор страницы из поля next_cursor предыдущего ответа. Не может быть передан вместе с offset.",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -68,6 +79,11 @@ const docTemplate = `{
                     "courier-controller"
                 ],
                 "summary": "Создание профилей курьеров",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "parameters": [
                     {
                         "description": "Couriers",
@@ -107,6 +123,11 @@ const docTemplate = `{
                     "courier-controller"
                 ],
                 "summary": "список распределенных заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "parameters": [
                     {
                         "type": "integer",
@@ -133,6 +154,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
                     }
                 }
             }
@@ -149,6 +176,11 @@ const docTemplate = `{
                     "courier-controller"
                 ],
                 "summary": "Получение meta-информации о курьере.",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "parameters": [
                     {
                         "type": "integer",
@@ -200,6 +232,11 @@ const docTemplate = `{
                     "courier-controller"
                 ],
                 "summary": "Получение профиля курьера",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "parameters": [
                     {
                         "type": "integer",
@@ -229,10 +266,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/orders/": {
-            "get": {
+            },
+            "patch": {
                 "consumes": [
                     "application/json"
                 ],
@@ -240,31 +275,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order-controller"
+                    "courier-controller"
+                ],
+                "summary": "Изменение профиля курьера",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Получение заказов",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Максимальное количество заказов в выдаче. Если параметр не передан, то значение по умолчанию равно 1.",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Courier identifier",
+                        "name": "courier_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Количество заказов, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0.",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateCourierRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.OrderDTO"
-                            }
+                            "$ref": "#/definitions/model.CourierDTO"
                         }
                     },
                     "400": {
@@ -272,10 +313,16 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -283,28 +330,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order-controller"
+                    "courier-controller"
+                ],
+                "summary": "Деактивация курьера",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Создание заказов",
                 "parameters": [
                     {
-                        "description": "Orders",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.CreateOrderRequest"
-                        }
+                        "type": "integer",
+                        "description": "Courier identifier",
+                        "name": "courier_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.OrderDTO"
-                            }
+                            "$ref": "#/definitions/model.CourierDTO"
                         }
                     },
                     "400": {
@@ -312,35 +359,57 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
                     }
-                }
+                },
+                "description": "Deactivated courier is not listed and does not get orders in next assignments."
             }
         },
-        "/orders/assign": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/events": {
+            "get": {
+                "description": "Every event is sent with its id, so client which reconnects with Last-Event-ID header gets events which it missed.\nStream selected by courier does not contain order.created events because new orders have no courier yet. Stream is\nclosed if client does not read events fast enough, client should reconnect then.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "order-controller"
+                    "event-controller"
+                ],
+                "summary": "Поток событий заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Распределение заказов по курьерам",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Дата распределения заказов. Если не указана, то используется текущий день",
-                        "name": "date",
+                        "type": "integer",
+                        "description": "Courier identifier",
+                        "name": "courier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Region of orders",
+                        "name": "region",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Identifier of the last received event",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "201": {
+                    "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.OrderAssignResponse"
+                            "$ref": "#/definitions/model.Event"
                         }
                     },
                     "400": {
@@ -348,12 +417,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
                     }
                 }
             }
         },
-        "/orders/complete": {
+        "/keys": {
             "post": {
+                "description": "Only hash of key is stored, so key is returned only in response of this handler. Key is sent in Authorization\nheader as \"Bearer <key>\". Courier key must be bound to courier and allows only to complete orders of this courier\nand to change their status.",
                 "consumes": [
                     "application/json"
                 ],
@@ -361,28 +437,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order-controller"
+                    "auth-controller"
+                ],
+                "summary": "Выпуск API ключа",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Завершение заказов",
                 "parameters": [
                     {
-                        "description": "Orders",
+                        "description": "API key",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.CompleteOrderRequest"
+                            "$ref": "#/definitions/model.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.OrderDTO"
-                            }
+                            "$ref": "#/definitions/model.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -390,12 +468,24 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
                     }
                 }
             }
         },
-        "/orders/{order_id}": {
-            "get": {
+        "/keys/{key_id}": {
+            "delete": {
                 "consumes": [
                     "application/json"
                 ],
@@ -403,24 +493,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "order-controller"
+                    "auth-controller"
+                ],
+                "summary": "Отзыв API ключа",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
-                "summary": "Получение информации о заказе",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Order identifier",
-                        "name": "order_id",
+                        "description": "API key identifier",
+                        "name": "key_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.OrderDTO"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -428,6 +520,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.BadRequestResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -436,73 +540,832 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.BadRequestResponse": {
-            "type": "object"
-        },
-        "model.CompleteOrder": {
-            "type": "object",
-            "required": [
-                "complete_time",
-                "courier_id",
-                "order_id"
-            ],
-            "properties": {
-                "complete_time": {
-                    "type": "string"
-                },
-                "courier_id": {
-                    "type": "integer"
-                },
-                "order_id": {
-                    "type": "integer"
-                }
-            }
         },
-        "model.CompleteOrderRequest": {
-            "type": "object",
-            "required": [
-                "complete_info"
-            ],
-            "properties": {
-                "complete_info": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.CompleteOrder"
+        "/orders/": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Получение заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     }
-                }
-            }
-        },
-        "model.CourierDTO": {
-            "type": "object",
-            "required": [
-                "courier_id",
-                "courier_type",
-                "regions",
-                "working_hours"
-            ],
-            "properties": {
-                "courier_id": {
-                    "type": "integer",
-                    "example": 2
-                },
-                "courier_type": {
-                    "type": "string",
-                    "enum": [
-                        "FOOT",
-                        "BIKE",
-                        "AUTO"
-                    ],
-                    "example": "AUTO"
-                },
-                "regions": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество заказов в выдаче. Если параметр не передан, то значение по умолчанию равно 1.",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "example": [
+                    {
+                        "type": "integer",
+                        "description": "Количество заказов, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0.",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор страницы из поля next_cursor предыдущего ответа. Не может быть передан вместе с offset.",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Создание заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OrderDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/assign": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Распределение заказов по курьерам",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Дата распределения заказов. Если не указана, то используется текущий день",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "greedy",
+                            "optimal"
+                        ],
+                        "type": "string",
+                        "description": "Стратегия распределения заказов. Если не указана, то используется стратегия по умолчанию",
+                        "name": "strategy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только рассчитать распределение, не сохраняя его",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Распределить заново, даже если заказы на эту дату уже распределены",
+                        "name": "force",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Распределить только новые заказы, сохранив уже сформированные группы заказов",
+                        "name": "incremental",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderAssignResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderAssignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Отмена заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OrderDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                },
+                "description": "This handler is idempotent. Orders which are completed or are delivered by courier can not be cancelled."
+            }
+        },
+        "/orders/complete": {
+            "post": {
+                "description": "This handler is idempotent. Courier key allows to complete only orders of its courier.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Завершение заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CompleteOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.OrderDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Получение информации о заказе",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order identifier",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/history": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "История статусов заказа",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order identifier",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_id}/status": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "order-controller"
+                ],
+                "summary": "Изменение статуса заказа",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order identifier",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChangeOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.OrderDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                },
+                "description": "Courier reports with it that order is taken for delivery or that delivery failed. Courier key allows to\nchange status only of orders of its courier."
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook-controller"
+                ],
+                "summary": "Получение подписок на события",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetWebhooksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook-controller"
+                ],
+                "summary": "Подписка на события заказов",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                },
+                "description": "Deliveries are signed with HMAC-SHA256 of \"<X-Webhook-Timestamp>.<body>\" with secret of webhook in\nX-Webhook-Signature header. Secret is returned only in response of this handler."
+            }
+        },
+        "/webhooks/{webhook_id}": {
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook-controller"
+                ],
+                "summary": "Удаление подписки на события",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook identifier",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                },
+                "description": "Pending deliveries of webhook are failed, history of deliveries is kept."
+            }
+        },
+        "/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook-controller"
+                ],
+                "summary": "История доставок событий",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook identifier",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Максимальное количество доставок в выдаче. Если параметр не передан, то значение по умолчанию равно 1.",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Количество доставок, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0.",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.GetWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook_id}/deliveries/{delivery_id}/replay": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook-controller"
+                ],
+                "summary": "Повторная доставка события",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook identifier",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery identifier",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.BadRequestResponse"
+                        }
+                    }
+                },
+                "description": "Replay creates new delivery, replayed delivery is kept with its attempts."
+            }
+        }
+    },
+    "definitions": {
+        "model.BadRequestResponse": {
+            "type": "object"
+        },
+        "model.CancelOrderRequest": {
+            "type": "object",
+            "required": [
+                "order_ids",
+                "reason"
+            ],
+            "properties": {
+                "order_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "customer withdrew order"
+                }
+            }
+        },
+        "model.ChangeOrderStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "IN_DELIVERY",
+                        "FAILED"
+                    ],
+                    "example": "IN_DELIVERY"
+                }
+            }
+        },
+        "model.CompleteOrder": {
+            "type": "object",
+            "required": [
+                "complete_time",
+                "courier_id",
+                "order_id"
+            ],
+            "properties": {
+                "complete_time": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CompleteOrderRequest": {
+            "type": "object",
+            "required": [
+                "complete_info"
+            ],
+            "properties": {
+                "complete_info": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CompleteOrder"
+                    }
+                }
+            }
+        },
+        "model.CourierDTO": {
+            "type": "object",
+            "required": [
+                "courier_id",
+                "courier_type",
+                "regions",
+                "working_hours"
+            ],
+            "properties": {
+                "active": {
+                    "description": "Active is false for deactivated courier. Deactivated couriers are not listed and do not get orders.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "courier_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "courier_type": {
+                    "type": "string",
+                    "enum": [
+                        "FOOT",
+                        "BIKE",
+                        "AUTO"
+                    ],
+                    "example": "AUTO"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
                         1,
                         2,
                         3
@@ -521,28 +1384,81 @@ const docTemplate = `{
                 }
             }
         },
-        "model.CourierGroupOrders": {
+        "model.CourierGroupOrders": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GroupOrders"
+                    }
+                }
+            }
+        },
+        "model.CouriersCreateResponse": {
+            "type": "object",
+            "properties": {
+                "couriers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CourierDTO"
+                    }
+                }
+            }
+        },
+        "model.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "courier_id": {
+                    "description": "CourierID is id of courier who owns key, it is required for courier role and forbidden for other ones.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "dispatcher",
+                        "courier"
+                    ],
+                    "example": "courier"
+                }
+            }
+        },
+        "model.CreateAPIKeyResponse": {
             "type": "object",
             "properties": {
                 "courier_id": {
-                    "type": "integer"
+                    "description": "CourierID is id of courier who owns key, it is set only for keys with courier role.",
+                    "type": "integer",
+                    "example": 1
                 },
-                "orders": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.GroupOrders"
-                    }
-                }
-            }
-        },
-        "model.CouriersCreateResponse": {
-            "type": "object",
-            "properties": {
-                "couriers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.CourierDTO"
-                    }
+                "created_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Key is sent by client in \"Authorization: Bearer <key>\" header. It is returned only when key is issued.",
+                    "type": "string",
+                    "example": "9b0e6f6c1d2a4b3c8e7f5a4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b1a0"
+                },
+                "key_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "dispatcher",
+                        "courier"
+                    ],
+                    "example": "courier"
                 }
             }
         },
@@ -641,6 +1557,68 @@ const docTemplate = `{
                 }
             }
         },
+        "model.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "order.created",
+                            "order.assigned",
+                            "order.completed",
+                            "order.cancelled"
+                        ]
+                    },
+                    "example": [
+                        "order.created",
+                        "order.completed"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret is key of HMAC-SHA256 signature of deliveries, random secret is generated if it is not provided.",
+                    "type": "string",
+                    "example": "4f1c2a9e0b7d4e3f8a6c5b2d1e0f9a8b"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example/lavka/events"
+                }
+            }
+        },
+        "model.Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID is increasing number of event, consumers may use it to drop events which they got twice.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "courier.created",
+                        "order.created",
+                        "orders.assigned",
+                        "order.assigned",
+                        "order.completed",
+                        "order.cancelled"
+                    ],
+                    "example": "order.created"
+                }
+            }
+        },
         "model.GetCourierMetaInfoResponse": {
             "type": "object",
             "required": [
@@ -707,6 +1685,61 @@ const docTemplate = `{
                 },
                 "offset": {
                     "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "NextCursor is cursor of the next page, it is empty if page is not full.",
+                    "type": "string",
+                    "example": "aWQ6MTI"
+                }
+            }
+        },
+        "model.GetOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "NextCursor is cursor of the next page, it is empty if page is not full.",
+                    "type": "string",
+                    "example": "aWQ6MTI"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderDTO"
+                    }
+                }
+            }
+        },
+        "model.GetWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDelivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GetWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WebhookDTO"
+                    }
                 }
             }
         },
@@ -716,6 +1749,11 @@ const docTemplate = `{
                 "orders"
             ],
             "properties": {
+                "delivery_window": {
+                    "description": "DeliveryWindow is time interval in HH:MM-HH:MM format in which courier delivers group.",
+                    "type": "string",
+                    "example": "12:00-12:35"
+                },
                 "group_order_id": {
                     "type": "integer"
                 },
@@ -738,6 +1776,31 @@ const docTemplate = `{
                 },
                 "date": {
                     "type": "string"
+                },
+                "dry_run": {
+                    "description": "DryRun is true if assignment was only computed and was not stored.",
+                    "type": "boolean"
+                },
+                "strategy": {
+                    "description": "Strategy is name of strategy which was used to assign orders.",
+                    "type": "string",
+                    "enum": [
+                        "greedy",
+                        "optimal"
+                    ],
+                    "example": "greedy"
+                },
+                "total_cost": {
+                    "description": "TotalCost is summary cost of delivery of all groups with grouping discount.",
+                    "type": "number",
+                    "example": 980
+                },
+                "unassigned": {
+                    "description": "Unassigned are orders which were not given to any courier.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UnassignedOrder"
+                    }
                 }
             }
         },
@@ -751,6 +1814,11 @@ const docTemplate = `{
                 "weight"
             ],
             "properties": {
+                "cancel_reason": {
+                    "description": "CancelReason is reason which order was cancelled with.",
+                    "type": "string",
+                    "example": "customer withdrew order"
+                },
                 "completed_time": {
                     "type": "string"
                 },
@@ -764,16 +1832,254 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "delivery_time": {
+                    "description": "DeliveryTime is estimated time of delivery of assigned order in HH:MM format.",
+                    "type": "string",
+                    "example": "12:25"
+                },
                 "order_id": {
                     "type": "integer"
                 },
+                "parent_order_id": {
+                    "description": "ParentOrderID is id of order which was split into sub-orders, one of which is this order.",
+                    "type": "integer"
+                },
                 "regions": {
                     "type": "integer"
                 },
+                "status": {
+                    "description": "Status is current status of order.",
+                    "type": "string",
+                    "enum": [
+                        "CREATED",
+                        "ASSIGNED",
+                        "IN_DELIVERY",
+                        "COMPLETED",
+                        "CANCELLED",
+                        "FAILED"
+                    ],
+                    "example": "CREATED"
+                },
+                "sub_orders": {
+                    "description": "SubOrders are parts of order which is heavier than any courier can carry.\n\nSub-orders are assigned independently, order is completed when all of its sub-orders are completed.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderDTO"
+                    }
+                },
+                "unassigned_reason": {
+                    "description": "UnassignedReason explains why order was left unassigned by the last assignment.",
+                    "type": "string",
+                    "enum": [
+                        "overweight",
+                        "no_courier_in_region",
+                        "no_hours_overlap",
+                        "capacity_exhausted"
+                    ]
+                },
                 "weight": {
                     "type": "number"
                 }
             }
+        },
+        "model.OrderHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OrderStatusChange"
+                    }
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.OrderStatusChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor is who changed status: api, assignment or courier:<courier_id>.",
+                    "type": "string",
+                    "example": "assignment"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "CREATED",
+                        "ASSIGNED",
+                        "IN_DELIVERY",
+                        "COMPLETED",
+                        "CANCELLED",
+                        "FAILED"
+                    ],
+                    "example": "ASSIGNED"
+                }
+            }
+        },
+        "model.UnassignedOrder": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "description": "Reason explains why order was not assigned.",
+                    "type": "string",
+                    "enum": [
+                        "overweight",
+                        "no_courier_in_region",
+                        "no_hours_overlap",
+                        "capacity_exhausted"
+                    ],
+                    "example": "capacity_exhausted"
+                }
+            }
+        },
+        "model.UpdateCourierRequest": {
+            "type": "object",
+            "properties": {
+                "courier_type": {
+                    "type": "string",
+                    "enum": [
+                        "FOOT",
+                        "BIKE",
+                        "AUTO"
+                    ],
+                    "example": "BIKE"
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "working_hours": {
+                    "description": "WorkingHours is string slice of strings that represents time interval.\n\nString must be in HH:MM-HH:MM format where HH is hour (integer 0-23) and MM is minutes (integer 0-59).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "12:00-23:00"
+                    ]
+                }
+            }
+        },
+        "model.WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "order.created",
+                            "order.assigned",
+                            "order.completed",
+                            "order.cancelled"
+                        ]
+                    },
+                    "example": [
+                        "order.created",
+                        "order.completed"
+                    ]
+                },
+                "secret": {
+                    "description": "Secret is key of HMAC-SHA256 signature of deliveries. It is returned only when webhook is created.",
+                    "type": "string",
+                    "example": "4f1c2a9e0b7d4e3f8a6c5b2d1e0f9a8b"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example/lavka/events"
+                },
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts is number of attempts which were made to deliver event.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_type": {
+                    "type": "string",
+                    "enum": [
+                        "order.created",
+                        "order.assigned",
+                        "order.completed",
+                        "order.cancelled"
+                    ],
+                    "example": "order.created"
+                },
+                "last_error": {
+                    "description": "LastError describes why the last attempt failed.",
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "description": "LastStatusCode is status of the last response of webhook.",
+                    "type": "integer",
+                    "example": 200
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is time of the next attempt of pending delivery.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "DELIVERED",
+                        "FAILED"
+                    ],
+                    "example": "DELIVERED"
+                },
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API key as \"Bearer <key>\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
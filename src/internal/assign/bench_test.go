@@ -0,0 +1,66 @@
+package assign
+
+import (
+	"context"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/dataset"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/metrics"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+	"time"
+)
+
+// benchmarkAssign runs assign on generated datasets of provided sizes and reports quality of the last assignment.
+func benchmarkAssign(b *testing.B, sizes []dataset.Size, assign func(d *dataset.Dataset) *model.OrderAssignResponse) {
+	for _, size := range sizes {
+		b.Run(size.Name, func(b *testing.B) {
+			d := dataset.Generate(size, dataset.DefaultSeed)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			var resp *model.OrderAssignResponse
+			for i := 0; i < b.N; i++ {
+				resp = assign(d)
+			}
+
+			b.StopTimer()
+			report := metrics.Score(resp, d.Couriers, d.Orders)
+			b.ReportMetric(report.AssignedShare, "assigned_share")
+			b.ReportMetric(report.TotalCost, "total_cost")
+			b.ReportMetric(float64(report.IdleMinutes), "idle_minutes")
+			b.ReportMetric(float64(report.RegionSwitches), "region_switches")
+		})
+	}
+}
+
+func BenchmarkGreedy_Assign(b *testing.B) {
+	benchmarkAssign(b, dataset.Sizes, func(d *dataset.Dataset) *model.OrderAssignResponse {
+		return Greedy{}.Assign(context.Background(), "2023-01-01", d.Couriers, d.Orders)
+	})
+}
+
+func BenchmarkOptimal_Assign(b *testing.B) {
+	benchmarkAssign(b, []dataset.Size{dataset.Small, dataset.Medium}, func(d *dataset.Dataset) *model.OrderAssignResponse {
+		return Optimal{}.Assign(context.Background(), "2023-01-01", d.Couriers, d.Orders)
+	})
+}
+
+// benchBudget is time limit of search of optimal strategy on large dataset.
+const benchBudget = 2 * time.Second
+
+// BenchmarkOptimal_AssignBudget runs optimal strategy with time limit: every improvement pass tries all pairs of
+// groups, so without limit it takes minutes on large dataset.
+func BenchmarkOptimal_AssignBudget(b *testing.B) {
+	benchmarkAssign(b, dataset.Sizes, func(d *dataset.Dataset) *model.OrderAssignResponse {
+		ctx, cancel := context.WithTimeout(context.Background(), benchBudget)
+		defer cancel()
+		return Optimal{}.Assign(ctx, "2023-01-01", d.Couriers, d.Orders)
+	})
+}
+
+func BenchmarkGreedy_Extend(b *testing.B) {
+	benchmarkAssign(b, dataset.Sizes, func(d *dataset.Dataset) *model.OrderAssignResponse {
+		half := len(d.Orders) / 2
+		existing := Greedy{}.Assign(context.Background(), "2023-01-01", d.Couriers, d.Orders[:half])
+		return Greedy{}.Extend(context.Background(), existing, d.Couriers, d.Orders[half:])
+	})
+}
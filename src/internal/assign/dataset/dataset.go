@@ -0,0 +1,169 @@
+// Package dataset generates reproducible sets of couriers and orders to exercise assignment of orders.
+package dataset
+
+import (
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"math/rand"
+	"sort"
+)
+
+// Size describes size of generated dataset.
+type Size struct {
+	Name     string
+	Couriers int
+	Orders   int
+	Regions  int
+}
+
+// Predefined sizes of datasets.
+var (
+	Small  = Size{Name: "small", Couriers: 10, Orders: 100, Regions: 5}
+	Medium = Size{Name: "medium", Couriers: 50, Orders: 1000, Regions: 10}
+	Large  = Size{Name: "large", Couriers: 200, Orders: 5000, Regions: 20}
+
+	Sizes = []Size{Small, Medium, Large}
+)
+
+// DefaultSeed is seed of datasets which are used by benchmarks.
+const DefaultSeed = 42
+
+// Bounds of generated data in minutes and in units of model.
+const (
+	dayStart = 7 * 60
+	dayEnd   = 22 * 60
+
+	minShift = 2 * 60
+	maxShift = 8 * 60
+
+	minDeliveryHours = 60
+	maxDeliveryHours = 4 * 60
+
+	minCost = 50
+	maxCost = 1000
+
+	// meanWeight is mean weight of order, most of orders are light and only some of them need AUTO courier.
+	meanWeight = 5
+)
+
+var courierTypes = []string{model.FootCourierTypeString, model.BikeCourierTypeString, model.AutoCourierTypeString}
+
+// Dataset is generated couriers and orders with ids starting from 1.
+type Dataset struct {
+	Couriers []model.CourierDTO
+	Orders   []*model.OrderDTO
+}
+
+// SizeByName returns predefined size with provided name.
+func SizeByName(name string) (Size, error) {
+	for _, size := range Sizes {
+		if size.Name == name {
+			return size, nil
+		}
+	}
+	return Size{}, fmt.Errorf("unknown dataset size %q", name)
+}
+
+// Generate returns dataset of provided size.
+//
+// Datasets generated with the same size and seed are equal. Orders are never heavier than AUTO courier can carry.
+func Generate(size Size, seed int64) *Dataset {
+	rnd := rand.New(rand.NewSource(seed))
+	regions := size.Regions
+	if regions <= 0 {
+		regions = 1
+	}
+
+	d := &Dataset{
+		Couriers: make([]model.CourierDTO, 0, size.Couriers),
+		Orders:   make([]*model.OrderDTO, 0, size.Orders),
+	}
+	for i := 0; i < size.Couriers; i++ {
+		d.Couriers = append(d.Couriers, courier(rnd, int64(i+1), regions))
+	}
+	for i := 0; i < size.Orders; i++ {
+		d.Orders = append(d.Orders, order(rnd, int64(i+1), regions))
+	}
+	return d
+}
+
+// Requests returns dataset in shape of bodies of POST /couriers and POST /orders.
+func (d *Dataset) Requests() (*model.CreateCourierRequest, *model.CreateOrderRequest) {
+	couriers := &model.CreateCourierRequest{Couriers: make([]model.CreateCourierDTO, 0, len(d.Couriers))}
+	for _, c := range d.Couriers {
+		couriers.Couriers = append(couriers.Couriers, model.CreateCourierDTO{
+			CourierType:  c.CourierType,
+			Regions:      c.Regions,
+			WorkingHours: c.WorkingHours,
+		})
+	}
+	orders := &model.CreateOrderRequest{Orders: make([]model.CreateOrderDTO, 0, len(d.Orders))}
+	for _, o := range d.Orders {
+		orders.Orders = append(orders.Orders, model.CreateOrderDTO{
+			Weight:        o.Weight,
+			Regions:       o.Regions,
+			DeliveryHours: o.DeliveryHours,
+			Cost:          o.Cost,
+		})
+	}
+	return couriers, orders
+}
+
+// courier returns courier with random type, distinct regions and one or two shifts.
+func courier(rnd *rand.Rand, id int64, regions int) model.CourierDTO {
+	c := model.CourierDTO{
+		CourierID:   id,
+		CourierType: courierTypes[rnd.Intn(len(courierTypes))],
+	}
+
+	count := 1 + rnd.Intn(c.MaxRegions())
+	if count > regions {
+		count = regions
+	}
+	for _, region := range rnd.Perm(regions)[:count] {
+		c.Regions = append(c.Regions, int32(region+1))
+	}
+	sort.Slice(c.Regions, func(i, j int) bool {
+		return c.Regions[i] < c.Regions[j]
+	})
+
+	start := dayStart + rnd.Intn(dayEnd-dayStart-minShift)
+	end := start + minShift + rnd.Intn(maxShift-minShift)
+	if end > dayEnd {
+		end = dayEnd
+	}
+	c.WorkingHours = append(c.WorkingHours, interval(start, end))
+	// some couriers come back after break.
+	if gap := end + 60; rnd.Intn(3) == 0 && gap+minShift <= dayEnd {
+		c.WorkingHours = append(c.WorkingHours, interval(gap, gap+minShift+rnd.Intn(dayEnd-gap-minShift+1)))
+	}
+	return c
+}
+
+// order returns order with random weight, region, delivery hours and cost.
+func order(rnd *rand.Rand, id int64, regions int) *model.OrderDTO {
+	weight := 0.1 + rnd.ExpFloat64()*meanWeight
+	if weight > model.AutoCourierMaxWeight {
+		weight = model.AutoCourierMaxWeight
+	}
+	start := dayStart + rnd.Intn(dayEnd-dayStart-minDeliveryHours)
+	end := start + minDeliveryHours + rnd.Intn(maxDeliveryHours-minDeliveryHours)
+	if end > dayEnd {
+		end = dayEnd
+	}
+	// weight is rounded to grams as it is done by clients.
+	weight = float64(int(weight*1000)) / 1000
+	return &model.OrderDTO{
+		OrderID:       id,
+		Weight:        weight,
+		Regions:       int32(1 + rnd.Intn(regions)),
+		DeliveryHours: []*datetime.TimeInterval{interval(start, end)},
+		Cost:          int32(minCost + rnd.Intn(maxCost-minCost)),
+	}
+}
+
+// interval returns time interval between provided minutes of day.
+func interval(start, end int) *datetime.TimeInterval {
+	return datetime.TimeIntervalAlias{Start: int32(start), End: int32(end)}.TimeInterval()
+}
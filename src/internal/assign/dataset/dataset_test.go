@@ -0,0 +1,51 @@
+package dataset
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestGenerate_Reproducible(t *testing.T) {
+	assert.Equal(t, Generate(Small, DefaultSeed), Generate(Small, DefaultSeed))
+	assert.NotEqual(t, Generate(Small, DefaultSeed), Generate(Small, DefaultSeed+1))
+}
+
+func TestGenerate_Valid(t *testing.T) {
+	for _, size := range Sizes {
+		t.Run(size.Name, func(t *testing.T) {
+			d := Generate(size, DefaultSeed)
+			assert.Len(t, d.Couriers, size.Couriers)
+			assert.Len(t, d.Orders, size.Orders)
+
+			couriers, orders := d.Requests()
+			assert.True(t, couriers.Valid())
+			assert.True(t, orders.Valid())
+
+			for i, c := range d.Couriers {
+				assert.Equal(t, int64(i+1), c.CourierID)
+				assert.LessOrEqual(t, len(c.Regions), c.MaxRegions())
+				for _, h := range c.WorkingHours {
+					assert.Less(t, h.Start(), h.End())
+				}
+			}
+			for i, o := range d.Orders {
+				assert.Equal(t, int64(i+1), o.OrderID)
+				assert.Greater(t, o.Weight, 0.0)
+				assert.LessOrEqual(t, o.Weight, float64(model.AutoCourierMaxWeight))
+				assert.GreaterOrEqual(t, o.Regions, int32(1))
+				assert.LessOrEqual(t, o.Regions, int32(size.Regions))
+				assert.Greater(t, o.Cost, int32(0))
+			}
+		})
+	}
+}
+
+func TestSizeByName(t *testing.T) {
+	size, err := SizeByName("medium")
+	assert.NoError(t, err)
+	assert.Equal(t, Medium, size)
+
+	_, err = SizeByName("huge")
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package assign
+
+import (
+	"context"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+)
+
+// Greedy is fast assignment strategy which builds groups one by one and never reconsiders them.
+type Greedy struct{}
+
+// Assign distributes orders between couriers.
+//
+// Orders are handed out in rounds: on every round each courier receives at most one group, so orders are spread
+// between couriers instead of being taken by the first one. Heavy orders are placed first because they are the
+// hardest to fit. Every group gets the earliest free window in working hours of courier. Orders that can not be
+// delivered by any courier are reported as unassigned. Greedy makes only one pass over orders, so it ignores ctx.
+func (Greedy) Assign(_ context.Context, date string, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse {
+	s := newSolution(date, couriers, orders)
+	s.greedy()
+	return s.response()
+}
+
+// greedy assigns pending orders by rounds.
+func (s *solution) greedy() {
+	// exhausted couriers will never get new group because pending orders and free time are only decreasing.
+	exhausted := make(map[*courierState]bool)
+	for assigned := true; assigned && len(s.pending) > 0; {
+		assigned = false
+		for _, c := range s.couriers {
+			if exhausted[c] {
+				continue
+			}
+			g := c.collect(s.pending)
+			if g == nil {
+				exhausted[c] = true
+				continue
+			}
+			s.pending = without(s.pending, g.orders...)
+			assigned = true
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package assign
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func testInterval(t testing.TB, raw string) *datetime.TimeInterval {
+	t.Helper()
+	h, err := datetime.ParseTimeInterval(raw)
+	require.NoError(t, err)
+	return h
+}
+
+func testCourier(t testing.TB, id int64, courierType string, hours string, regions ...int32) model.CourierDTO {
+	t.Helper()
+	return model.CourierDTO{
+		CourierID:    id,
+		CourierType:  courierType,
+		Regions:      regions,
+		WorkingHours: []*datetime.TimeInterval{testInterval(t, hours)},
+	}
+}
+
+func testOrder(t testing.TB, id int64, weight float64, region int32, hours string) *model.OrderDTO {
+	t.Helper()
+	return &model.OrderDTO{
+		OrderID:       id,
+		Weight:        weight,
+		Regions:       region,
+		DeliveryHours: []*datetime.TimeInterval{testInterval(t, hours)},
+		Cost:          100,
+	}
+}
+
+// assignedIDs returns ids of orders grouped by courier id.
+func assignedIDs(resp *model.OrderAssignResponse) map[int64][][]int64 {
+	res := make(map[int64][][]int64)
+	for _, c := range resp.Couriers {
+		for _, g := range c.Orders {
+			var ids []int64
+			for _, o := range g.Orders {
+				ids = append(ids, o.OrderID)
+			}
+			res[c.CourierID] = append(res[c.CourierID], ids)
+		}
+	}
+	return res
+}
+
+func TestGreedy_Empty(t *testing.T) {
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", nil, nil)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "2023-01-01", resp.Date)
+		assert.NotNil(t, resp.Couriers)
+		assert.Empty(t, resp.Couriers)
+	}
+}
+
+func TestGreedy_RespectsRegions(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 2, "10:00-12:00"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Empty(t, resp.Couriers)
+	assert.Equal(t, []model.UnassignedOrder{{OrderID: 1, Reason: model.UnassignedReasonNoRegion}}, resp.Unassigned)
+}
+
+func TestGreedy_RespectsWorkingHours(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "13:00-14:00"),
+		testOrder(t, 2, 1, 1, "11:00-14:00"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{2}}}, assignedIDs(resp))
+	assert.Equal(t, []model.UnassignedOrder{{OrderID: 1, Reason: model.UnassignedReasonNoHoursOverlap}}, resp.Unassigned)
+}
+
+func TestGreedy_RespectsCapacity(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier string
+		orders  int
+		weight  float64
+		want    [][]int64
+	}{
+		{"foot count", model.FootCourierTypeString, 3, 1, [][]int64{{1, 2}, {3}}},
+		{"foot weight", model.FootCourierTypeString, 2, 6, [][]int64{{1}, {2}}},
+		{"foot overweight", model.FootCourierTypeString, 1, 11, nil},
+		{"bike count", model.BikeCourierTypeString, 5, 1, [][]int64{{1, 2, 3, 4}, {5}}},
+		{"bike weight", model.BikeCourierTypeString, 3, 7, [][]int64{{1, 2}, {3}}},
+		{"auto count", model.AutoCourierTypeString, 8, 1, [][]int64{{1, 2, 3, 4, 5, 6, 7}, {8}}},
+		{"auto weight", model.AutoCourierTypeString, 3, 15, [][]int64{{1, 2}, {3}}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			couriers := []model.CourierDTO{
+				testCourier(t, 1, tc.courier, "00:00-23:59", 1),
+			}
+			var orders []*model.OrderDTO
+			for i := 1; i <= tc.orders; i++ {
+				orders = append(orders, testOrder(t, int64(i), tc.weight, 1, "00:00-23:59"))
+			}
+			resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+			assert.Equal(t, tc.want, assignedIDs(resp)[1])
+		})
+	}
+}
+
+func TestGreedy_RespectsRegionsLimit(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.BikeCourierTypeString, "00:00-23:59", 1, 2, 3),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:00-23:59"),
+		testOrder(t, 2, 1, 2, "00:00-23:59"),
+		testOrder(t, 3, 1, 3, "00:00-23:59"),
+		testOrder(t, 4, 1, 1, "00:00-23:59"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 4, 2}, {3}}}, assignedIDs(resp))
+}
+
+func TestGreedy_SpreadsBetweenCouriers(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 2, model.FootCourierTypeString, "00:00-23:59", 1),
+		testCourier(t, 1, model.FootCourierTypeString, "00:00-23:59", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:00-23:59"),
+		testOrder(t, 2, 1, 1, "00:00-23:59"),
+		testOrder(t, 3, 1, 1, "00:00-23:59"),
+		testOrder(t, 4, 1, 1, "00:00-23:59"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 2}}, 2: {{3, 4}}}, assignedIDs(resp))
+	if assert.Len(t, resp.Couriers, 2) {
+		assert.Equal(t, int64(1), resp.Couriers[0].CourierID)
+		assert.Equal(t, int64(2), resp.Couriers[1].CourierID)
+	}
+}
+
+func TestGreedy_HeavyOrdersFirst(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "00:00-23:59", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:00-23:59"),
+		testOrder(t, 2, 9, 1, "00:00-23:59"),
+		testOrder(t, 3, 5, 1, "00:00-23:59"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{2, 1}, {3}}}, assignedIDs(resp))
+}
+
+func TestGreedy_UnassignedReasons(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-10:30", 1),
+		testCourier(t, 2, model.BikeCourierTypeString, "10:00-12:00", 2),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 41, 1, "10:00-12:00"),
+		testOrder(t, 2, 1, 3, "10:00-12:00"),
+		testOrder(t, 3, 15, 1, "10:00-12:00"),
+		testOrder(t, 4, 1, 2, "13:00-14:00"),
+		testOrder(t, 5, 1, 1, "10:00-10:30"),
+		testOrder(t, 6, 1, 1, "10:00-10:30"),
+		testOrder(t, 7, 1, 1, "10:00-10:30"),
+	}
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{5}}}, assignedIDs(resp))
+	assert.Equal(t, []model.UnassignedOrder{
+		{OrderID: 1, Reason: model.UnassignedReasonOverweight},
+		{OrderID: 2, Reason: model.UnassignedReasonNoRegion},
+		{OrderID: 3, Reason: model.UnassignedReasonOverweight},
+		{OrderID: 4, Reason: model.UnassignedReasonNoHoursOverlap},
+		{OrderID: 6, Reason: model.UnassignedReasonCapacityExhausted},
+		{OrderID: 7, Reason: model.UnassignedReasonCapacityExhausted},
+	}, resp.Unassigned)
+}
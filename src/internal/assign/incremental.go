@@ -0,0 +1,178 @@
+package assign
+
+import (
+	"context"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+)
+
+// Extend distributes new orders keeping groups of existing assignment.
+//
+// New orders are appended to the end of existing groups which have spare capacity and free time right after them,
+// so orders that couriers already have keep their place and delivery time. Orders that do not fit into existing
+// groups are put into new groups in free working time of couriers.
+func (Greedy) Extend(_ context.Context, existing *model.OrderAssignResponse, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse {
+	s := newExtendedSolution(existing, couriers, orders)
+	s.appendPending()
+	s.greedy()
+	return s.response()
+}
+
+// Extend distributes new orders keeping groups of existing assignment.
+//
+// It works as Greedy.Extend and then improves only new groups, existing groups are never reshuffled.
+func (o Optimal) Extend(ctx context.Context, existing *model.OrderAssignResponse, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse {
+	s := newExtendedSolution(existing, couriers, orders)
+	s.appendPending()
+	s.greedy()
+	o.improve(ctx, s)
+	return s.response()
+}
+
+// newExtendedSolution returns solution with restored groups of existing assignment.
+//
+// Orders of existing groups are fixed. Groups of couriers which are not provided are kept as is.
+func newExtendedSolution(existing *model.OrderAssignResponse, couriers []model.CourierDTO, orders []*model.OrderDTO) *solution {
+	if existing == nil {
+		existing = new(model.OrderAssignResponse)
+	}
+	s := newSolution(existing.Date, couriers, orders)
+
+	byID := make(map[int64]*courierState, len(s.couriers))
+	for _, c := range s.couriers {
+		byID[c.dto.CourierID] = c
+	}
+	for _, assigned := range existing.Couriers {
+		c, ok := byID[assigned.CourierID]
+		if !ok {
+			c = newCourierState(&model.CourierDTO{CourierID: assigned.CourierID})
+			byID[assigned.CourierID] = c
+			s.couriers = append(s.couriers, c)
+		}
+		for i := range assigned.Orders {
+			c.restore(&assigned.Orders[i])
+		}
+	}
+	sort.SliceStable(s.couriers, func(i, j int) bool {
+		return s.couriers[i].dto.CourierID < s.couriers[j].dto.CourierID
+	})
+	return s
+}
+
+// restore adds stored group into courier's state and marks its time as busy.
+//
+// If group can not be located in working hours of courier then it is kept frozen and is never extended.
+func (c *courierState) restore(stored *model.GroupOrders) {
+	g := newGroup()
+	g.id = stored.GroupOrderID
+	for i := range stored.Orders {
+		order := stored.Orders[i]
+		g.orders = append(g.orders, &order)
+		g.weight += order.Weight
+		g.regions.Add(order.Regions)
+	}
+	g.fixed = len(g.orders)
+
+	if g.place = c.locate(stored); g.place == nil {
+		g.frozen = stored
+		c.groups = append(c.groups, g)
+		c.sortGroups()
+		return
+	}
+	c.reserve(g)
+}
+
+// locate returns placement of stored group in working hours of courier.
+func (c *courierState) locate(stored *model.GroupOrders) *placement {
+	if stored.DeliveryWindow == nil || len(stored.Orders) == 0 {
+		return nil
+	}
+	start := stored.DeliveryWindow.Start()
+	duration := stored.DeliveryWindow.End().Sub(start)
+	offsets := make([]int, 0, len(stored.Orders))
+	for _, order := range stored.Orders {
+		if order.DeliveryTime == nil {
+			return nil
+		}
+		offsets = append(offsets, order.DeliveryTime.Sub(start))
+	}
+
+	for slot, h := range c.hours {
+		offset := start.Sub(h.Start())
+		if offset+duration > h.End().Sub(h.Start()) {
+			continue
+		}
+		w := window{slot: slot, start: offset, end: offset + duration}
+		if !c.free(w.slot, w.start, w.end) {
+			continue
+		}
+		return &placement{
+			window:  w,
+			offsets: offsets,
+			from:    h.Start(),
+		}
+	}
+	return nil
+}
+
+// extend returns placement of group with order delivered after all orders of group.
+//
+// If order can not be appended to the end of group then nil will be returned.
+func (g *group) extend(c *courierState, order *model.OrderDTO) *placement {
+	if g.place == nil || !c.canServe(order) || !c.fitsLimits(append(g.orders[:len(g.orders):len(g.orders)], order)) {
+		return nil
+	}
+	step := c.dto.NextDeliveryMinutes()
+	if g.orders[len(g.orders)-1].Regions != order.Regions {
+		step = c.dto.FirstDeliveryMinutes()
+	}
+	offset := g.place.offsets[len(g.place.offsets)-1] + step
+	end := g.place.start + offset
+
+	h := c.hours[g.place.slot]
+	if end > h.End().Sub(h.Start()) || !c.free(g.place.slot, g.place.end, end) {
+		return nil
+	}
+	if !deliveredAt(order, g.place.Start().Add(offset)) {
+		return nil
+	}
+	return &placement{
+		window:  window{slot: g.place.slot, start: g.place.start, end: end},
+		offsets: append(g.place.offsets[:len(g.place.offsets):len(g.place.offsets)], offset),
+		from:    g.place.from,
+	}
+}
+
+// append adds order to the end of group without changing order of delivery.
+func (c *courierState) append(g *group, order *model.OrderDTO, place *placement) {
+	c.release(g.place.window)
+	c.occupy(place.window)
+	g.orders = append(g.orders, order)
+	g.weight += order.Weight
+	g.regions.Add(order.Regions)
+	g.place = place
+}
+
+// appendPending appends pending orders to the end of existing groups where it is possible.
+func (s *solution) appendPending() {
+	for _, order := range append([]*model.OrderDTO(nil), s.pending...) {
+		if s.appendOrder(order) {
+			s.pending = without(s.pending, order)
+		}
+	}
+}
+
+func (s *solution) appendOrder(order *model.OrderDTO) bool {
+	for _, c := range s.couriers {
+		for _, g := range c.groups {
+			if g.fixed == 0 {
+				continue
+			}
+			if place := g.extend(c, order); place != nil {
+				c.append(g, order, place)
+				return true
+			}
+		}
+	}
+	return false
+}
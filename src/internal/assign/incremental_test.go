@@ -0,0 +1,162 @@
+package assign
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+// storedGroup returns stored group with provided id, window and delivery times of orders.
+func storedGroup(t testing.TB, id int64, raw string, orders []*model.OrderDTO, times ...string) model.GroupOrders {
+	t.Helper()
+	require.Len(t, times, len(orders))
+	g := model.GroupOrders{
+		GroupOrderID:   id,
+		DeliveryWindow: testInterval(t, raw),
+	}
+	for i, order := range orders {
+		o := *order
+		m, err := datetime.ParseTime(times[i])
+		require.NoError(t, err)
+		o.DeliveryTime = &m
+		g.Orders = append(g.Orders, o)
+	}
+	return g
+}
+
+func TestGreedy_Extend_Empty(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "10:00-12:00"),
+	}
+	want := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	got := Greedy{}.Extend(context.Background(), &model.OrderAssignResponse{Date: "2023-01-01"}, couriers, orders)
+	assert.Equal(t, want, got)
+}
+
+func TestGreedy_Extend_AppendsToExistingGroup(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.BikeCourierTypeString, "10:00-12:00", 1, 2),
+	}
+	existing := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{
+				storedGroup(t, 7, "10:00-10:20", []*model.OrderDTO{
+					testOrder(t, 1, 5, 1, "10:00-12:00"),
+					testOrder(t, 2, 5, 1, "10:00-12:00"),
+				}, "10:12", "10:20"),
+			},
+		}},
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 3, 5, 2, "10:00-12:00"),
+		testOrder(t, 4, 5, 2, "10:00-12:00"),
+		testOrder(t, 5, 5, 1, "10:00-12:00"),
+	}
+
+	resp := Greedy{}.Extend(context.Background(), existing, couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 2, 3, 4}, {5}}}, assignedIDs(resp))
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 2) {
+		group := resp.Couriers[0].Orders[0]
+		assert.Equal(t, int64(7), group.GroupOrderID)
+		assert.Equal(t, "10:00-10:40", group.DeliveryWindow.String())
+		var times []string
+		for _, o := range group.Orders {
+			times = append(times, o.DeliveryTime.String())
+		}
+		assert.Equal(t, []string{"10:12", "10:20", "10:32", "10:40"}, times)
+
+		next := resp.Couriers[0].Orders[1]
+		assert.Zero(t, next.GroupOrderID)
+		assert.Equal(t, "10:40-10:52", next.DeliveryWindow.String())
+	}
+}
+
+func TestGreedy_Extend_KeepsExistingTime(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-11:00", 1),
+	}
+	existing := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{
+				storedGroup(t, 1, "10:20-10:45", []*model.OrderDTO{
+					testOrder(t, 1, 1, 1, "10:00-11:00"),
+				}, "10:45"),
+			},
+		}},
+	}
+	orders := []*model.OrderDTO{
+		// ends at 10:55, it fits only at the end of existing group.
+		testOrder(t, 2, 1, 1, "10:50-11:00"),
+		// there is no free time before existing group for the next order.
+		testOrder(t, 3, 1, 1, "10:00-11:00"),
+	}
+
+	resp := Greedy{}.Extend(context.Background(), existing, couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 2}}}, assignedIDs(resp))
+	assert.Equal(t, []model.UnassignedOrder{{OrderID: 3, Reason: model.UnassignedReasonCapacityExhausted}}, resp.Unassigned)
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		assert.Equal(t, "10:20-10:55", resp.Couriers[0].Orders[0].DeliveryWindow.String())
+	}
+}
+
+func TestGreedy_Extend_FrozenGroups(t *testing.T) {
+	existing := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 2,
+			Orders: []model.GroupOrders{
+				storedGroup(t, 1, "10:00-10:25", []*model.OrderDTO{
+					testOrder(t, 1, 1, 1, "10:00-11:00"),
+				}, "10:25"),
+			},
+		}},
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 2, 1, 1, "10:00-11:00"),
+	}
+
+	resp := Greedy{}.Extend(context.Background(), existing, nil, orders)
+	assert.Equal(t, existing.Couriers, resp.Couriers)
+	assert.Equal(t, []model.UnassignedOrder{{OrderID: 2, Reason: model.UnassignedReasonNoRegion}}, resp.Unassigned)
+}
+
+func TestOptimal_Extend_DoesNotMoveExistingOrders(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "00:00-23:59", 1),
+		testCourier(t, 2, model.BikeCourierTypeString, "00:00-23:59", 1),
+	}
+	existing := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{
+				storedGroup(t, 1, "00:00-00:25", []*model.OrderDTO{
+					testOrder(t, 1, 1, 1, "00:00-23:59"),
+				}, "00:25"),
+			},
+		}},
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 2, 1, 1, "00:00-23:59"),
+		testOrder(t, 3, 1, 1, "00:00-23:59"),
+		testOrder(t, 4, 1, 1, "00:00-23:59"),
+	}
+
+	resp := Optimal{}.Extend(context.Background(), existing, couriers, orders)
+	ids := assignedIDs(resp)
+	if assert.NotEmpty(t, ids[1]) {
+		assert.Equal(t, int64(1), ids[1][0][0])
+	}
+	assert.Equal(t, 4, countOrders(resp))
+	assert.Empty(t, resp.Unassigned)
+}
@@ -0,0 +1,126 @@
+// Package metrics scores quality of assignment of orders between couriers.
+package metrics
+
+import (
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/collections"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+)
+
+// Report is score of assignment.
+type Report struct {
+	// Orders is count of orders which had to be distributed.
+	Orders int `json:"orders"`
+	// Assigned is count of provided orders which were assigned to couriers.
+	Assigned int `json:"assigned"`
+	// AssignedShare is share of provided orders which were assigned to couriers.
+	AssignedShare float64 `json:"assigned_share"`
+	// TotalCost is summary cost of delivery of all groups with discount for orders delivered in one group.
+	TotalCost float64 `json:"total_cost"`
+	// IdleMinutes is summary working time of all couriers which is not spent on delivery.
+	IdleMinutes int `json:"idle_minutes"`
+	// RegionSwitches is summary count of moves between regions inside groups of orders.
+	RegionSwitches int `json:"region_switches"`
+	// Utilisation is share of working time of all couriers which is spent on delivery.
+	Utilisation float64   `json:"utilisation"`
+	Couriers    []Courier `json:"couriers"`
+}
+
+// Courier is score of work of one courier.
+type Courier struct {
+	CourierID      int64   `json:"courier_id"`
+	Groups         int     `json:"groups"`
+	Orders         int     `json:"orders"`
+	WorkingMinutes int     `json:"working_minutes"`
+	BusyMinutes    int     `json:"busy_minutes"`
+	IdleMinutes    int     `json:"idle_minutes"`
+	RegionSwitches int     `json:"region_switches"`
+	Utilisation    float64 `json:"utilisation"`
+}
+
+// Score returns report about assignment of orders between couriers.
+//
+// Only provided orders are counted as assigned, couriers which are not provided are ignored. Couriers are reported in
+// the same order as they are provided.
+func Score(resp *model.OrderAssignResponse, couriers []model.CourierDTO, orders []*model.OrderDTO) Report {
+	r := Report{
+		Orders:   len(orders),
+		Couriers: make([]Courier, 0, len(couriers)),
+	}
+
+	ids := collections.NewSet[int64]()
+	for _, order := range orders {
+		ids.Add(order.OrderID)
+	}
+
+	assigned := make(map[int64]*model.CourierGroupOrders)
+	if resp != nil {
+		for i := range resp.Couriers {
+			assigned[resp.Couriers[i].CourierID] = &resp.Couriers[i]
+		}
+	}
+
+	var working, busy int
+	for _, courier := range couriers {
+		c := score(&courier, assigned[courier.CourierID], ids)
+		r.Assigned += c.Orders
+		r.TotalCost += cost(assigned[courier.CourierID])
+		r.IdleMinutes += c.IdleMinutes
+		r.RegionSwitches += c.RegionSwitches
+		working += c.WorkingMinutes
+		busy += c.BusyMinutes
+		r.Couriers = append(r.Couriers, c)
+	}
+
+	if r.Orders > 0 {
+		r.AssignedShare = float64(r.Assigned) / float64(r.Orders)
+	}
+	if working > 0 {
+		r.Utilisation = float64(busy) / float64(working)
+	}
+	return r
+}
+
+// score returns score of courier with provided groups of orders.
+func score(courier *model.CourierDTO, assigned *model.CourierGroupOrders, ids *collections.Set[int64]) Courier {
+	c := Courier{CourierID: courier.CourierID}
+	for _, h := range courier.WorkingHours {
+		c.WorkingMinutes += h.End().Sub(h.Start())
+	}
+
+	if assigned != nil {
+		for _, g := range assigned.Orders {
+			c.Groups++
+			if g.DeliveryWindow != nil {
+				c.BusyMinutes += g.DeliveryWindow.End().Sub(g.DeliveryWindow.Start())
+			}
+			for i, order := range g.Orders {
+				if ids.Contain(order.OrderID) {
+					c.Orders++
+				}
+				if i > 0 && g.Orders[i-1].Regions != order.Regions {
+					c.RegionSwitches++
+				}
+			}
+		}
+	}
+
+	if c.IdleMinutes = c.WorkingMinutes - c.BusyMinutes; c.IdleMinutes < 0 {
+		c.IdleMinutes = 0
+	}
+	if c.WorkingMinutes > 0 {
+		c.Utilisation = float64(c.BusyMinutes) / float64(c.WorkingMinutes)
+	}
+	return c
+}
+
+// cost returns cost of delivery of all groups of courier.
+func cost(assigned *model.CourierGroupOrders) float64 {
+	if assigned == nil {
+		return 0
+	}
+	var res float64
+	for i := range assigned.Orders {
+		res += assigned.Orders[i].Cost()
+	}
+	return res
+}
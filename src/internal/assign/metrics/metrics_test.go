@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestScore_Empty(t *testing.T) {
+	assert.Equal(t, Report{Couriers: []Courier{}}, Score(nil, nil, nil))
+}
+
+func TestScore(t *testing.T) {
+	couriers := []model.CourierDTO{
+		{CourierID: 1, WorkingHours: []*datetime.TimeInterval{
+			datetime.TimeIntervalAlias{Start: 600, End: 660}.TimeInterval(),
+			datetime.TimeIntervalAlias{Start: 700, End: 740}.TimeInterval(),
+		}},
+		{CourierID: 2, WorkingHours: []*datetime.TimeInterval{
+			datetime.TimeIntervalAlias{Start: 600, End: 700}.TimeInterval(),
+		}},
+	}
+	orders := []*model.OrderDTO{
+		{OrderID: 1}, {OrderID: 2}, {OrderID: 3}, {OrderID: 4},
+	}
+	resp := &model.OrderAssignResponse{
+		Couriers: []model.CourierGroupOrders{
+			{
+				CourierID: 1,
+				Orders: []model.GroupOrders{
+					{
+						DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 625}.TimeInterval(),
+						Orders: []model.OrderDTO{
+							{OrderID: 1, Regions: 1, Cost: 100},
+							{OrderID: 2, Regions: 2, Cost: 100},
+							{OrderID: 3, Regions: 1, Cost: 100},
+						},
+					},
+					{
+						DeliveryWindow: datetime.TimeIntervalAlias{Start: 700, End: 715}.TimeInterval(),
+						Orders:         []model.OrderDTO{{OrderID: 10, Regions: 3, Cost: 50}},
+					},
+				},
+			},
+			{
+				// courier which is not provided is ignored.
+				CourierID: 3,
+				Orders:    []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 4, Cost: 100}}}},
+			},
+		},
+	}
+
+	assert.Equal(t, Report{
+		Orders:         4,
+		Assigned:       3,
+		AssignedShare:  0.75,
+		TotalCost:      310,
+		IdleMinutes:    160,
+		RegionSwitches: 2,
+		Utilisation:    0.2,
+		Couriers: []Courier{
+			{
+				CourierID:      1,
+				Groups:         2,
+				Orders:         3,
+				WorkingMinutes: 100,
+				BusyMinutes:    40,
+				IdleMinutes:    60,
+				RegionSwitches: 2,
+				Utilisation:    0.4,
+			},
+			{CourierID: 2, WorkingMinutes: 100, IdleMinutes: 100},
+		},
+	}, Score(resp, couriers, orders))
+}
@@ -0,0 +1,203 @@
+package assign
+
+import (
+	"context"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+)
+
+// DefaultMaxIterations is default limit of improvement passes of Optimal strategy.
+const DefaultMaxIterations = 100
+
+// Optimal is local-search assignment strategy.
+//
+// It starts from greedy solution and then improves it: orders which were left unassigned are inserted into free
+// capacity of existing groups or into new groups, and orders are moved between groups while it strictly decreases
+// total cost of delivery. Result is local optimum, it is never worse than greedy solution on the same data but is
+// not guaranteed to be globally optimal.
+//
+// Search stops when context is done, then the best solution found so far is returned. Every step of search keeps
+// solution valid, so it is never worse than greedy one.
+type Optimal struct {
+	// MaxIterations limits count of improvement passes. DefaultMaxIterations is used if it is not positive.
+	MaxIterations int
+}
+
+// Assign distributes orders between couriers.
+func (o Optimal) Assign(ctx context.Context, date string, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse {
+	s := newSolution(date, couriers, orders)
+	s.greedy()
+	o.improve(ctx, s)
+	return s.response()
+}
+
+// improve runs improvement passes until solution stops changing, limit of iterations is reached or ctx is done.
+func (o Optimal) improve(ctx context.Context, s *solution) {
+	iterations := o.MaxIterations
+	if iterations <= 0 {
+		iterations = DefaultMaxIterations
+	}
+	for i := 0; i < iterations && ctx.Err() == nil; i++ {
+		inserted := s.insertPending(ctx)
+		relocated := s.relocate(ctx)
+		if !inserted && !relocated {
+			break
+		}
+	}
+}
+
+// insertPending tries to assign every pending order.
+//
+// Existing groups are preferred because next order in group is cheaper than first one. Orders are only appended to
+// the end of groups with fixed orders.
+func (s *solution) insertPending(ctx context.Context) (changed bool) {
+	for _, order := range append([]*model.OrderDTO(nil), s.pending...) {
+		if ctx.Err() != nil {
+			return changed
+		}
+		if s.insert(order) {
+			s.pending = without(s.pending, order)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// insert puts order into existing group or creates new group for it.
+func (s *solution) insert(order *model.OrderDTO) bool {
+	for _, c := range s.couriers {
+		if !c.canServe(order) {
+			continue
+		}
+		for _, g := range c.groups {
+			if g.fixed > 0 {
+				if place := g.extend(c, order); place != nil {
+					c.append(g, order, place)
+					return true
+				}
+				continue
+			}
+			orders := sequence(append(g.orders[:len(g.orders):len(g.orders)], order))
+			if !c.fitsLimits(orders) {
+				continue
+			}
+			if c.replace(g, orders) {
+				return true
+			}
+		}
+	}
+	for _, c := range s.couriers {
+		if !c.canServe(order) {
+			continue
+		}
+		if place := c.schedule([]*model.OrderDTO{order}); place != nil {
+			g := newGroup()
+			g.add(order, place)
+			c.reserve(g)
+			return true
+		}
+	}
+	return false
+}
+
+// relocate moves orders between groups while total cost strictly decreases.
+//
+// Whole groups are merged first, then single orders are moved. Groups with fixed orders are never touched.
+func (s *solution) relocate(ctx context.Context) (changed bool) {
+	for _, from := range s.couriers {
+		for i := 0; i < len(from.groups); i++ {
+			if ctx.Err() != nil {
+				return changed
+			}
+			src := from.groups[i]
+			if s.relocateGroup(from, src) {
+				changed = true
+				// groups of courier may be reordered or removed, so start from the beginning.
+				i = -1
+			}
+		}
+	}
+	return changed
+}
+
+// relocateGroup tries to merge src into another group or to move one of its orders.
+func (s *solution) relocateGroup(from *courierState, src *group) bool {
+	if src.fixed > 0 {
+		return false
+	}
+	candidates := [][]*model.OrderDTO{src.orders}
+	if len(src.orders) > 1 {
+		for _, order := range src.orders {
+			candidates = append(candidates, []*model.OrderDTO{order})
+		}
+	}
+	for _, orders := range candidates {
+		for _, to := range s.couriers {
+			for _, dst := range to.groups {
+				if dst != src && dst.fixed == 0 && move(from, src, to, dst, orders) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// move moves orders from src group of courier from into dst group of courier to.
+//
+// Orders are moved only if it decreases cost of delivery and both groups can be scheduled after it. Otherwise state
+// stays untouched and false is returned.
+func move(from *courierState, src *group, to *courierState, dst *group, orders []*model.OrderDTO) bool {
+	moved := sequence(append(dst.orders[:len(dst.orders):len(dst.orders)], orders...))
+	rest := sequence(without(append([]*model.OrderDTO(nil), src.orders...), orders...))
+	if cost(moved)+cost(rest) >= src.cost()+dst.cost() || !to.fitsLimits(moved) {
+		return false
+	}
+
+	from.release(src.place.window)
+	to.release(dst.place.window)
+	restore := func() {
+		from.occupy(src.place.window)
+		to.occupy(dst.place.window)
+	}
+
+	movedPlace := to.schedule(moved)
+	if movedPlace == nil {
+		restore()
+		return false
+	}
+	to.occupy(movedPlace.window)
+
+	var restPlace *placement
+	if len(rest) > 0 {
+		if restPlace = from.schedule(rest); restPlace == nil {
+			to.release(movedPlace.window)
+			restore()
+			return false
+		}
+		from.occupy(restPlace.window)
+	}
+
+	dst.set(moved, movedPlace)
+	to.sortGroups()
+	if restPlace == nil {
+		from.drop(src)
+		return true
+	}
+	src.set(rest, restPlace)
+	from.sortGroups()
+	return true
+}
+
+// replace sets orders of group if they can be scheduled instead of current ones.
+func (c *courierState) replace(g *group, orders []*model.OrderDTO) bool {
+	c.release(g.place.window)
+	place := c.schedule(orders)
+	if place == nil {
+		c.occupy(g.place.window)
+		return false
+	}
+	c.occupy(place.window)
+	g.set(orders, place)
+	c.sortGroups()
+	return true
+}
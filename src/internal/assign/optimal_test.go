@@ -0,0 +1,109 @@
+package assign
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestOptimal_Empty(t *testing.T) {
+	resp := Optimal{}.Assign(context.Background(), "2023-01-01", nil, nil)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, "2023-01-01", resp.Date)
+		assert.NotNil(t, resp.Couriers)
+		assert.Empty(t, resp.Couriers)
+		assert.Zero(t, resp.TotalCost)
+	}
+}
+
+func TestOptimal_MergesGroups(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "00:00-23:59", 1),
+		testCourier(t, 2, model.BikeCourierTypeString, "00:00-23:59", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:00-23:59"),
+		testOrder(t, 2, 1, 1, "00:00-23:59"),
+		testOrder(t, 3, 1, 1, "00:00-23:59"),
+		testOrder(t, 4, 1, 1, "00:00-23:59"),
+	}
+
+	greedy := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 2}}, 2: {{3, 4}}}, assignedIDs(greedy))
+	assert.Equal(t, float64(360), greedy.TotalCost)
+
+	resp := Optimal{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Equal(t, map[int64][][]int64{2: {{3, 4, 1, 2}}}, assignedIDs(resp))
+	assert.Equal(t, float64(340), resp.TotalCost)
+	assert.Equal(t, resp.Cost(), resp.TotalCost)
+}
+
+func TestOptimal_ContextDone(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "00:00-23:59", 1),
+		testCourier(t, 2, model.BikeCourierTypeString, "00:00-23:59", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:00-23:59"),
+		testOrder(t, 2, 1, 1, "00:00-23:59"),
+		testOrder(t, 3, 1, 1, "00:00-23:59"),
+		testOrder(t, 4, 1, 1, "00:00-23:59"),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	greedy := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	resp := Optimal{}.Assign(ctx, "2023-01-01", couriers, orders)
+	assert.Equal(t, assignedIDs(greedy), assignedIDs(resp))
+	assert.Equal(t, greedy.TotalCost, resp.TotalCost)
+
+	resp = Optimal{}.Extend(ctx, &model.OrderAssignResponse{Date: "2023-01-01"}, couriers, orders)
+	assert.Equal(t, assignedIDs(greedy), assignedIDs(resp))
+}
+
+func TestOptimal_NotWorseThanGreedy(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+		testCourier(t, 2, model.BikeCourierTypeString, "09:00-11:00", 1, 2),
+		testCourier(t, 3, model.AutoCourierTypeString, "11:00-13:00", 2, 3),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 5, 1, "10:00-11:00"),
+		testOrder(t, 2, 15, 2, "09:00-12:00"),
+		testOrder(t, 3, 3, 1, "10:30-11:30"),
+		testOrder(t, 4, 25, 3, "11:00-13:00"),
+		testOrder(t, 5, 1, 2, "12:00-13:00"),
+		testOrder(t, 6, 8, 1, "09:00-10:00"),
+		testOrder(t, 7, 2, 3, "12:30-13:00"),
+	}
+
+	greedy := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	resp := Optimal{MaxIterations: 10}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.GreaterOrEqual(t, countOrders(resp), countOrders(greedy))
+	if countOrders(resp) == countOrders(greedy) {
+		assert.LessOrEqual(t, resp.TotalCost, greedy.TotalCost)
+	}
+}
+
+func TestOptimal_InsertsPending(t *testing.T) {
+	s := newSolution("2023-01-01", []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}, []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "10:00-12:00"),
+		testOrder(t, 2, 1, 1, "10:00-12:00"),
+	})
+	assert.True(t, s.insertPending(context.Background()))
+	assert.Empty(t, s.pending)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 2}}}, assignedIDs(s.response()))
+	assert.False(t, s.insertPending(context.Background()))
+}
+
+func countOrders(resp *model.OrderAssignResponse) (res int) {
+	for _, c := range resp.Couriers {
+		for _, g := range c.Orders {
+			res += len(g.Orders)
+		}
+	}
+	return res
+}
@@ -0,0 +1,184 @@
+package assign
+
+import (
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+)
+
+// window is part of courier's working interval.
+//
+// Start and end are offsets in minutes from start of working interval with index slot.
+type window struct {
+	slot  int
+	start int
+	end   int
+}
+
+// placement is position of group of orders in courier's working hours.
+type placement struct {
+	window
+	// offsets are minutes from start of window to delivery of each order of group.
+	offsets []int
+	// from is start of working interval which window belongs to.
+	from datetime.Minute
+}
+
+func (w window) before(other window) bool {
+	if w.slot != other.slot {
+		return w.slot < other.slot
+	}
+	return w.start < other.start
+}
+
+func (w window) overlaps(slot, start, end int) bool {
+	return w.slot == slot && start < w.end && w.start < end
+}
+
+// Start returns time at which courier starts delivery of group.
+func (p *placement) Start() datetime.Minute {
+	return p.from.Add(p.start)
+}
+
+// Interval returns time interval in which courier delivers group.
+func (p *placement) Interval() *datetime.TimeInterval {
+	start, end := p.from.Add(p.start), p.from.Add(p.end)
+	return datetime.TimeIntervalAlias{
+		Start:   int32(start),
+		End:     int32(end),
+		Reverse: end < start,
+	}.TimeInterval()
+}
+
+// DeliveryTime returns time of delivery of i-th order of group.
+func (p *placement) DeliveryTime(i int) datetime.Minute {
+	return p.Start().Add(p.offsets[i])
+}
+
+// sequence returns orders in order of delivery.
+//
+// Orders of one region are delivered one after another, regions are visited in order they were added to group.
+// Inside of region orders with earlier delivery hours are delivered first.
+func sequence(orders []*model.OrderDTO) []*model.OrderDTO {
+	rank := make(map[int32]int)
+	for _, order := range orders {
+		if _, ok := rank[order.Regions]; !ok {
+			rank[order.Regions] = len(rank)
+		}
+	}
+	res := make([]*model.OrderDTO, len(orders))
+	copy(res, orders)
+	sort.SliceStable(res, func(i, j int) bool {
+		if rank[res[i].Regions] != rank[res[j].Regions] {
+			return rank[res[i].Regions] < rank[res[j].Regions]
+		}
+		return earliest(res[i]) < earliest(res[j])
+	})
+	return res
+}
+
+// earliest returns the earliest start of delivery hours of order.
+func earliest(order *model.OrderDTO) datetime.Minute {
+	res := datetime.Minute(-1)
+	for _, h := range order.DeliveryHours {
+		if h != nil && (res < 0 || h.Start() < res) {
+			res = h.Start()
+		}
+	}
+	return res
+}
+
+// offsets returns minutes from start of group to delivery of each order and total duration of group.
+//
+// Orders must be already sequenced. First order in each region takes more time than next ones.
+func (c *courierState) offsets(orders []*model.OrderDTO) (res []int, duration int) {
+	res = make([]int, 0, len(orders))
+	for i, order := range orders {
+		if i == 0 || orders[i-1].Regions != order.Regions {
+			duration += c.dto.FirstDeliveryMinutes()
+		} else {
+			duration += c.dto.NextDeliveryMinutes()
+		}
+		res = append(res, duration)
+	}
+	return res, duration
+}
+
+// schedule finds the earliest placement of sequenced orders in free working time of courier.
+//
+// Group must fully fit into one working interval and delivery time of every order must be inside its delivery hours.
+// If there is no such placement then nil will be returned.
+func (c *courierState) schedule(orders []*model.OrderDTO) *placement {
+	offsets, duration := c.offsets(orders)
+	for slot, h := range c.hours {
+		span := h.End().Sub(h.Start())
+		if duration > span {
+			continue
+		}
+		for _, start := range c.candidates(slot, orders, offsets) {
+			if start+duration > span || !c.free(slot, start, start+duration) {
+				continue
+			}
+			if delivered(h.Start().Add(start), orders, offsets) {
+				return &placement{
+					window:  window{slot: slot, start: start, end: start + duration},
+					offsets: offsets,
+					from:    h.Start(),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// candidates returns sorted offsets from start of working interval at which group may start.
+//
+// The earliest feasible start always begins either at start of interval, at the end of busy window or at the moment
+// when some order reaches start of its delivery hours, so only these offsets must be checked.
+func (c *courierState) candidates(slot int, orders []*model.OrderDTO, offsets []int) []int {
+	from := c.hours[slot].Start()
+	res := []int{0}
+	for _, w := range c.busy {
+		if w.slot == slot {
+			res = append(res, w.end)
+		}
+	}
+	for i, order := range orders {
+		for _, h := range order.DeliveryHours {
+			if h != nil {
+				res = append(res, h.Start().Add(-offsets[i]).Sub(from))
+			}
+		}
+	}
+	sort.Ints(res)
+	return res
+}
+
+// free return is window of working interval not busy by other groups.
+func (c *courierState) free(slot, start, end int) bool {
+	for _, w := range c.busy {
+		if w.overlaps(slot, start, end) {
+			return false
+		}
+	}
+	return true
+}
+
+// delivered return are all orders delivered inside of their delivery hours if group starts at provided time.
+func delivered(start datetime.Minute, orders []*model.OrderDTO, offsets []int) bool {
+	for i, order := range orders {
+		if !deliveredAt(order, start.Add(offsets[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+func deliveredAt(order *model.OrderDTO, t datetime.Minute) bool {
+	for _, h := range order.DeliveryHours {
+		if h != nil && t.In(h) {
+			return true
+		}
+	}
+	return false
+}
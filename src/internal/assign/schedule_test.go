@@ -0,0 +1,105 @@
+package assign
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+// windows returns delivery windows and delivery times of orders of courier.
+func windows(resp *model.OrderAssignResponse, courierID int64) (res []string, times []string) {
+	for _, c := range resp.Couriers {
+		if c.CourierID != courierID {
+			continue
+		}
+		for _, g := range c.Orders {
+			res = append(res, g.DeliveryWindow.String())
+			for _, o := range g.Orders {
+				times = append(times, o.DeliveryTime.String())
+			}
+		}
+	}
+	return res, times
+}
+
+func TestGreedy_Schedule_WorkingHours(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}
+	var orders []*model.OrderDTO
+	for i := 1; i <= 8; i++ {
+		orders = append(orders, testOrder(t, int64(i), 1, 1, "10:00-12:00"))
+	}
+
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	got, times := windows(resp, 1)
+	assert.Equal(t, []string{"10:00-10:35", "10:35-11:10", "11:10-11:45"}, got)
+	assert.Equal(t, []string{"10:25", "10:35", "11:00", "11:10", "11:35", "11:45"}, times)
+}
+
+func TestGreedy_Schedule_DeliveryHours(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-12:00", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "11:00-11:05"),
+		testOrder(t, 2, 1, 1, "11:55-13:00"),
+	}
+
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	got, times := windows(resp, 1)
+	assert.Equal(t, []string{"10:35-11:00", "11:30-11:55"}, got)
+	assert.Equal(t, []string{"11:00", "11:55"}, times)
+}
+
+func TestGreedy_Schedule_Regions(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.BikeCourierTypeString, "10:00-12:00", 1, 2),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "10:00-12:00"),
+		testOrder(t, 2, 1, 2, "10:00-12:00"),
+		testOrder(t, 3, 1, 1, "10:00-12:00"),
+	}
+
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	got, times := windows(resp, 1)
+	assert.Equal(t, []string{"10:00-10:32"}, got)
+	assert.Equal(t, []string{"10:12", "10:20", "10:32"}, times)
+	assert.Equal(t, map[int64][][]int64{1: {{1, 3, 2}}}, assignedIDs(resp))
+}
+
+func TestGreedy_Schedule_ReversedHours(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.AutoCourierTypeString, "23:00-01:00", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "00:10-00:30"),
+	}
+
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	got, times := windows(resp, 1)
+	assert.Equal(t, []string{"00:02-00:10"}, got)
+	assert.Equal(t, []string{"00:10"}, times)
+}
+
+func TestGreedy_Schedule_NoTime(t *testing.T) {
+	couriers := []model.CourierDTO{
+		testCourier(t, 1, model.FootCourierTypeString, "10:00-10:20", 1),
+	}
+	orders := []*model.OrderDTO{
+		testOrder(t, 1, 1, 1, "10:00-12:00"),
+	}
+
+	resp := Greedy{}.Assign(context.Background(), "2023-01-01", couriers, orders)
+	assert.Empty(t, resp.Couriers)
+}
+
+func TestCourierState_offsets(t *testing.T) {
+	c := newCourierState(&model.CourierDTO{CourierType: model.AutoCourierTypeString})
+	orders := []*model.OrderDTO{{Regions: 1}, {Regions: 1}, {Regions: 2}, {Regions: 2}, {Regions: 3}}
+	offsets, duration := c.offsets(orders)
+	assert.Equal(t, []int{8, 12, 20, 24, 32}, offsets)
+	assert.Equal(t, 32, duration)
+}
@@ -0,0 +1,177 @@
+package assign
+
+import (
+	"context"
+	"errors"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/collections"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+)
+
+// Strategy names which are available to choose.
+const (
+	GreedyStrategy  = "greedy"
+	OptimalStrategy = "optimal"
+)
+
+// ErrUnknownStrategy is returned when there is no assigner with name of strategy.
+var ErrUnknownStrategy = errors.New("unknown assignment strategy")
+
+// Assigner distributes orders between couriers.
+//
+// Orders that can not be delivered by any courier must be reported as unassigned. Implementations must fill
+// total cost of delivery in response. When ctx is done implementations must stop search and return the best valid
+// assignment found so far instead of an error.
+//
+// Extend must keep groups of existing assignment and orders in them, new orders may only be appended to the end of
+// existing groups or put into new ones.
+type Assigner interface {
+	Assign(ctx context.Context, date string, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse
+	Extend(ctx context.Context, existing *model.OrderAssignResponse, couriers []model.CourierDTO, orders []*model.OrderDTO) *model.OrderAssignResponse
+}
+
+// Strategies are assigners by names of their strategies.
+var Strategies = map[string]Assigner{
+	GreedyStrategy:  Greedy{},
+	OptimalStrategy: Optimal{},
+}
+
+// solution is state of assignment which strategies are working on.
+type solution struct {
+	date     string
+	couriers []*courierState
+	// pending are orders that are not assigned yet sorted by weight descending.
+	pending []*model.OrderDTO
+}
+
+func newSolution(date string, couriers []model.CourierDTO, orders []*model.OrderDTO) *solution {
+	s := &solution{
+		date:     date,
+		couriers: make([]*courierState, 0, len(couriers)),
+		pending:  make([]*model.OrderDTO, 0, len(orders)),
+	}
+	for _, order := range orders {
+		if order != nil {
+			s.pending = append(s.pending, order)
+		}
+	}
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		return s.pending[i].Weight > s.pending[j].Weight
+	})
+
+	for i := range couriers {
+		s.couriers = append(s.couriers, newCourierState(&couriers[i]))
+	}
+	sort.SliceStable(s.couriers, func(i, j int) bool {
+		return s.couriers[i].dto.CourierID < s.couriers[j].dto.CourierID
+	})
+	return s
+}
+
+// cost returns summary cost of delivery of all groups.
+func (s *solution) cost() (res float64) {
+	for _, c := range s.couriers {
+		for _, g := range c.groups {
+			res += g.cost()
+		}
+	}
+	return res
+}
+
+// without returns orders from pending that are not presented in taken.
+func without(pending []*model.OrderDTO, taken ...*model.OrderDTO) []*model.OrderDTO {
+	set := collections.NewSet[*model.OrderDTO](taken...)
+	res := pending[:0]
+	for _, order := range pending {
+		if !set.Contain(order) {
+			res = append(res, order)
+		}
+	}
+	return res
+}
+
+// reason explains why order was not assigned.
+//
+// Reasons are checked from the most fundamental one: order that can not be delivered by any courier type at all is
+// overweight even if nobody works in its region.
+func (s *solution) reason(order *model.OrderDTO) string {
+	if order.Weight > model.AutoCourierMaxWeight {
+		return model.UnassignedReasonOverweight
+	}
+	var inRegion, carry bool
+	for _, c := range s.couriers {
+		if !c.regions.Contain(order.Regions) {
+			continue
+		}
+		inRegion = true
+		if order.Weight > c.dto.MaxWeight() {
+			continue
+		}
+		carry = true
+		if hoursOverlap(c.hours, order.DeliveryHours) {
+			return model.UnassignedReasonCapacityExhausted
+		}
+	}
+	switch {
+	case !inRegion:
+		return model.UnassignedReasonNoRegion
+	case !carry:
+		return model.UnassignedReasonOverweight
+	}
+	return model.UnassignedReasonNoHoursOverlap
+}
+
+// response converts solution into response.
+//
+// Only couriers with at least one group of orders are included. Groups of courier are sorted by time of delivery.
+// Orders which are still pending are reported as unassigned with reason.
+func (s *solution) response() *model.OrderAssignResponse {
+	resp := &model.OrderAssignResponse{
+		Date:     s.date,
+		Couriers: []model.CourierGroupOrders{},
+	}
+	for _, c := range s.couriers {
+		if len(c.groups) == 0 {
+			continue
+		}
+		courier := model.CourierGroupOrders{
+			CourierID: c.dto.CourierID,
+			Orders:    make([]model.GroupOrders, 0, len(c.groups)),
+		}
+		for _, g := range c.groups {
+			courier.Orders = append(courier.Orders, g.response())
+		}
+		resp.Couriers = append(resp.Couriers, courier)
+	}
+	resp.TotalCost = s.cost()
+	for _, order := range s.pending {
+		resp.Unassigned = append(resp.Unassigned, model.UnassignedOrder{
+			OrderID: order.OrderID,
+			Reason:  s.reason(order),
+		})
+	}
+	sort.Slice(resp.Unassigned, func(i, j int) bool {
+		return resp.Unassigned[i].OrderID < resp.Unassigned[j].OrderID
+	})
+	return resp
+}
+
+// response converts group into response with delivery window and delivery time of each order.
+func (g *group) response() model.GroupOrders {
+	if g.frozen != nil {
+		return *g.frozen
+	}
+	res := model.GroupOrders{
+		GroupOrderID:   g.id,
+		DeliveryWindow: g.place.Interval(),
+		Orders:         make([]model.OrderDTO, 0, len(g.orders)),
+	}
+	for i, order := range g.orders {
+		o := *order
+		t := g.place.DeliveryTime(i)
+		o.DeliveryTime = &t
+		o.UnassignedReason = ""
+		res.Orders = append(res.Orders, o)
+	}
+	return res
+}
@@ -0,0 +1,227 @@
+package assign
+
+import (
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/collections"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+)
+
+// courierState stores groups that were already given to courier and time which is busy by them.
+type courierState struct {
+	dto     *model.CourierDTO
+	regions *collections.Set[int32]
+	// hours are working hours of courier sorted by start.
+	hours []*datetime.TimeInterval
+	// busy are windows of working hours which are already taken by groups.
+	busy   []window
+	groups []*group
+}
+
+// group is group of orders which courier delivers at once.
+type group struct {
+	orders  []*model.OrderDTO
+	weight  float64
+	regions *collections.Set[int32]
+	place   *placement
+	// id is id of stored group, it is zero for new groups.
+	id int64
+	// fixed is count of first orders of group which were already assigned and must not be moved.
+	fixed int
+	// frozen is stored group which can not be placed into working hours of courier, it is returned as is.
+	frozen *model.GroupOrders
+}
+
+func newCourierState(courier *model.CourierDTO) *courierState {
+	c := &courierState{
+		dto:     courier,
+		regions: collections.NewSet[int32](courier.Regions...),
+	}
+	for _, h := range courier.WorkingHours {
+		if h != nil {
+			c.hours = append(c.hours, h)
+		}
+	}
+	sort.SliceStable(c.hours, func(i, j int) bool {
+		return c.hours[i].Start() < c.hours[j].Start()
+	})
+	return c
+}
+
+func newGroup() *group {
+	return &group{
+		regions: collections.NewSet[int32](),
+	}
+}
+
+// canServe return can courier deliver order at all.
+//
+// Courier must work in region of order, be able to carry it and working hours of courier must overlap with
+// delivery hours of order.
+func (c *courierState) canServe(order *model.OrderDTO) bool {
+	if order == nil || !c.regions.Contain(order.Regions) || order.Weight > c.dto.MaxWeight() {
+		return false
+	}
+	return hoursOverlap(c.hours, order.DeliveryHours)
+}
+
+// fits return can order be added to group without exceeding courier limits.
+//
+// If order fits then placement of group with this order will be returned.
+func (g *group) fits(c *courierState, order *model.OrderDTO) *placement {
+	if len(g.orders) >= c.dto.MaxOrders() || g.weight+order.Weight > c.dto.MaxWeight() {
+		return nil
+	}
+	if !g.regions.Contain(order.Regions) && g.regions.Len() >= c.dto.MaxRegions() {
+		return nil
+	}
+	return c.schedule(sequence(append(g.orders[:len(g.orders):len(g.orders)], order)))
+}
+
+func (g *group) add(order *model.OrderDTO, place *placement) {
+	g.set(append(g.orders, order), place)
+}
+
+// set replaces orders of group and its placement.
+func (g *group) set(orders []*model.OrderDTO, place *placement) {
+	g.orders = sequence(orders)
+	g.weight = 0
+	g.regions = collections.NewSet[int32]()
+	for _, order := range g.orders {
+		g.weight += order.Weight
+		g.regions.Add(order.Regions)
+	}
+	g.place = place
+}
+
+// cost returns cost of delivery of group.
+func (g *group) cost() float64 {
+	return cost(g.orders)
+}
+
+// cost returns cost of delivery of sequenced orders in one group.
+func cost(orders []*model.OrderDTO) float64 {
+	costs := make([]int32, 0, len(orders))
+	for _, order := range orders {
+		costs = append(costs, order.Cost)
+	}
+	return model.GroupCost(costs...)
+}
+
+// fitsLimits return can courier deliver all orders in one group without exceeding limits of courier type.
+func (c *courierState) fitsLimits(orders []*model.OrderDTO) bool {
+	if len(orders) > c.dto.MaxOrders() {
+		return false
+	}
+	var weight float64
+	regions := collections.NewSet[int32]()
+	for _, order := range orders {
+		if !c.canServe(order) {
+			return false
+		}
+		weight += order.Weight
+		regions.Add(order.Regions)
+	}
+	return weight <= c.dto.MaxWeight() && regions.Len() <= c.dto.MaxRegions()
+}
+
+// next returns best order from pending to add into group with its placement.
+//
+// Orders in regions which group already visits are preferred. If there is no suitable order then nil will be returned.
+func (g *group) next(c *courierState, pending []*model.OrderDTO, taken map[*model.OrderDTO]bool) (*model.OrderDTO, *placement) {
+	for _, sameRegion := range []bool{true, false} {
+		for _, order := range pending {
+			if taken[order] || g.regions.Contain(order.Regions) != sameRegion || !c.canServe(order) {
+				continue
+			}
+			if place := g.fits(c, order); place != nil {
+				return order, place
+			}
+		}
+	}
+	return nil, nil
+}
+
+// collect builds next group of courier from pending orders and reserves time for it.
+//
+// If courier can not deliver any of pending orders then nil will be returned.
+func (c *courierState) collect(pending []*model.OrderDTO) *group {
+	g := newGroup()
+	taken := make(map[*model.OrderDTO]bool)
+	for order, place := g.next(c, pending, taken); order != nil; order, place = g.next(c, pending, taken) {
+		g.add(order, place)
+		taken[order] = true
+	}
+	if len(g.orders) == 0 {
+		return nil
+	}
+	c.reserve(g)
+	return g
+}
+
+// reserve marks time of group as busy and stores group in courier's state.
+func (c *courierState) reserve(g *group) {
+	c.occupy(g.place.window)
+	c.groups = append(c.groups, g)
+	c.sortGroups()
+}
+
+// remove deletes group from courier's state and frees its time.
+func (c *courierState) remove(g *group) {
+	c.release(g.place.window)
+	c.drop(g)
+}
+
+// drop deletes group from courier's state without touching busy time.
+func (c *courierState) drop(g *group) {
+	for i := range c.groups {
+		if c.groups[i] == g {
+			c.groups = append(c.groups[:i], c.groups[i+1:]...)
+			return
+		}
+	}
+}
+
+// occupy marks window as busy.
+func (c *courierState) occupy(w window) {
+	c.busy = append(c.busy, w)
+	sort.SliceStable(c.busy, func(i, j int) bool {
+		return c.busy[i].before(c.busy[j])
+	})
+}
+
+// release marks window as free.
+func (c *courierState) release(w window) {
+	for i := range c.busy {
+		if c.busy[i] == w {
+			c.busy = append(c.busy[:i], c.busy[i+1:]...)
+			return
+		}
+	}
+}
+
+// sortGroups sorts groups by time of delivery, frozen groups go first.
+func (c *courierState) sortGroups() {
+	sort.SliceStable(c.groups, func(i, j int) bool {
+		a, b := c.groups[i].place, c.groups[j].place
+		if a == nil || b == nil {
+			return a == nil && (b != nil || c.groups[i].id < c.groups[j].id)
+		}
+		return a.before(b.window)
+	})
+}
+
+// hoursOverlap return is there at least one common minute of working and delivery hours.
+func hoursOverlap(working, delivery []*datetime.TimeInterval) bool {
+	for _, w := range working {
+		for _, d := range delivery {
+			if w == nil || d == nil {
+				continue
+			}
+			if _, duration := w.Common(d); duration > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
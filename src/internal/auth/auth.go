@@ -0,0 +1,47 @@
+// Package auth contains API keys of clients and passes key which authenticated request through its context.
+//
+// Keys are random, so they are stored as SHA-256 hashes without salt: hash can not be reversed and lets storage find
+// key by index.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+)
+
+// keySize is number of random bytes in API key.
+const keySize = 32
+
+// keyCtx is key of context value with API key.
+type keyCtx struct{}
+
+// NewKey returns random API key encoded in hex.
+func NewKey() (string, error) {
+	buf := make([]byte, keySize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Hash returns hash of API key which is stored instead of key.
+func Hash(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+// WithKey returns context of request which is authenticated with key.
+func WithKey(ctx context.Context, key *model.APIKey) context.Context {
+	return context.WithValue(ctx, keyCtx{}, key)
+}
+
+// FromContext returns key which authenticated request.
+//
+// It returns false if request was not authenticated, for example because authentication is disabled.
+func FromContext(ctx context.Context) (*model.APIKey, bool) {
+	key, ok := ctx.Value(keyCtx{}).(*model.APIKey)
+	return key, ok && key != nil
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestNewKey(t *testing.T) {
+	key, err := NewKey()
+	require.NoError(t, err)
+	raw, err := hex.DecodeString(key)
+	require.NoError(t, err)
+	assert.Len(t, raw, keySize)
+
+	another, err := NewKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, another)
+}
+
+func TestHash(t *testing.T) {
+	assert.Len(t, Hash("key"), 32)
+	assert.Equal(t, Hash("key"), Hash("key"))
+	assert.NotEqual(t, Hash("key"), Hash("another key"))
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = FromContext(WithKey(context.Background(), nil))
+	assert.False(t, ok)
+
+	want := &model.APIKey{KeyID: 1, Role: model.RoleAdmin}
+	key, ok := FromContext(WithKey(context.Background(), want))
+	assert.True(t, ok)
+	assert.Equal(t, want, key)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	defaultAssignStrategy = "greedy"
+	defaultAssignBudget   = 2 * time.Second
+)
+
+// AssignConfig configures assignment of orders.
+type AssignConfig struct {
+	// Strategy is name of strategy which is used when request does not specify it.
+	Strategy string `env:"ASSIGN_STRATEGY" envDefault:"greedy"`
+	// Budget limits time of search of assignment. Not positive budget disables limit.
+	Budget time.Duration `env:"ASSIGN_BUDGET" envDefault:"2s"`
+}
+
+// NewAssignConfig initializes assignment config from environment.
+func NewAssignConfig() (*AssignConfig, error) {
+	cfg := new(AssignConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	return cfg, nil
+}
+
+// DefaultStrategy returns name of default assignment strategy.
+func (cfg *AssignConfig) DefaultStrategy() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultAssignStrategy
+	}
+	return cfg.Strategy
+}
+
+// SearchBudget returns time limit of search of assignment.
+func (cfg *AssignConfig) SearchBudget() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultAssignBudget
+	}
+	return cfg.Budget
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewAssignConfig(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		before := os.Getenv("ASSIGN_STRATEGY")
+		defer assert.NoError(t, os.Setenv("ASSIGN_STRATEGY", before))
+		assert.NoError(t, os.Setenv("ASSIGN_STRATEGY", "optimal"))
+		cfg, err := NewAssignConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, "optimal", cfg.DefaultStrategy())
+			assert.Equal(t, defaultAssignBudget, cfg.SearchBudget())
+		}
+	})
+	t.Run("default", func(t *testing.T) {
+		before := os.Getenv("ASSIGN_STRATEGY")
+		defer assert.NoError(t, os.Setenv("ASSIGN_STRATEGY", before))
+		assert.NoError(t, os.Unsetenv("ASSIGN_STRATEGY"))
+		cfg, err := NewAssignConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, defaultAssignStrategy, cfg.DefaultStrategy())
+		}
+	})
+}
+
+func TestAssignConfig_DefaultStrategy(t *testing.T) {
+	var cfg *AssignConfig
+	assert.Equal(t, defaultAssignStrategy, cfg.DefaultStrategy())
+}
+
+func TestAssignConfig_SearchBudget(t *testing.T) {
+	var cfg *AssignConfig
+	assert.Equal(t, defaultAssignBudget, cfg.SearchBudget())
+	cfg = &AssignConfig{Budget: time.Minute}
+	assert.Equal(t, time.Minute, cfg.SearchBudget())
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+)
+
+// minAdminKeyLength is minimum length of admin key from environment.
+const minAdminKeyLength = 32
+
+var ErrShortAdminKey = fmt.Errorf("admin key must be at least %d characters long", minAdminKeyLength)
+
+// AuthConfig configures authentication of requests with API keys.
+type AuthConfig struct {
+	Enabled bool `env:"AUTH_ENABLED" envDefault:"true"`
+	// Key is admin key which is used to issue the first keys, it is not stored in database.
+	Key string `env:"AUTH_ADMIN_KEY"`
+}
+
+// NewAuthConfig initializes auth config from environment.
+func NewAuthConfig() (*AuthConfig, error) {
+	cfg := new(AuthConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	if cfg.Key != "" && len(cfg.Key) < minAdminKeyLength {
+		return nil, ErrShortAdminKey
+	}
+	return cfg, nil
+}
+
+// AuthEnabled returns false if requests are not authenticated.
+func (cfg *AuthConfig) AuthEnabled() bool {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return true
+	}
+	return cfg.Enabled
+}
+
+// AdminKey returns admin key which is not stored in database.
+func (cfg *AuthConfig) AdminKey() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return ""
+	}
+	return cfg.Key
+}
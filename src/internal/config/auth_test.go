@@ -0,0 +1,46 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNewAuthConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg, err := NewAuthConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.True(t, cfg.AuthEnabled())
+			assert.Empty(t, cfg.AdminKey())
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		t.Setenv("AUTH_ENABLED", "false")
+		t.Setenv("AUTH_ADMIN_KEY", strings.Repeat("k", minAdminKeyLength))
+		cfg, err := NewAuthConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.False(t, cfg.AuthEnabled())
+			assert.Equal(t, strings.Repeat("k", minAdminKeyLength), cfg.AdminKey())
+		}
+	})
+	t.Run("short admin key", func(t *testing.T) {
+		t.Setenv("AUTH_ADMIN_KEY", "admin")
+		cfg, err := NewAuthConfig()
+		assert.ErrorIs(t, err, ErrShortAdminKey)
+		assert.Nil(t, cfg)
+	})
+	t.Run("bad enabled", func(t *testing.T) {
+		t.Setenv("AUTH_ENABLED", "sometimes")
+		cfg, err := NewAuthConfig()
+		assert.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestAuthConfig_Nil(t *testing.T) {
+	var cfg *AuthConfig
+	assert.True(t, cfg.AuthEnabled(), "nil config must not disable authentication")
+	assert.Empty(t, cfg.AdminKey())
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	// NoOutboxSink disables relay, events stay in outbox until sink is configured.
+	NoOutboxSink = "none"
+	// FileOutboxSink appends events to JSONL file.
+	FileOutboxSink = "file"
+	// WebhookOutboxSink posts events to webhook.
+	WebhookOutboxSink = "webhook"
+)
+
+const (
+	defaultOutboxPollInterval    = time.Second
+	defaultOutboxBatchSize       = 100
+	defaultOutboxFilePath        = "events.jsonl"
+	defaultOutboxWebhookAttempts = 3
+	defaultOutboxWebhookDelay    = time.Second
+	defaultOutboxWebhookTimeout  = 5 * time.Second
+)
+
+var (
+	ErrUnknownOutboxSink = errors.New("unknown outbox sink")
+	ErrNoWebhookURL      = errors.New("webhook sink requires OUTBOX_WEBHOOK_URL")
+	ErrBadOutboxBatch    = errors.New("outbox batch size must be positive")
+)
+
+// OutboxConfig configures relay of domain events from outbox.
+type OutboxConfig struct {
+	// Sink is name of destination of events: none, file or webhook.
+	Sink     string        `env:"OUTBOX_SINK" envDefault:"none"`
+	Interval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"1s"`
+	Batch    int           `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	File     string        `env:"OUTBOX_FILE" envDefault:"events.jsonl"`
+	URL      string        `env:"OUTBOX_WEBHOOK_URL"`
+	Attempts uint          `env:"OUTBOX_WEBHOOK_ATTEMPTS" envDefault:"3"`
+	Delay    time.Duration `env:"OUTBOX_WEBHOOK_DELAY" envDefault:"1s"`
+	Timeout  time.Duration `env:"OUTBOX_WEBHOOK_TIMEOUT" envDefault:"5s"`
+}
+
+// NewOutboxConfig initializes outbox config from environment.
+func NewOutboxConfig() (*OutboxConfig, error) {
+	cfg := new(OutboxConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	switch cfg.Sink {
+	case NoOutboxSink, FileOutboxSink:
+	case WebhookOutboxSink:
+		if cfg.URL == "" {
+			return nil, ErrNoWebhookURL
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOutboxSink, cfg.Sink)
+	}
+	if cfg.Batch <= 0 {
+		return nil, ErrBadOutboxBatch
+	}
+	return cfg, nil
+}
+
+// SinkName returns name of destination of events.
+func (cfg *OutboxConfig) SinkName() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return NoOutboxSink
+	}
+	return cfg.Sink
+}
+
+// PollInterval returns delay between checks of outbox.
+func (cfg *OutboxConfig) PollInterval() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxPollInterval
+	}
+	return cfg.Interval
+}
+
+// BatchSize returns maximum number of events which are sent at once.
+func (cfg *OutboxConfig) BatchSize() int {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxBatchSize
+	}
+	return cfg.Batch
+}
+
+// FilePath returns path of file which file sink appends events to.
+func (cfg *OutboxConfig) FilePath() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxFilePath
+	}
+	return cfg.File
+}
+
+// WebhookURL returns URL which webhook sink posts events to.
+func (cfg *OutboxConfig) WebhookURL() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return ""
+	}
+	return cfg.URL
+}
+
+// WebhookAttempts returns number of attempts to post batch of events.
+func (cfg *OutboxConfig) WebhookAttempts() uint {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxWebhookAttempts
+	}
+	return cfg.Attempts
+}
+
+// WebhookDelay returns delay between attempts to post batch of events.
+func (cfg *OutboxConfig) WebhookDelay() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxWebhookDelay
+	}
+	return cfg.Delay
+}
+
+// WebhookTimeout returns timeout of one attempt to post batch of events.
+func (cfg *OutboxConfig) WebhookTimeout() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultOutboxWebhookTimeout
+	}
+	return cfg.Timeout
+}
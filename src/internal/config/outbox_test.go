@@ -0,0 +1,75 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewOutboxConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg, err := NewOutboxConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, NoOutboxSink, cfg.SinkName())
+			assert.Equal(t, defaultOutboxPollInterval, cfg.PollInterval())
+			assert.Equal(t, defaultOutboxBatchSize, cfg.BatchSize())
+			assert.Equal(t, defaultOutboxFilePath, cfg.FilePath())
+			assert.Equal(t, "", cfg.WebhookURL())
+			assert.Equal(t, uint(defaultOutboxWebhookAttempts), cfg.WebhookAttempts())
+			assert.Equal(t, defaultOutboxWebhookDelay, cfg.WebhookDelay())
+			assert.Equal(t, defaultOutboxWebhookTimeout, cfg.WebhookTimeout())
+		}
+	})
+	t.Run("webhook", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", WebhookOutboxSink)
+		t.Setenv("OUTBOX_WEBHOOK_URL", "http://localhost/events")
+		t.Setenv("OUTBOX_WEBHOOK_ATTEMPTS", "5")
+		t.Setenv("OUTBOX_POLL_INTERVAL", "10s")
+		cfg, err := NewOutboxConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, WebhookOutboxSink, cfg.SinkName())
+			assert.Equal(t, "http://localhost/events", cfg.WebhookURL())
+			assert.Equal(t, uint(5), cfg.WebhookAttempts())
+			assert.Equal(t, 10*time.Second, cfg.PollInterval())
+		}
+	})
+	t.Run("webhook without url", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", WebhookOutboxSink)
+		t.Setenv("OUTBOX_WEBHOOK_URL", "")
+		cfg, err := NewOutboxConfig()
+		assert.ErrorIs(t, err, ErrNoWebhookURL)
+		assert.Nil(t, cfg)
+	})
+	t.Run("unknown sink", func(t *testing.T) {
+		t.Setenv("OUTBOX_SINK", "kafka")
+		cfg, err := NewOutboxConfig()
+		assert.ErrorIs(t, err, ErrUnknownOutboxSink)
+		assert.Nil(t, cfg)
+	})
+	t.Run("bad batch", func(t *testing.T) {
+		t.Setenv("OUTBOX_BATCH_SIZE", "0")
+		cfg, err := NewOutboxConfig()
+		assert.ErrorIs(t, err, ErrBadOutboxBatch)
+		assert.Nil(t, cfg)
+	})
+	t.Run("bad interval", func(t *testing.T) {
+		t.Setenv("OUTBOX_POLL_INTERVAL", "often")
+		cfg, err := NewOutboxConfig()
+		assert.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestOutboxConfig_Nil(t *testing.T) {
+	var cfg *OutboxConfig
+	assert.Equal(t, NoOutboxSink, cfg.SinkName())
+	assert.Equal(t, defaultOutboxPollInterval, cfg.PollInterval())
+	assert.Equal(t, defaultOutboxBatchSize, cfg.BatchSize())
+	assert.Equal(t, defaultOutboxFilePath, cfg.FilePath())
+	assert.Equal(t, "", cfg.WebhookURL())
+	assert.Equal(t, uint(defaultOutboxWebhookAttempts), cfg.WebhookAttempts())
+	assert.Equal(t, defaultOutboxWebhookDelay, cfg.WebhookDelay())
+	assert.Equal(t, defaultOutboxWebhookTimeout, cfg.WebhookTimeout())
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+)
+
+const (
+	// PostgresStoreDriver keeps data in postgres.
+	PostgresStoreDriver = "postgres"
+	// MemoryStoreDriver keeps data in memory of process, data is lost on restart.
+	MemoryStoreDriver = "memory"
+)
+
+var ErrUnknownStoreDriver = errors.New("unknown store driver")
+
+// StoreConfig selects storage of service.
+type StoreConfig struct {
+	// Driver is name of storage: postgres or memory.
+	Driver string `env:"STORE_DRIVER" envDefault:"postgres"`
+}
+
+// NewStoreConfig initializes store config from environment.
+func NewStoreConfig() (*StoreConfig, error) {
+	cfg := new(StoreConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	switch cfg.Driver {
+	case PostgresStoreDriver, MemoryStoreDriver:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStoreDriver, cfg.Driver)
+	}
+	return cfg, nil
+}
+
+// StoreDriver returns name of storage.
+func (cfg *StoreConfig) StoreDriver() string {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return PostgresStoreDriver
+	}
+	return cfg.Driver
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestNewStoreConfig(t *testing.T) {
+	tt := []struct {
+		name  string
+		value *string
+		want  string
+		err   error
+	}{
+		{"default", nil, PostgresStoreDriver, nil},
+		{"postgres", &[]string{PostgresStoreDriver}[0], PostgresStoreDriver, nil},
+		{"memory", &[]string{MemoryStoreDriver}[0], MemoryStoreDriver, nil},
+		{"unknown", &[]string{"mongo"}[0], "", ErrUnknownStoreDriver},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			before, ok := os.LookupEnv("STORE_DRIVER")
+			defer func() {
+				if ok {
+					assert.NoError(t, os.Setenv("STORE_DRIVER", before))
+				} else {
+					assert.NoError(t, os.Unsetenv("STORE_DRIVER"))
+				}
+			}()
+			if tc.value != nil {
+				assert.NoError(t, os.Setenv("STORE_DRIVER", *tc.value))
+			} else {
+				assert.NoError(t, os.Unsetenv("STORE_DRIVER"))
+			}
+
+			cfg, err := NewStoreConfig()
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err != nil {
+				assert.Nil(t, cfg)
+				return
+			}
+			assert.Equal(t, tc.want, cfg.StoreDriver())
+		})
+	}
+}
+
+func TestStoreConfig_StoreDriver(t *testing.T) {
+	var cfg *StoreConfig
+	assert.Equal(t, PostgresStoreDriver, cfg.StoreDriver())
+}
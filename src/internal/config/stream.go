@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	defaultStreamPollInterval = 500 * time.Millisecond
+	defaultStreamBatchSize    = 100
+	defaultStreamGapTimeout   = 2 * time.Second
+	defaultStreamBufferSize   = 64
+)
+
+var (
+	ErrBadStreamInterval = errors.New("events poll interval must be positive")
+	ErrBadStreamBatch    = errors.New("events batch size must be positive")
+	ErrBadStreamGap      = errors.New("events gap timeout must not be negative")
+	ErrBadStreamBuffer   = errors.New("events buffer size must be positive")
+)
+
+// StreamConfig configures hub which streams order events to subscribed clients.
+type StreamConfig struct {
+	Interval time.Duration `env:"EVENTS_POLL_INTERVAL" envDefault:"500ms"`
+	Batch    int           `env:"EVENTS_BATCH_SIZE" envDefault:"100"`
+	Gap      time.Duration `env:"EVENTS_GAP_TIMEOUT" envDefault:"2s"`
+	Buffer   int           `env:"EVENTS_BUFFER_SIZE" envDefault:"64"`
+}
+
+// NewStreamConfig initializes event stream config from environment.
+func NewStreamConfig() (*StreamConfig, error) {
+	cfg := new(StreamConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	switch {
+	case cfg.Interval <= 0:
+		return nil, ErrBadStreamInterval
+	case cfg.Batch <= 0:
+		return nil, ErrBadStreamBatch
+	case cfg.Gap < 0:
+		return nil, ErrBadStreamGap
+	case cfg.Buffer <= 0:
+		return nil, ErrBadStreamBuffer
+	}
+	return cfg, nil
+}
+
+// PollInterval returns delay between checks of new events.
+func (cfg *StreamConfig) PollInterval() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultStreamPollInterval
+	}
+	return cfg.Interval
+}
+
+// BatchSize returns maximum number of events which are read at once.
+func (cfg *StreamConfig) BatchSize() int {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultStreamBatchSize
+	}
+	return cfg.Batch
+}
+
+// GapTimeout returns how long event with missing id is waited for.
+func (cfg *StreamConfig) GapTimeout() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultStreamGapTimeout
+	}
+	return cfg.Gap
+}
+
+// BufferSize returns number of events which subscriber may not read yet before it is dropped.
+func (cfg *StreamConfig) BufferSize() int {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultStreamBufferSize
+	}
+	return cfg.Buffer
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewStreamConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg, err := NewStreamConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, defaultStreamPollInterval, cfg.PollInterval())
+			assert.Equal(t, defaultStreamBatchSize, cfg.BatchSize())
+			assert.Equal(t, defaultStreamGapTimeout, cfg.GapTimeout())
+			assert.Equal(t, defaultStreamBufferSize, cfg.BufferSize())
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		t.Setenv("EVENTS_GAP_TIMEOUT", "0s")
+		t.Setenv("EVENTS_BUFFER_SIZE", "8")
+		cfg, err := NewStreamConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, time.Duration(0), cfg.GapTimeout())
+			assert.Equal(t, 8, cfg.BufferSize())
+		}
+	})
+	for _, tc := range []struct {
+		name string
+		env  string
+		val  string
+		want error
+	}{
+		{"bad interval", "EVENTS_POLL_INTERVAL", "0s", ErrBadStreamInterval},
+		{"bad batch", "EVENTS_BATCH_SIZE", "0", ErrBadStreamBatch},
+		{"bad gap", "EVENTS_GAP_TIMEOUT", "-1s", ErrBadStreamGap},
+		{"bad buffer", "EVENTS_BUFFER_SIZE", "-1", ErrBadStreamBuffer},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, tc.val)
+			cfg, err := NewStreamConfig()
+			assert.ErrorIs(t, err, tc.want)
+			assert.Nil(t, cfg)
+		})
+	}
+	t.Run("unparsable", func(t *testing.T) {
+		t.Setenv("EVENTS_BUFFER_SIZE", "many")
+		cfg, err := NewStreamConfig()
+		assert.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestStreamConfig_Nil(t *testing.T) {
+	var cfg *StreamConfig
+	assert.Equal(t, defaultStreamPollInterval, cfg.PollInterval())
+	assert.Equal(t, defaultStreamBatchSize, cfg.BatchSize())
+	assert.Equal(t, defaultStreamGapTimeout, cfg.GapTimeout())
+	assert.Equal(t, defaultStreamBufferSize, cfg.BufferSize())
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v8"
+	"go.uber.org/zap"
+	"time"
+)
+
+const (
+	defaultWebhookPollInterval = time.Second
+	defaultWebhookBatchSize    = 50
+	defaultWebhookMaxAttempts  = 8
+	defaultWebhookBackoff      = time.Second
+	defaultWebhookMaxBackoff   = 10 * time.Minute
+	defaultWebhookTimeout      = 5 * time.Second
+)
+
+var (
+	ErrBadWebhookBatch    = errors.New("webhook batch size must be positive")
+	ErrBadWebhookAttempts = errors.New("webhook max attempts must be positive")
+	ErrBadWebhookBackoff  = errors.New("webhook backoff must be positive and not greater than max backoff")
+	ErrBadWebhookTimeout  = errors.New("webhook timeout must be positive")
+)
+
+// WebhookConfig configures dispatcher of deliveries to webhooks which are subscribed with API.
+type WebhookConfig struct {
+	Interval   time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	Batch      int           `env:"WEBHOOK_BATCH_SIZE" envDefault:"50"`
+	Attempts   int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	Base       time.Duration `env:"WEBHOOK_BACKOFF" envDefault:"1s"`
+	MaxBase    time.Duration `env:"WEBHOOK_MAX_BACKOFF" envDefault:"10m"`
+	AttemptTTL time.Duration `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
+}
+
+// NewWebhookConfig initializes webhook config from environment.
+func NewWebhookConfig() (*WebhookConfig, error) {
+	cfg := new(WebhookConfig)
+	if err := env.Parse(cfg); err != nil {
+		return nil, fmt.Errorf("env: parse: %w", err)
+	}
+	switch {
+	case cfg.Batch <= 0:
+		return nil, ErrBadWebhookBatch
+	case cfg.Attempts <= 0:
+		return nil, ErrBadWebhookAttempts
+	case cfg.Base <= 0 || cfg.Base > cfg.MaxBase:
+		return nil, ErrBadWebhookBackoff
+	case cfg.AttemptTTL <= 0:
+		return nil, ErrBadWebhookTimeout
+	}
+	return cfg, nil
+}
+
+// PollInterval returns delay between checks of due deliveries.
+func (cfg *WebhookConfig) PollInterval() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookPollInterval
+	}
+	return cfg.Interval
+}
+
+// BatchSize returns maximum number of deliveries which are posted at once.
+func (cfg *WebhookConfig) BatchSize() int {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookBatchSize
+	}
+	return cfg.Batch
+}
+
+// MaxAttempts returns number of attempts after which delivery is failed.
+func (cfg *WebhookConfig) MaxAttempts() int {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookMaxAttempts
+	}
+	return cfg.Attempts
+}
+
+// Backoff returns delay after the first failed attempt of delivery.
+func (cfg *WebhookConfig) Backoff() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookBackoff
+	}
+	return cfg.Base
+}
+
+// MaxBackoff returns maximum delay between attempts of delivery.
+func (cfg *WebhookConfig) MaxBackoff() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookMaxBackoff
+	}
+	return cfg.MaxBase
+}
+
+// Timeout returns timeout of one attempt of delivery.
+func (cfg *WebhookConfig) Timeout() time.Duration {
+	if cfg == nil {
+		zap.L().Warn("unexpectedly got nil config object")
+		return defaultWebhookTimeout
+	}
+	return cfg.AttemptTTL
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewWebhookConfig(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		cfg, err := NewWebhookConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, defaultWebhookPollInterval, cfg.PollInterval())
+			assert.Equal(t, defaultWebhookBatchSize, cfg.BatchSize())
+			assert.Equal(t, defaultWebhookMaxAttempts, cfg.MaxAttempts())
+			assert.Equal(t, defaultWebhookBackoff, cfg.Backoff())
+			assert.Equal(t, defaultWebhookMaxBackoff, cfg.MaxBackoff())
+			assert.Equal(t, defaultWebhookTimeout, cfg.Timeout())
+		}
+	})
+	t.Run("custom", func(t *testing.T) {
+		t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+		t.Setenv("WEBHOOK_BACKOFF", "100ms")
+		t.Setenv("WEBHOOK_MAX_BACKOFF", "1s")
+		cfg, err := NewWebhookConfig()
+		assert.NoError(t, err)
+		if assert.NotNil(t, cfg) {
+			assert.Equal(t, 3, cfg.MaxAttempts())
+			assert.Equal(t, 100*time.Millisecond, cfg.Backoff())
+			assert.Equal(t, time.Second, cfg.MaxBackoff())
+		}
+	})
+	for _, tc := range []struct {
+		name string
+		env  string
+		val  string
+		want error
+	}{
+		{"bad batch", "WEBHOOK_BATCH_SIZE", "0", ErrBadWebhookBatch},
+		{"bad attempts", "WEBHOOK_MAX_ATTEMPTS", "-1", ErrBadWebhookAttempts},
+		{"backoff greater than max", "WEBHOOK_BACKOFF", "1h", ErrBadWebhookBackoff},
+		{"bad timeout", "WEBHOOK_TIMEOUT", "0s", ErrBadWebhookTimeout},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.env, tc.val)
+			cfg, err := NewWebhookConfig()
+			assert.ErrorIs(t, err, tc.want)
+			assert.Nil(t, cfg)
+		})
+	}
+	t.Run("bad interval", func(t *testing.T) {
+		t.Setenv("WEBHOOK_POLL_INTERVAL", "often")
+		cfg, err := NewWebhookConfig()
+		assert.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestWebhookConfig_Nil(t *testing.T) {
+	var cfg *WebhookConfig
+	assert.Equal(t, defaultWebhookPollInterval, cfg.PollInterval())
+	assert.Equal(t, defaultWebhookBatchSize, cfg.BatchSize())
+	assert.Equal(t, defaultWebhookMaxAttempts, cfg.MaxAttempts())
+	assert.Equal(t, defaultWebhookBackoff, cfg.Backoff())
+	assert.Equal(t, defaultWebhookMaxBackoff, cfg.MaxBackoff())
+	assert.Equal(t, defaultWebhookTimeout, cfg.Timeout())
+}
@@ -1,12 +1,18 @@
 package http
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
+// sseHeartbeat is interval of comments which keep idle stream of events open through proxies.
+const sseHeartbeat = 15 * time.Second
+
 func (srv *Controller) HandlePing(c echo.Context) error {
 	return c.String(http.StatusOK, "pong")
 }
@@ -21,6 +27,7 @@ func (srv *Controller) HandlePing(c echo.Context) error {
 //	@Success	200			{object}	model.CourierDTO			"OK"
 //	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
 //	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
 //	@Router		/couriers/{courier_id} [get]
 func (srv *Controller) HandleGetCourier(c echo.Context) error {
 	id := c.Param("courier_id")
@@ -46,8 +53,10 @@ func (srv *Controller) HandleGetCourier(c echo.Context) error {
 //	@Produce	json
 //	@Param		limit	query		int							false	"Максимальное количество курьеров в выдаче. Если параметр не передан, то значение по умолчанию равно 1."
 //	@Param		offset	query		int							false	"Количество курьеров, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0."
+//	@Param		cursor	query		string						false	"Курсор страницы из поля next_cursor предыдущего ответа. Не может быть передан вместе с offset."
 //	@Success	200		{object}	model.GetCouriersResponse	"OK"
 //	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Security	ApiKeyAuth
 //	@Router		/couriers/ [get]
 func (srv *Controller) HandleGetCouriers(c echo.Context) error {
 	opts := GetPaginationOptsFromRequest(c)
@@ -74,6 +83,7 @@ func (srv *Controller) HandleGetCouriers(c echo.Context) error {
 //	@Param		request	body		model.CreateCourierRequest		true	"Couriers"
 //	@Success	200		{object}	model.CouriersCreateResponse	"OK"
 //	@Failure	400		{object}	model.BadRequestResponse		"Bad Request"
+//	@Security	ApiKeyAuth
 //	@Router		/couriers/ [post]
 func (srv *Controller) HandleCreateCouriers(c echo.Context) error {
 	var request model.CreateCourierRequest
@@ -87,6 +97,56 @@ func (srv *Controller) HandleCreateCouriers(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// HandleUpdateCourier changes provided fields of courier.
+//
+//	@Tags		courier-controller
+//	@Summary	Изменение профиля курьера
+//	@Accept		json
+//	@Produce	json
+//	@Param		courier_id	path		int							true	"Courier identifier"
+//	@Param		request		body		model.UpdateCourierRequest	true	"Fields to change"
+//	@Success	200			{object}	model.CourierDTO			"OK"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/couriers/{courier_id} [patch]
+func (srv *Controller) HandleUpdateCourier(c echo.Context) error {
+	id := c.Param("courier_id")
+
+	var request model.UpdateCourierRequest
+	if err := c.Bind(&request); err != nil {
+		return srv.checkErr(c, "err while binding request", err, zap.String("courier_id", id))
+	}
+	courier, err := srv.srv.UpdateCourier(c.Request().Context(), id, &request)
+	if err != nil {
+		return srv.checkErr(c, "err while updating courier", err, zap.String("courier_id", id))
+	}
+	return c.JSON(http.StatusOK, courier)
+}
+
+// HandleDeactivateCourier deactivates courier.
+//
+// Deactivated courier is not listed and does not get orders in next assignments.
+//
+//	@Tags		courier-controller
+//	@Summary	Деактивация курьера
+//	@Accept		json
+//	@Produce	json
+//	@Param		courier_id	path		int							true	"Courier identifier"
+//	@Success	200			{object}	model.CourierDTO			"OK"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/couriers/{courier_id} [delete]
+func (srv *Controller) HandleDeactivateCourier(c echo.Context) error {
+	id := c.Param("courier_id")
+	courier, err := srv.srv.DeactivateCourier(c.Request().Context(), id)
+	if err != nil {
+		return srv.checkErr(c, "err while deactivating courier", err, zap.String("courier_id", id))
+	}
+	return c.JSON(http.StatusOK, courier)
+}
+
 // HandleGetCourierMetaInfo return courier meta info.
 //
 //	@Tags		courier-controller
@@ -98,6 +158,7 @@ func (srv *Controller) HandleCreateCouriers(c echo.Context) error {
 //	@Param		endDate		query		string								true	"Количество курьеров, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0."
 //	@Success	200			{object}	model.GetCourierMetaInfoResponse	"OK"
 //	@Failure	400			{object}	model.BadRequestResponse			"Bad Request"
+//	@Security	ApiKeyAuth
 //	@Router		/couriers/meta-info/{courier_id} [get]
 func (srv *Controller) HandleGetCourierMetaInfo(c echo.Context) error {
 	var req model.GetCourierMetaInfoRequest
@@ -114,7 +175,9 @@ func (srv *Controller) HandleGetCourierMetaInfo(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
-// HandleGetOrdersAssign doc.
+// HandleGetOrdersAssign returns orders that were assigned at date.
+//
+// If orders were never assigned at date then 404 will be returned.
 //
 //	@Tags		courier-controller
 //	@Summary	список распределенных заказов
@@ -124,6 +187,8 @@ func (srv *Controller) HandleGetCourierMetaInfo(c echo.Context) error {
 //	@Param		date		query		string						false	"Дата распределения заказов. Если не указана, то используется текущий день"
 //	@Success	200			{object}	model.OrderAssignResponse	"OK"
 //	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
 //	@Router		/couriers/assignments [get]
 func (srv *Controller) HandleGetOrdersAssign(c echo.Context) error {
 	date, err := srv.dateFromContext(c, "date")
@@ -152,6 +217,7 @@ func (srv *Controller) HandleGetOrdersAssign(c echo.Context) error {
 //	@Success	200			{object}	model.OrderDTO				"OK"
 //	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
 //	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
 //	@Router		/orders/{order_id} [get]
 func (srv *Controller) HandleGetOrder(c echo.Context) error {
 	id := c.Param("order_id")
@@ -162,6 +228,60 @@ func (srv *Controller) HandleGetOrder(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// HandleGetOrderHistory returns history of order status.
+//
+//	@Tags		order-controller
+//	@Summary	История статусов заказа
+//	@Accept		json
+//	@Produce	json
+//	@Param		order_id	path		int							true	"Order identifier"
+//	@Success	200			{object}	model.OrderHistoryResponse	"OK"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/orders/{order_id}/history [get]
+func (srv *Controller) HandleGetOrderHistory(c echo.Context) error {
+	id := c.Param("order_id")
+	resp, err := srv.srv.GetOrderHistory(c.Request().Context(), id)
+	if err != nil {
+		return srv.checkErr(c, "err while getting order history", err, zap.String("order_id", id))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleChangeOrderStatus changes status of order.
+//
+// Courier reports with it that order is taken for delivery or that delivery failed. Courier key allows to
+// change status only of orders of its courier.
+//
+//	@Tags		order-controller
+//	@Summary	Изменение статуса заказа
+//	@Accept		json
+//	@Produce	json
+//	@Param		order_id	path		int								true	"Order identifier"
+//	@Param		request		body		model.ChangeOrderStatusRequest	true	"New status"
+//	@Success	200			{object}	model.OrderDTO					"OK"
+//	@Failure	400			{object}	model.BadRequestResponse		"Bad Request"
+//	@Failure	401			{object}	model.BadRequestResponse		"Unauthorized"
+//	@Failure	403			{object}	model.BadRequestResponse		"Forbidden"
+//	@Failure	404			{object}	model.BadRequestResponse		"Not Found"
+//	@Failure	409			{object}	model.BadRequestResponse		"Conflict"
+//	@Security	ApiKeyAuth
+//	@Router		/orders/{order_id}/status [post]
+func (srv *Controller) HandleChangeOrderStatus(c echo.Context) error {
+	id := c.Param("order_id")
+
+	var request model.ChangeOrderStatusRequest
+	if err := c.Bind(&request); err != nil {
+		return srv.checkErr(c, "err while binding request", err, zap.String("order_id", id))
+	}
+	order, err := srv.srv.ChangeOrderStatus(c.Request().Context(), id, &request)
+	if err != nil {
+		return srv.checkErr(c, "err while changing order status", err, zap.String("order_id", id))
+	}
+	return c.JSON(http.StatusOK, order)
+}
+
 // HandleGetOrders return courier with provided id.
 //
 //	@Tags		order-controller
@@ -170,8 +290,10 @@ func (srv *Controller) HandleGetOrder(c echo.Context) error {
 //	@Produce	json
 //	@Param		limit	query		int							false	"Максимальное количество заказов в выдаче. Если параметр не передан, то значение по умолчанию равно 1."
 //	@Param		offset	query		int							false	"Количество заказов, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0."
-//	@Success	200		{array}		model.OrderDTO				"OK"
+//	@Param		cursor	query		string						false	"Курсор страницы из поля next_cursor предыдущего ответа. Не может быть передан вместе с offset."
+//	@Success	200		{object}	model.GetOrdersResponse		"OK"
 //	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Security	ApiKeyAuth
 //	@Router		/orders/ [get]
 func (srv *Controller) HandleGetOrders(c echo.Context) error {
 	opts := GetPaginationOptsFromRequest(c)
@@ -191,6 +313,7 @@ func (srv *Controller) HandleGetOrders(c echo.Context) error {
 //	@Param		request	body		model.CreateOrderRequest	true	"Orders"
 //	@Success	200		{array}		model.OrderDTO				"OK"
 //	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Security	ApiKeyAuth
 //	@Router		/orders/ [post]
 func (srv *Controller) HandleCreateOrders(c echo.Context) error {
 	req := new(model.CreateOrderRequest)
@@ -206,7 +329,7 @@ func (srv *Controller) HandleCreateOrders(c echo.Context) error {
 
 // HandleCompleteOrders completes provided orders.
 //
-// This handler is idempotent.
+// This handler is idempotent. Courier key allows to complete only orders of its courier.
 //
 //	@Tags		order-controller
 //	@Summary	Завершение заказов
@@ -215,6 +338,10 @@ func (srv *Controller) HandleCreateOrders(c echo.Context) error {
 //	@Param		request	body		model.CompleteOrderRequest	true	"Orders"
 //	@Success	200		{array}		model.OrderDTO				"OK"
 //	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	401		{object}	model.BadRequestResponse	"Unauthorized"
+//	@Failure	403		{object}	model.BadRequestResponse	"Forbidden"
+//	@Failure	409		{object}	model.BadRequestResponse	"Conflict"
+//	@Security	ApiKeyAuth
 //	@Router		/orders/complete [post]
 func (srv *Controller) HandleCompleteOrders(c echo.Context) error {
 	req := new(model.CompleteOrderRequest)
@@ -228,15 +355,54 @@ func (srv *Controller) HandleCompleteOrders(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// HandleCancelOrders cancels provided orders.
+//
+// This handler is idempotent. Orders which are completed or are delivered by courier can not be cancelled.
+//
+//	@Tags		order-controller
+//	@Summary	Отмена заказов
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		model.CancelOrderRequest	true	"Orders"
+//	@Success	200		{array}		model.OrderDTO				"OK"
+//	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404		{object}	model.BadRequestResponse	"Not Found"
+//	@Failure	409		{object}	model.BadRequestResponse	"Conflict"
+//	@Security	ApiKeyAuth
+//	@Router		/orders/cancel [post]
+func (srv *Controller) HandleCancelOrders(c echo.Context) error {
+	req := new(model.CancelOrderRequest)
+	if err := c.Bind(req); err != nil {
+		return srv.checkErr(c, "error while binding request", err)
+	}
+	resp, err := srv.srv.CancelOrders(c.Request().Context(), req)
+	if err != nil {
+		return srv.checkErr(c, "error while cancelling orders", err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
 // HandleAssignOrders assigns orders.
 //
+// With dry_run=true assignment is only previewed and nothing is stored. Repeated assignment of the same date returns
+// stored result unless force=true is passed. With incremental=true stored groups are kept and only orders created
+// after previous assignment are distributed. Orders can be distributed only for today, for other dates only stored
+// result is returned.
+//
 //	@Tags		order-controller
 //	@Summary	Распределение заказов по курьерам
 //	@Accept		json
 //	@Produce	json
-//	@Param		date	query		string						false	"Дата распределения заказов. Если не указана, то используется текущий день"
-//	@Success	201		{object}	model.OrderAssignResponse	"OK"
-//	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Param		date		query		string						false	"Дата распределения заказов. Если не указана, то используется текущий день"
+//	@Param		strategy	query		string						false	"Стратегия распределения заказов. Если не указана, то используется стратегия по умолчанию"	Enums(greedy, optimal)
+//	@Param		dry_run		query		bool						false	"Только рассчитать распределение, не сохраняя его"
+//	@Param		force		query		bool						false	"Распределить заново, даже если заказы на эту дату уже распределены"
+//	@Param		incremental	query		bool						false	"Распределить только новые заказы, сохранив уже сформированные группы заказов"
+//	@Success	200			{object}	model.OrderAssignResponse	"Dry run"
+//	@Success	201			{object}	model.OrderAssignResponse	"OK"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	409			{object}	model.BadRequestResponse	"Conflict"
+//	@Security	ApiKeyAuth
 //	@Router		/orders/assign [post]
 func (srv *Controller) HandleAssignOrders(c echo.Context) error {
 	date, err := srv.dateFromContext(c, "date")
@@ -244,10 +410,255 @@ func (srv *Controller) HandleAssignOrders(c echo.Context) error {
 		return srv.checkErr(c, "error while getting date from context", err)
 	}
 
+	var opts *AssignOpts
+	opts, err = GetAssignOptsFromRequest(c)
+	if err != nil {
+		return srv.checkErr(c, "error while getting assign opts from context", err)
+	}
+
 	var resp *model.OrderAssignResponse
-	resp, err = srv.srv.AssignOrders(c.Request().Context(), date)
+	resp, err = srv.srv.AssignOrders(c.Request().Context(), date, opts)
 	if err != nil {
 		return srv.checkErr(c, "error while assigning orders", err)
 	}
+	if resp.DryRun {
+		return c.JSON(http.StatusOK, resp)
+	}
 	return c.JSON(http.StatusCreated, resp)
 }
+
+// HandleCreateWebhook subscribes callback URL to order events.
+//
+// Deliveries are signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" with secret of webhook in
+// X-Webhook-Signature header. Secret is returned only in response of this handler.
+//
+//	@Tags		webhook-controller
+//	@Summary	Подписка на события заказов
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		model.CreateWebhookRequest	true	"Webhook"
+//	@Success	201		{object}	model.WebhookDTO			"Created"
+//	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Security	ApiKeyAuth
+//	@Router		/webhooks [post]
+func (srv *Controller) HandleCreateWebhook(c echo.Context) error {
+	req := new(model.CreateWebhookRequest)
+	if err := c.Bind(req); err != nil {
+		return srv.checkErr(c, "error while binding request", err)
+	}
+	resp, err := srv.srv.CreateWebhook(c.Request().Context(), req)
+	if err != nil {
+		return srv.checkErr(c, "error while creating webhook", err)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// HandleGetWebhooks returns all webhooks without their secrets.
+//
+//	@Tags		webhook-controller
+//	@Summary	Получение подписок на события
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{object}	model.GetWebhooksResponse	"OK"
+//	@Failure	400	{object}	model.BadRequestResponse	"Bad Request"
+//	@Security	ApiKeyAuth
+//	@Router		/webhooks [get]
+func (srv *Controller) HandleGetWebhooks(c echo.Context) error {
+	resp, err := srv.srv.GetWebhooks(c.Request().Context())
+	if err != nil {
+		return srv.checkErr(c, "error while getting webhooks", err)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleDeleteWebhook unsubscribes webhook from events.
+//
+// Pending deliveries of webhook are failed, history of deliveries is kept.
+//
+//	@Tags		webhook-controller
+//	@Summary	Удаление подписки на события
+//	@Accept		json
+//	@Produce	json
+//	@Param		webhook_id	path	int	true	"Webhook identifier"
+//	@Success	204			"No Content"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/webhooks/{webhook_id} [delete]
+func (srv *Controller) HandleDeleteWebhook(c echo.Context) error {
+	id := c.Param("webhook_id")
+	if err := srv.srv.DeleteWebhook(c.Request().Context(), id); err != nil {
+		return srv.checkErr(c, "error while deleting webhook", err, zap.String("webhook_id", id))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleGetWebhookDeliveries returns deliveries of events to webhook from the newest one.
+//
+//	@Tags		webhook-controller
+//	@Summary	История доставок событий
+//	@Accept		json
+//	@Produce	json
+//	@Param		webhook_id	path		int									true	"Webhook identifier"
+//	@Param		limit		query		int									false	"Максимальное количество доставок в выдаче. Если параметр не передан, то значение по умолчанию равно 1."
+//	@Param		offset		query		int									false	"Количество доставок, которое нужно пропустить для отображения текущей страницы. Если параметр не передан, то значение по умолчанию равно 0."
+//	@Success	200			{object}	model.GetWebhookDeliveriesResponse	"OK"
+//	@Failure	400			{object}	model.BadRequestResponse			"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse			"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/webhooks/{webhook_id}/deliveries [get]
+func (srv *Controller) HandleGetWebhookDeliveries(c echo.Context) error {
+	id := c.Param("webhook_id")
+	opts := GetPaginationOptsFromRequest(c)
+	resp, err := srv.srv.GetWebhookDeliveries(c.Request().Context(), id, opts)
+	if err != nil {
+		return srv.checkErr(c, "error while getting webhook deliveries", err, zap.String("webhook_id", id))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleReplayWebhookDelivery schedules event of delivery to be delivered to webhook again.
+//
+// Replay creates new delivery, replayed delivery is kept with its attempts.
+//
+//	@Tags		webhook-controller
+//	@Summary	Повторная доставка события
+//	@Accept		json
+//	@Produce	json
+//	@Param		webhook_id	path		int							true	"Webhook identifier"
+//	@Param		delivery_id	path		int							true	"Delivery identifier"
+//	@Success	202			{object}	model.WebhookDelivery		"Accepted"
+//	@Failure	400			{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	404			{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/webhooks/{webhook_id}/deliveries/{delivery_id}/replay [post]
+func (srv *Controller) HandleReplayWebhookDelivery(c echo.Context) error {
+	webhookID, deliveryID := c.Param("webhook_id"), c.Param("delivery_id")
+	resp, err := srv.srv.ReplayWebhookDelivery(c.Request().Context(), webhookID, deliveryID)
+	if err != nil {
+		return srv.checkErr(
+			c,
+			"error while replaying webhook delivery",
+			err,
+			zap.String("webhook_id", webhookID),
+			zap.String("delivery_id", deliveryID),
+		)
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// HandleCreateAPIKey issues API key with role.
+//
+// Only hash of key is stored, so key is returned only in response of this handler. Key is sent in Authorization
+// header as "Bearer <key>". Courier key must be bound to courier and allows only to complete orders of this courier
+// and to change their status.
+//
+//	@Tags		auth-controller
+//	@Summary	Выпуск API ключа
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		model.CreateAPIKeyRequest	true	"API key"
+//	@Success	201		{object}	model.CreateAPIKeyResponse	"Created"
+//	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	401		{object}	model.BadRequestResponse	"Unauthorized"
+//	@Failure	403		{object}	model.BadRequestResponse	"Forbidden"
+//	@Security	ApiKeyAuth
+//	@Router		/keys [post]
+func (srv *Controller) HandleCreateAPIKey(c echo.Context) error {
+	req := new(model.CreateAPIKeyRequest)
+	if err := c.Bind(req); err != nil {
+		return srv.checkErr(c, "error while binding request", err)
+	}
+	resp, err := srv.srv.CreateAPIKey(c.Request().Context(), req)
+	if err != nil {
+		return srv.checkErr(c, "error while creating api key", err)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// HandleRevokeAPIKey revokes API key, requests with it are unauthorized after that.
+//
+//	@Tags		auth-controller
+//	@Summary	Отзыв API ключа
+//	@Accept		json
+//	@Produce	json
+//	@Param		key_id	path	int	true	"API key identifier"
+//	@Success	204		"No Content"
+//	@Failure	400		{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	401		{object}	model.BadRequestResponse	"Unauthorized"
+//	@Failure	403		{object}	model.BadRequestResponse	"Forbidden"
+//	@Failure	404		{object}	model.BadRequestResponse	"Not Found"
+//	@Security	ApiKeyAuth
+//	@Router		/keys/{key_id} [delete]
+func (srv *Controller) HandleRevokeAPIKey(c echo.Context) error {
+	id := c.Param("key_id")
+	if err := srv.srv.RevokeAPIKey(c.Request().Context(), id); err != nil {
+		return srv.checkErr(c, "error while revoking api key", err, zap.String("key_id", id))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// HandleEvents streams events about created, assigned, completed and cancelled orders as Server-Sent Events.
+//
+// Every event is sent with its id, so client which reconnects with Last-Event-ID header gets events which it missed.
+// Stream selected by courier does not contain order.created events because new orders have no courier yet. Stream is
+// closed if client does not read events fast enough, client should reconnect then.
+//
+//	@Tags		event-controller
+//	@Summary	Поток событий заказов
+//	@Produce	text/event-stream
+//	@Param		courier_id		query		int							false	"Courier identifier"
+//	@Param		region			query		int							false	"Region of orders"
+//	@Param		Last-Event-ID	header		int							false	"Identifier of the last received event"
+//	@Success	200				{object}	model.Event					"OK"
+//	@Failure	400				{object}	model.BadRequestResponse	"Bad Request"
+//	@Failure	503				{object}	model.BadRequestResponse	"Service Unavailable"
+//	@Security	ApiKeyAuth
+//	@Router		/events [get]
+func (srv *Controller) HandleEvents(c echo.Context) error {
+	req, err := GetSubscribeEventsRequestFromRequest(c)
+	if err != nil {
+		srv.log.Debug("bad events request", zap.Error(err))
+		return c.JSON(http.StatusBadRequest, model.BadRequestResponse{})
+	}
+	ctx := c.Request().Context()
+	events, err := srv.broker.Subscribe(ctx, req)
+	if err != nil {
+		srv.log.Warn("error while subscribing to events", zap.Error(err))
+		return c.JSON(http.StatusServiceUnavailable, model.BadRequestResponse{})
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(&e)
+			if err != nil {
+				srv.log.Error("unable to marshal event", zap.Int64("event_id", e.ID), zap.Error(err))
+				continue
+			}
+			if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
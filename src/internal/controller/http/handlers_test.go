@@ -2,6 +2,7 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -265,6 +266,185 @@ func TestController_HandleGetCouriers_Negative(t *testing.T) {
 	}
 }
 
+func TestController_HandleUpdateCourier_Positive(t *testing.T) {
+	bike := model.BikeCourierTypeString
+	req := &model.UpdateCourierRequest{CourierType: &bike, Regions: []int32{4}}
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockService(ctrl)
+	srv.EXPECT().UpdateCourier(gomock.Any(), fmt.Sprint(TestCourier1.CourierID), req).Return(TestCourier1, nil)
+	s := testServer(t, srv)
+
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	r := httptest.NewRequest(http.MethodPatch, "/", bytes.NewReader(body))
+	r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	defer assert.NoError(t, r.Body.Close())
+	w := httptest.NewRecorder()
+	c := s.engine.NewContext(r, w)
+	c.SetParamNames("courier_id")
+	c.SetParamValues(fmt.Sprint(TestCourier1.CourierID))
+	if assert.NoError(t, s.HandleUpdateCourier(c)) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		jsonCourier, err := json.Marshal(TestCourier1)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(jsonCourier), w.Body.String())
+	}
+}
+
+func TestController_HandleUpdateCourier_Negative(t *testing.T) {
+	tt := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"bad body", "{", nil, http.StatusBadRequest, model.BadRequestResponse{}},
+		{"not found", `{"regions":[1]}`, fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound, someData},
+		{"unavailable", `{"regions":[1]}`, fielderr.New("some msg", someData, fielderr.CodeUnavailable), http.StatusServiceUnavailable, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.err != nil {
+				srv.EXPECT().UpdateCourier(gomock.Any(), "1", gomock.Any()).Return(nil, tc.err)
+			}
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("courier_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleUpdateCourier(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleDeactivateCourier(t *testing.T) {
+	deactivated := *TestCourier1
+	deactivated.Active = false
+	tt := []struct {
+		name       string
+		resp       *model.CourierDTO
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", &deactivated, nil, http.StatusOK, &deactivated},
+		{"not found", nil, fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().DeactivateCourier(gomock.Any(), "1").Return(tc.resp, tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("courier_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleDeactivateCourier(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleGetOrderHistory(t *testing.T) {
+	history := &model.OrderHistoryResponse{
+		OrderID: 1,
+		History: []model.OrderStatusChange{
+			{Status: model.OrderStatusCreated, Actor: model.ActorAPI, ChangedAt: datetime.Time(time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))},
+		},
+	}
+	tt := []struct {
+		name       string
+		resp       *model.OrderHistoryResponse
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", history, nil, http.StatusOK, history},
+		{"not found", nil, fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().GetOrderHistory(gomock.Any(), "1").Return(tc.resp, tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("order_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleGetOrderHistory(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleChangeOrderStatus(t *testing.T) {
+	order := &model.OrderDTO{OrderID: 1, Weight: 1, Regions: 1, Cost: 1, Status: model.OrderStatusInDelivery}
+	tt := []struct {
+		name       string
+		body       string
+		resp       *model.OrderDTO
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", `{"status":"IN_DELIVERY"}`, order, nil, http.StatusOK, order},
+		{"bad body", "{", nil, nil, http.StatusBadRequest, model.BadRequestResponse{}},
+		{"conflict", `{"status":"FAILED"}`, nil, fielderr.New("some msg", someData, fielderr.CodeConflict), http.StatusConflict, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.resp != nil || tc.err != nil {
+				srv.EXPECT().ChangeOrderStatus(gomock.Any(), "1", gomock.Any()).Return(tc.resp, tc.err)
+			}
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("order_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleChangeOrderStatus(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
 func TestController_HandleCreateCouriers_Positive(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	srv := mocks.NewMockService(ctrl)
@@ -699,7 +879,11 @@ func TestController_HandleGetOrder_Negative_NF(t *testing.T) {
 }
 
 func TestController_HandleGetOrders_Positive(t *testing.T) {
-	wantResp, resp := testOrders(t)
+	_, orders := testOrders(t)
+	resp := &model.GetOrdersResponse{Orders: orders, Limit: 1, NextCursor: model.EncodeCursor(123)}
+	wantResp, err := json.Marshal(resp)
+	require.NoError(t, err)
+
 	ctrl := gomock.NewController(t)
 	srv := mocks.NewMockService(ctrl)
 	srv.EXPECT().GetOrders(gomock.Any(), gomock.Eq(NewPaginationOpts("", ""))).Return(resp, nil)
@@ -714,7 +898,29 @@ func TestController_HandleGetOrders_Positive(t *testing.T) {
 	c := serv.engine.NewContext(r, w)
 	if assert.NoError(t, serv.HandleGetOrders(c)) {
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.JSONEq(t, wantResp, w.Body.String())
+		assert.JSONEq(t, string(wantResp), w.Body.String())
+	}
+}
+
+func TestController_HandleGetOrders_Positive_Cursor(t *testing.T) {
+	cursor := model.EncodeCursor(123)
+	resp := &model.GetOrdersResponse{Orders: []*model.OrderDTO{}, Limit: 1}
+
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockService(ctrl)
+	srv.EXPECT().GetOrders(gomock.Any(), gomock.Eq(NewPaginationOpts("", "").WithCursor(cursor))).Return(resp, nil)
+
+	serv := testServer(t, srv)
+
+	w := httptest.NewRecorder()
+	defer assert.NoError(t, w.Result().Body.Close())
+	r := httptest.NewRequest(http.MethodGet, "/?cursor="+cursor, nil)
+	defer assert.NoError(t, r.Body.Close())
+
+	c := serv.engine.NewContext(r, w)
+	if assert.NoError(t, serv.HandleGetOrders(c)) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"orders":[],"limit":1,"offset":0}`, w.Body.String())
 	}
 }
 
@@ -917,9 +1123,9 @@ func TestController_HandleAssignOrders_Positive(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	srv := mocks.NewMockService(ctrl)
 
-	srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date)).Return(resp, nil)
+	srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date), gomock.Eq(&AssignOpts{strategy: "optimal"})).Return(resp, nil)
 
-	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/?date=%s", dateLayout), nil)
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/?date=%s&strategy=optimal", dateLayout), nil)
 	defer assert.NoError(t, r.Body.Close())
 	w := httptest.NewRecorder()
 	defer assert.NoError(t, w.Result().Body.Close())
@@ -933,6 +1139,79 @@ func TestController_HandleAssignOrders_Positive(t *testing.T) {
 	}
 }
 
+func TestController_HandleAssignOrders_DryRun(t *testing.T) {
+	const dateLayout = "2022-12-22"
+	date, err := datetime.ParseDate(dateLayout)
+	require.NoError(t, err)
+
+	_, resp := assignOrdersResponse(t, dateLayout)
+	resp.DryRun = true
+	resp.Unassigned = []model.UnassignedOrder{{OrderID: 1}}
+	wantResp, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockService(ctrl)
+
+	srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date), gomock.Eq(&AssignOpts{dryRun: true})).Return(resp, nil)
+
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/?date=%s&dry_run=true", dateLayout), nil)
+	defer assert.NoError(t, r.Body.Close())
+	w := httptest.NewRecorder()
+	defer assert.NoError(t, w.Result().Body.Close())
+
+	serv := testServer(t, srv)
+	c := serv.engine.NewContext(r, w)
+
+	if assert.NoError(t, serv.HandleAssignOrders(c)) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, string(wantResp), w.Body.String())
+	}
+}
+
+func TestController_HandleAssignOrders_Incremental(t *testing.T) {
+	const dateLayout = "2022-12-22"
+	date, err := datetime.ParseDate(dateLayout)
+	require.NoError(t, err)
+
+	_, resp := assignOrdersResponse(t, dateLayout)
+	wantResp, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	srv := mocks.NewMockService(ctrl)
+
+	srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date), gomock.Eq(&AssignOpts{incremental: true})).Return(resp, nil)
+
+	r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/?date=%s&incremental=true", dateLayout), nil)
+	defer assert.NoError(t, r.Body.Close())
+	w := httptest.NewRecorder()
+	defer assert.NoError(t, w.Result().Body.Close())
+
+	serv := testServer(t, srv)
+	c := serv.engine.NewContext(r, w)
+
+	if assert.NoError(t, serv.HandleAssignOrders(c)) {
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, string(wantResp), w.Body.String())
+	}
+}
+
+func TestController_HandleAssignOrders_Negative_BadDryRun(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/?dry_run=maybe", nil)
+	defer assert.NoError(t, r.Body.Close())
+	w := httptest.NewRecorder()
+	defer assert.NoError(t, w.Result().Body.Close())
+
+	serv := testServer(t, nil)
+	c := serv.engine.NewContext(r, w)
+
+	if assert.NoError(t, serv.HandleAssignOrders(c)) {
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, "{}", w.Body.String())
+	}
+}
+
 func TestController_HandleAssignOrders_Negative_BadDate(t *testing.T) {
 	const dateLayout = "2022-12-33"
 
@@ -971,7 +1250,7 @@ func TestController_HandleAssignOrders_Negative_ErrInternal(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			srv := mocks.NewMockService(ctrl)
 
-			srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date)).Return(nil, tc.err)
+			srv.EXPECT().AssignOrders(gomock.Any(), gomock.Eq(date), gomock.Eq(&AssignOpts{})).Return(nil, tc.err)
 
 			r := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/?date=%s", dateLayout), nil)
 			defer assert.NoError(t, r.Body.Close())
@@ -990,3 +1269,400 @@ func TestController_HandleAssignOrders_Negative_ErrInternal(t *testing.T) {
 		})
 	}
 }
+
+func TestController_HandleCancelOrders(t *testing.T) {
+	cancelled := []*model.OrderDTO{
+		{OrderID: 1, Weight: 1, Regions: 1, Cost: 1, Status: model.OrderStatusCancelled, CancelReason: "withdrew"},
+	}
+	tt := []struct {
+		name       string
+		body       string
+		resp       []*model.OrderDTO
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", `{"order_ids":[1],"reason":"withdrew"}`, cancelled, nil, http.StatusOK, cancelled},
+		{"bad body", "{", nil, nil, http.StatusBadRequest, model.BadRequestResponse{}},
+		{"conflict", `{"order_ids":[1],"reason":"withdrew"}`, nil, fielderr.New("some msg", someData, fielderr.CodeConflict), http.StatusConflict, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.resp != nil || tc.err != nil {
+				srv.EXPECT().
+					CancelOrders(gomock.Any(), &model.CancelOrderRequest{OrderIDs: []int64{1}, Reason: "withdrew"}).
+					Return(tc.resp, tc.err)
+			}
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			if assert.NoError(t, s.HandleCancelOrders(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleCreateWebhook(t *testing.T) {
+	webhook := &model.WebhookDTO{
+		WebhookID:  1,
+		URL:        "http://127.0.0.1:8081/events",
+		EventTypes: []string{model.EventOrderCreated},
+		Secret:     "0123456789abcdef",
+	}
+	body := `{"url":"http://127.0.0.1:8081/events","event_types":["order.created"]}`
+	tt := []struct {
+		name       string
+		body       string
+		resp       *model.WebhookDTO
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", body, webhook, nil, http.StatusCreated, webhook},
+		{"bad body", "{", nil, nil, http.StatusBadRequest, model.BadRequestResponse{}},
+		{"bad request", body, nil, fielderr.New("some msg", someData, fielderr.CodeBadRequest), http.StatusBadRequest, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.resp != nil || tc.err != nil {
+				srv.EXPECT().
+					CreateWebhook(gomock.Any(), &model.CreateWebhookRequest{URL: webhook.URL, EventTypes: webhook.EventTypes}).
+					Return(tc.resp, tc.err)
+			}
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			if assert.NoError(t, s.HandleCreateWebhook(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleGetWebhooks(t *testing.T) {
+	webhooks := &model.GetWebhooksResponse{Webhooks: []model.WebhookDTO{
+		{WebhookID: 1, URL: "http://127.0.0.1:8081/events", EventTypes: []string{model.EventOrderCreated}},
+	}}
+	tt := []struct {
+		name       string
+		resp       *model.GetWebhooksResponse
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", webhooks, nil, http.StatusOK, webhooks},
+		{"unavailable", nil, fielderr.New("some msg", someData, fielderr.CodeUnavailable), http.StatusServiceUnavailable, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().GetWebhooks(gomock.Any()).Return(tc.resp, tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			if assert.NoError(t, s.HandleGetWebhooks(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleDeleteWebhook(t *testing.T) {
+	tt := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"positive", nil, http.StatusNoContent},
+		{"not found", fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().DeleteWebhook(gomock.Any(), "1").Return(tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("webhook_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleDeleteWebhook(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestController_HandleGetWebhookDeliveries(t *testing.T) {
+	deliveries := &model.GetWebhookDeliveriesResponse{
+		Deliveries: []model.WebhookDelivery{{DeliveryID: 2, WebhookID: 1, Status: model.DeliveryStatusFailed, Attempts: 5}},
+		Limit:      1,
+	}
+	tt := []struct {
+		name       string
+		resp       *model.GetWebhookDeliveriesResponse
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", deliveries, nil, http.StatusOK, deliveries},
+		{"not found", nil, fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().GetWebhookDeliveries(gomock.Any(), "1", NewPaginationOpts("", "")).Return(tc.resp, tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("webhook_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleGetWebhookDeliveries(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleReplayWebhookDelivery(t *testing.T) {
+	replay := &model.WebhookDelivery{DeliveryID: 3, WebhookID: 1, EventID: 1, Status: model.DeliveryStatusPending}
+	tt := []struct {
+		name       string
+		resp       *model.WebhookDelivery
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", replay, nil, http.StatusAccepted, replay},
+		{"not found", nil, fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().ReplayWebhookDelivery(gomock.Any(), "1", "2").Return(tc.resp, tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("webhook_id", "delivery_id")
+			c.SetParamValues("1", "2")
+			if assert.NoError(t, s.HandleReplayWebhookDelivery(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleEvents(t *testing.T) {
+	events := []model.Event{
+		{ID: 3, Type: model.EventOrderCreated, Payload: json.RawMessage(`{"order_id":1}`)},
+		{ID: 5, Type: model.EventOrderAssigned, Payload: json.RawMessage(`{"order_id":1,"courier_id":2}`)},
+	}
+	ctrl := gomock.NewController(t)
+	broker := mocks.NewMockBroker(ctrl)
+	last := int64(2)
+	broker.EXPECT().
+		Subscribe(gomock.Any(), &model.SubscribeEventsRequest{CourierID: 2, Region: 1, LastEventID: &last}).
+		DoAndReturn(func(context.Context, *model.SubscribeEventsRequest) (<-chan model.Event, error) {
+			ch := make(chan model.Event, len(events))
+			for _, e := range events {
+				ch <- e
+			}
+			close(ch)
+			return ch, nil
+		})
+	s := testServer(t, nil)
+	s.broker = broker
+
+	r := httptest.NewRequest(http.MethodGet, "/events?courier_id=2&region=1", nil)
+	r.Header.Set("Last-Event-ID", "2")
+	w := httptest.NewRecorder()
+	if assert.NoError(t, s.HandleEvents(s.engine.NewContext(r, w))) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "no-cache", w.Header().Get(echo.HeaderCacheControl))
+
+		var want strings.Builder
+		for _, e := range events {
+			data, err := json.Marshal(&e)
+			require.NoError(t, err)
+			_, _ = fmt.Fprintf(&want, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		assert.Equal(t, want.String(), w.Body.String())
+	}
+}
+
+func TestController_HandleEvents_ClientGone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	broker := mocks.NewMockBroker(ctrl)
+	broker.EXPECT().Subscribe(gomock.Any(), &model.SubscribeEventsRequest{}).Return(make(chan model.Event), nil)
+	s := testServer(t, nil)
+	s.broker = broker
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest(http.MethodGet, "/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	done := make(chan error)
+	go func() {
+		done <- s.HandleEvents(s.engine.NewContext(r, w))
+	}()
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		assert.Fail(t, "handler must return when client is gone")
+	}
+}
+
+func TestController_HandleEvents_Negative(t *testing.T) {
+	for name, tc := range map[string]struct {
+		target string
+		last   string
+	}{
+		"bad courier":       {"/events?courier_id=x", ""},
+		"negative courier":  {"/events?courier_id=-1", ""},
+		"bad region":        {"/events?region=1.5", ""},
+		"bad last event id": {"/events", "x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := testServer(t, nil)
+			s.broker = mocks.NewMockBroker(gomock.NewController(t))
+			r := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			r.Header.Set("Last-Event-ID", tc.last)
+			w := httptest.NewRecorder()
+			if assert.NoError(t, s.HandleEvents(s.engine.NewContext(r, w))) {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+	t.Run("broker failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		broker := mocks.NewMockBroker(ctrl)
+		broker.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(nil, ErrUnknown)
+		s := testServer(t, nil)
+		s.broker = broker
+
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		w := httptest.NewRecorder()
+		if assert.NoError(t, s.HandleEvents(s.engine.NewContext(r, w))) {
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		}
+	})
+}
+
+func TestController_HandleCreateAPIKey(t *testing.T) {
+	courierID := int64(1)
+	key := &model.CreateAPIKeyResponse{
+		APIKey: model.APIKey{KeyID: 1, Role: model.RoleCourier, CourierID: &courierID},
+		Key:    "0123456789abcdef",
+	}
+	body := `{"role":"courier","courier_id":1}`
+	tt := []struct {
+		name       string
+		body       string
+		resp       *model.CreateAPIKeyResponse
+		err        error
+		wantStatus int
+		wantResp   any
+	}{
+		{"positive", body, key, nil, http.StatusCreated, key},
+		{"bad body", "{", nil, nil, http.StatusBadRequest, model.BadRequestResponse{}},
+		{"bad request", body, nil, fielderr.New("some msg", someData, fielderr.CodeBadRequest), http.StatusBadRequest, someData},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.resp != nil || tc.err != nil {
+				srv.EXPECT().
+					CreateAPIKey(gomock.Any(), &model.CreateAPIKeyRequest{Role: model.RoleCourier, CourierID: &courierID}).
+					Return(tc.resp, tc.err)
+			}
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			if assert.NoError(t, s.HandleCreateAPIKey(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+				wantResp, err := json.Marshal(tc.wantResp)
+				require.NoError(t, err)
+				assert.JSONEq(t, string(wantResp), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestController_HandleRevokeAPIKey(t *testing.T) {
+	tt := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"positive", nil, http.StatusNoContent},
+		{"not found", fielderr.New("some msg", someData, fielderr.CodeNotFound), http.StatusNotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			srv.EXPECT().RevokeAPIKey(gomock.Any(), "1").Return(tc.err)
+			s := testServer(t, srv)
+
+			r := httptest.NewRequest(http.MethodDelete, "/", nil)
+			defer assert.NoError(t, r.Body.Close())
+			w := httptest.NewRecorder()
+			c := s.engine.NewContext(r, w)
+			c.SetParamNames("key_id")
+			c.SetParamValues("1")
+			if assert.NoError(t, s.HandleRevokeAPIKey(c)) {
+				assert.Equal(t, tc.wantStatus, w.Code)
+			}
+		})
+	}
+}
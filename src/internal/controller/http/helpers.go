@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"github.com/labstack/echo/v4"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/fielderr"
@@ -14,6 +15,16 @@ import (
 const (
 	queryLimitParamName  = "limit"
 	queryOffsetParamName = "offset"
+	queryCursorParamName = "cursor"
+
+	queryStrategyParamName    = "strategy"
+	queryDryRunParamName      = "dry_run"
+	queryForceParamName       = "force"
+	queryIncrementalParamName = "incremental"
+
+	queryCourierIDParamName = "courier_id"
+	queryRegionParamName    = "region"
+	headerLastEventID       = "Last-Event-ID"
 )
 
 // respond writes data to response writer.
@@ -44,6 +55,7 @@ func (srv *Controller) dateFromContext(c echo.Context, queryParamName string) (*
 type PaginationOpts struct {
 	limit  int
 	offset int
+	cursor string
 }
 
 // NewPaginationOpts returns PaginationOpts with provided limit and offset.
@@ -90,8 +102,146 @@ func (opts *PaginationOpts) Offset() int {
 	return opts.offset
 }
 
+// WithCursor sets opaque cursor of page and returns opts.
+//
+// Cursor is validated by service, so it is stored as is.
+func (opts *PaginationOpts) WithCursor(cursor string) *PaginationOpts {
+	if opts == nil {
+		opts = NewPaginationOpts("", "")
+	}
+	opts.cursor = cursor
+	return opts
+}
+
+// Cursor is cursor getter.
+func (opts *PaginationOpts) Cursor() string {
+	if opts == nil {
+		zap.L().Warn("unexpected got nil pagination opts")
+		return ""
+	}
+	return opts.cursor
+}
+
 // GetPaginationOptsFromRequest return options from echo context.
 func GetPaginationOptsFromRequest(c echo.Context) *PaginationOpts {
 	opts := NewPaginationOpts(c.QueryParam(queryLimitParamName), c.QueryParam(queryOffsetParamName))
-	return opts
+	return opts.WithCursor(c.QueryParam(queryCursorParamName))
+}
+
+// AssignOpts encapsulates options of orders assignment into private fields.
+//
+// Opts can be accessed by getters.
+type AssignOpts struct {
+	strategy    string
+	dryRun      bool
+	force       bool
+	incremental bool
+}
+
+// NewAssignOpts returns AssignOpts with provided strategy, dry run, force and incremental flags.
+//
+// Empty strategy means that default strategy of service must be used. Empty flag means false, unlike pagination
+// opts not parsable flag is an error because silently doing another kind of assignment is dangerous.
+func NewAssignOpts(strategy, dryRun, force, incremental string) (*AssignOpts, error) {
+	opts := &AssignOpts{
+		strategy: strategy,
+	}
+	var err error
+	if opts.dryRun, err = parseFlag(dryRun); err != nil {
+		return nil, fmt.Errorf("dry_run: %w", err)
+	}
+	if opts.force, err = parseFlag(force); err != nil {
+		return nil, fmt.Errorf("force: %w", err)
+	}
+	if opts.incremental, err = parseFlag(incremental); err != nil {
+		return nil, fmt.Errorf("incremental: %w", err)
+	}
+	return opts, nil
+}
+
+// parseFlag parses boolean query param, empty param means false.
+func parseFlag(s string) (bool, error) {
+	if s == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+// Strategy is strategy getter.
+func (opts *AssignOpts) Strategy() string {
+	if opts == nil {
+		zap.L().Warn("unexpected got nil assign opts")
+		return ""
+	}
+	return opts.strategy
+}
+
+// DryRun is dry run getter.
+func (opts *AssignOpts) DryRun() bool {
+	if opts == nil {
+		zap.L().Warn("unexpected got nil assign opts")
+		return false
+	}
+	return opts.dryRun
+}
+
+// Force is force getter.
+func (opts *AssignOpts) Force() bool {
+	if opts == nil {
+		zap.L().Warn("unexpected got nil assign opts")
+		return false
+	}
+	return opts.force
+}
+
+// Incremental is incremental getter.
+func (opts *AssignOpts) Incremental() bool {
+	if opts == nil {
+		zap.L().Warn("unexpected got nil assign opts")
+		return false
+	}
+	return opts.incremental
+}
+
+// GetAssignOptsFromRequest return assign options from echo context.
+func GetAssignOptsFromRequest(c echo.Context) (*AssignOpts, error) {
+	return NewAssignOpts(
+		c.QueryParam(queryStrategyParamName),
+		c.QueryParam(queryDryRunParamName),
+		c.QueryParam(queryForceParamName),
+		c.QueryParam(queryIncrementalParamName),
+	)
+}
+
+// GetSubscribeEventsRequestFromRequest returns request of events stream from echo context.
+//
+// Empty courier and region select events of all couriers and regions. Last event id is taken from Last-Event-ID header
+// which browsers send on reconnect.
+func GetSubscribeEventsRequestFromRequest(c echo.Context) (*model.SubscribeEventsRequest, error) {
+	req := new(model.SubscribeEventsRequest)
+	if s := c.QueryParam(queryCourierIDParamName); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %w", queryCourierIDParamName, err)
+		}
+		req.CourierID = id
+	}
+	if s := c.QueryParam(queryRegionParamName); s != "" {
+		region, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %w", queryRegionParamName, err)
+		}
+		req.Region = int32(region)
+	}
+	if s := c.Request().Header.Get(headerLastEventID); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %w", headerLastEventID, err)
+		}
+		req.LastEventID = &id
+	}
+	if !req.Valid() {
+		return nil, errors.New("negative identifier")
+	}
+	return req, nil
 }
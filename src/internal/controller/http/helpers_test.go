@@ -83,6 +83,125 @@ func TestPaginationOpts_Offset(t *testing.T) {
 	}
 }
 
+func TestPaginationOpts_Cursor(t *testing.T) {
+	tt := []struct {
+		name string
+		opts *PaginationOpts
+		want string
+	}{
+		{"nil", nil, ""},
+		{"non nil", &PaginationOpts{cursor: "abc"}, "abc"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.opts.Cursor())
+		})
+	}
+}
+
+func TestPaginationOpts_WithCursor(t *testing.T) {
+	assert.Equal(t, &PaginationOpts{limit: 1, cursor: "abc"}, (*PaginationOpts)(nil).WithCursor("abc"))
+	assert.Equal(t, &PaginationOpts{limit: 2, offset: 3, cursor: "abc"}, NewPaginationOpts("2", "3").WithCursor("abc"))
+}
+
+func TestAssignOpts_Strategy(t *testing.T) {
+	tt := []struct {
+		name string
+		opts *AssignOpts
+		want string
+	}{
+		{"nil", nil, ""},
+		{"non nil", &AssignOpts{strategy: "optimal"}, "optimal"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.opts.Strategy())
+		})
+	}
+}
+
+func TestAssignOpts_DryRun(t *testing.T) {
+	tt := []struct {
+		name string
+		opts *AssignOpts
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non nil", &AssignOpts{dryRun: true}, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.opts.DryRun())
+		})
+	}
+}
+
+func TestAssignOpts_Force(t *testing.T) {
+	tt := []struct {
+		name string
+		opts *AssignOpts
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non nil", &AssignOpts{force: true}, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.opts.Force())
+		})
+	}
+}
+
+func TestAssignOpts_Incremental(t *testing.T) {
+	tt := []struct {
+		name string
+		opts *AssignOpts
+		want bool
+	}{
+		{"nil", nil, false},
+		{"non nil", &AssignOpts{incremental: true}, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.opts.Incremental())
+		})
+	}
+}
+
+func TestNewAssignOpts(t *testing.T) {
+	tt := []struct {
+		name        string
+		strategy    string
+		dryRun      string
+		force       string
+		incremental string
+		want        *AssignOpts
+		wantErr     bool
+	}{
+		{"not provided", "", "", "", "", &AssignOpts{}, false},
+		{"strategy", "greedy", "", "", "", &AssignOpts{strategy: "greedy"}, false},
+		{"dry run", "", "true", "", "", &AssignOpts{dryRun: true}, false},
+		{"not dry run", "optimal", "false", "", "", &AssignOpts{strategy: "optimal"}, false},
+		{"force", "", "", "1", "", &AssignOpts{force: true}, false},
+		{"dry run with force", "", "true", "true", "", &AssignOpts{dryRun: true, force: true}, false},
+		{"incremental", "", "", "", "true", &AssignOpts{incremental: true}, false},
+		{"dry run not parsable", "", "bad", "", "", nil, true},
+		{"force not parsable", "", "", "bad", "", nil, true},
+		{"incremental not parsable", "", "", "", "bad", nil, true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := NewAssignOpts(tc.strategy, tc.dryRun, tc.force, tc.incremental)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, opts)
+		})
+	}
+}
+
 func TestNewPaginationOpts(t *testing.T) {
 	tt := []struct {
 		name       string
@@ -8,6 +8,7 @@ import (
 	_ "github.com/vlad-marlo/yandex-academy-enrollment/docs"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller"
 	mw "github.com/vlad-marlo/yandex-academy-enrollment/internal/middleware"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/zap"
 )
 
@@ -16,23 +17,29 @@ type Controller struct {
 	log     *zap.Logger
 	cfg     controller.Config
 	srv     controller.Service
+	broker  controller.Broker
 	rateCfg mw.RateLimitConfig
+	authCfg mw.AuthConfig
 }
 
 func New(
 	logger *zap.Logger,
 	cfg controller.Config,
 	rateCfg mw.RateLimitConfig,
+	authCfg mw.AuthConfig,
 	service controller.Service,
+	broker controller.Broker,
 ) (*Controller, error) {
 	srv := &Controller{
 		engine:  echo.New(),
 		log:     logger,
 		cfg:     cfg,
 		srv:     service,
+		broker:  broker,
 		rateCfg: rateCfg,
+		authCfg: authCfg,
 	}
-	if logger == nil || cfg == nil || rateCfg == nil || service == nil {
+	if logger == nil || cfg == nil || rateCfg == nil || authCfg == nil || service == nil || broker == nil {
 		return nil, ErrNilReference
 	}
 	srv.configure()
@@ -48,26 +55,53 @@ func (srv *Controller) configureMW() {
 	//srv.engine.Pre(mw.LogRequest(srv.log))
 }
 
+// configureRoutes registers handlers with roles of API keys which are allowed to call them.
+//
+// Admins can do everything, dispatchers can do everything except management of API keys. Couriers can only complete
+// their own orders and change their status.
 func (srv *Controller) configureRoutes() {
+	var (
+		admin  = mw.Authorize(srv.authCfg, srv.srv, model.RoleAdmin)
+		staff  = mw.Authorize(srv.authCfg, srv.srv, model.RoleAdmin, model.RoleDispatcher)
+		anyone = mw.Authorize(srv.authCfg, srv.srv, model.RoleAdmin, model.RoleDispatcher, model.RoleCourier)
+	)
 	srv.engine.GET("/swagger/*", echoSwagger.WrapHandler)
 	srv.engine.GET("/ping", srv.HandlePing)
+	srv.engine.GET("/events", srv.HandleEvents, staff)
 	couriers := srv.engine.Group("/couriers")
 	{
-		srv.engine.GET("/couriers", srv.HandleGetCouriers)
-		srv.engine.POST("/couriers", srv.HandleCreateCouriers)
-		couriers.GET("/:courier_id", srv.HandleGetCourier)
-		couriers.GET("/meta-info/:courier_id", srv.HandleGetCourierMetaInfo)
-		couriers.GET("/assignments", srv.HandleGetOrdersAssign)
+		srv.engine.GET("/couriers", srv.HandleGetCouriers, staff)
+		srv.engine.POST("/couriers", srv.HandleCreateCouriers, staff)
+		couriers.GET("/:courier_id", srv.HandleGetCourier, staff)
+		couriers.PATCH("/:courier_id", srv.HandleUpdateCourier, staff)
+		couriers.DELETE("/:courier_id", srv.HandleDeactivateCourier, staff)
+		couriers.GET("/meta-info/:courier_id", srv.HandleGetCourierMetaInfo, staff)
+		couriers.GET("/assignments", srv.HandleGetOrdersAssign, staff)
 	}
 	orders := srv.engine.Group("/orders")
 	{
-		orders.POST("/orders/complete", srv.HandleCompleteOrders)
-		orders.POST("/orders/assign", srv.HandleAssignOrders)
-		orders.GET("/orders/:order_id", srv.HandleGetOrder)
-		srv.engine.GET("/orders", srv.HandleGetOrders)
-		srv.engine.POST("/orders", srv.HandleCreateOrders)
+		orders.POST("/complete", srv.HandleCompleteOrders, anyone)
+		orders.POST("/cancel", srv.HandleCancelOrders, staff)
+		orders.POST("/assign", srv.HandleAssignOrders, staff)
+		orders.GET("/:order_id", srv.HandleGetOrder, staff)
+		orders.GET("/:order_id/history", srv.HandleGetOrderHistory, staff)
+		orders.POST("/:order_id/status", srv.HandleChangeOrderStatus, anyone)
+		srv.engine.GET("/orders", srv.HandleGetOrders, staff)
+		srv.engine.POST("/orders", srv.HandleCreateOrders, staff)
+	}
+	webhooks := srv.engine.Group("/webhooks")
+	{
+		srv.engine.GET("/webhooks", srv.HandleGetWebhooks, staff)
+		srv.engine.POST("/webhooks", srv.HandleCreateWebhook, staff)
+		webhooks.DELETE("/:webhook_id", srv.HandleDeleteWebhook, staff)
+		webhooks.GET("/:webhook_id/deliveries", srv.HandleGetWebhookDeliveries, staff)
+		webhooks.POST("/:webhook_id/deliveries/:delivery_id/replay", srv.HandleReplayWebhookDelivery, staff)
+	}
+	keys := srv.engine.Group("/keys")
+	{
+		srv.engine.POST("/keys", srv.HandleCreateAPIKey, admin)
+		keys.DELETE("/:key_id", srv.HandleRevokeAPIKey, admin)
 	}
-
 }
 
 func (srv *Controller) configure() {
@@ -2,11 +2,18 @@ package http
 
 import (
 	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller/mocks"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -20,30 +27,45 @@ func TestRun(t *testing.T) {
 
 func TestNew(t *testing.T) {
 	t.Run("positive", func(t *testing.T) {
-		srv, err := New(zap.L(), &config{}, &config{}, &mocks.MockService{})
+		srv, err := New(zap.L(), &config{}, &config{}, &config{}, &mocks.MockService{}, &mocks.MockBroker{})
 		assert.NoError(t, err)
 		if assert.NotNil(t, srv) {
 			assert.Equal(t, zap.L(), srv.log)
 			assert.Equal(t, &config{}, srv.cfg)
 			assert.Equal(t, &config{}, srv.rateCfg)
+			assert.Equal(t, &config{}, srv.authCfg)
 		}
 	})
 	t.Run("nil logger", func(t *testing.T) {
-		srv, err := New(nil, &config{}, &config{}, &mocks.MockService{})
+		srv, err := New(nil, &config{}, &config{}, &config{}, &mocks.MockService{}, &mocks.MockBroker{})
 		assert.Nil(t, srv)
 		if assert.Error(t, err) {
 			assert.ErrorIs(t, err, ErrNilReference)
 		}
 	})
 	t.Run("nil config", func(t *testing.T) {
-		srv, err := New(zap.L(), nil, &config{}, &mocks.MockService{})
+		srv, err := New(zap.L(), nil, &config{}, &config{}, &mocks.MockService{}, &mocks.MockBroker{})
 		assert.Nil(t, srv)
 		if assert.Error(t, err) {
 			assert.ErrorIs(t, err, ErrNilReference)
 		}
 	})
 	t.Run("nil rate config", func(t *testing.T) {
-		srv, err := New(zap.L(), &config{}, nil, &mocks.MockService{})
+		srv, err := New(zap.L(), &config{}, nil, &config{}, &mocks.MockService{}, &mocks.MockBroker{})
+		assert.Nil(t, srv)
+		if assert.Error(t, err) {
+			assert.ErrorIs(t, err, ErrNilReference)
+		}
+	})
+	t.Run("nil auth config", func(t *testing.T) {
+		srv, err := New(zap.L(), &config{}, &config{}, nil, &mocks.MockService{}, &mocks.MockBroker{})
+		assert.Nil(t, srv)
+		if assert.Error(t, err) {
+			assert.ErrorIs(t, err, ErrNilReference)
+		}
+	})
+	t.Run("nil broker", func(t *testing.T) {
+		srv, err := New(zap.L(), &config{}, &config{}, &config{}, &mocks.MockService{}, nil)
 		assert.Nil(t, srv)
 		if assert.Error(t, err) {
 			assert.ErrorIs(t, err, ErrNilReference)
@@ -57,3 +79,104 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, rate.Limit(10), c.Limit())
 	assert.Equal(t, bindAddr, c.BindAddr())
 }
+
+func TestController_configureRoutes(t *testing.T) {
+	srv := testServer(t, nil)
+	srv.configureRoutes()
+
+	routes := make(map[string]bool)
+	for _, r := range srv.engine.Routes() {
+		routes[r.Method+" "+r.Path] = true
+	}
+	for _, want := range []string{
+		"GET /events",
+		"POST /orders/complete",
+		"POST /orders/cancel",
+		"POST /orders/assign",
+		"GET /orders/:order_id",
+		"GET /orders/:order_id/history",
+		"POST /orders/:order_id/status",
+		"GET /couriers/assignments",
+		"PATCH /couriers/:courier_id",
+		"DELETE /couriers/:courier_id",
+		"GET /webhooks",
+		"POST /webhooks",
+		"DELETE /webhooks/:webhook_id",
+		"GET /webhooks/:webhook_id/deliveries",
+		"POST /webhooks/:webhook_id/deliveries/:delivery_id/replay",
+		"POST /keys",
+		"DELETE /keys/:key_id",
+	} {
+		assert.True(t, routes[want], want)
+	}
+}
+
+type authConfig struct{}
+
+func (authConfig) AuthEnabled() bool { return true }
+
+func (authConfig) AdminKey() string { return "" }
+
+func TestController_configureRoutes_Auth(t *testing.T) {
+	courierID := int64(1)
+	courier := &model.APIKey{KeyID: 1, Role: model.RoleCourier, CourierID: &courierID}
+	tt := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		code   int
+	}{
+		{"no key", http.MethodPost, "/orders/complete", "", http.StatusUnauthorized},
+		{"courier completes", http.MethodPost, "/orders/complete", "courier", http.StatusOK},
+		{"courier changes status", http.MethodPost, "/orders/1/status", "courier", http.StatusOK},
+		{"courier gets order", http.MethodGet, "/orders/1", "courier", http.StatusForbidden},
+		{"courier cancels", http.MethodPost, "/orders/cancel", "courier", http.StatusForbidden},
+		{"courier issues key", http.MethodPost, "/keys", "courier", http.StatusForbidden},
+		{"public ping", http.MethodGet, "/ping", "", http.StatusOK},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			srv := mocks.NewMockService(ctrl)
+			if tc.key != "" {
+				srv.EXPECT().Authenticate(gomock.Any(), tc.key).Return(courier, nil)
+			}
+			checkKey := func(ctx context.Context) {
+				key, ok := auth.FromContext(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, courier, key)
+			}
+			if tc.code == http.StatusOK && tc.key != "" {
+				switch tc.path {
+				case "/orders/complete":
+					srv.EXPECT().CompleteOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+						func(ctx context.Context, _ *model.CompleteOrderRequest) ([]*model.OrderDTO, error) {
+							checkKey(ctx)
+							return nil, nil
+						},
+					)
+				case "/orders/1/status":
+					srv.EXPECT().ChangeOrderStatus(gomock.Any(), "1", gomock.Any()).DoAndReturn(
+						func(ctx context.Context, _ string, _ *model.ChangeOrderStatusRequest) (*model.OrderDTO, error) {
+							checkKey(ctx)
+							return &model.OrderDTO{OrderID: 1}, nil
+						},
+					)
+				}
+			}
+			s := testServer(t, srv)
+			s.authCfg = authConfig{}
+			s.configureRoutes()
+
+			r := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"complete_info":[]}`))
+			r.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.key != "" {
+				r.Header.Set(echo.HeaderAuthorization, "Bearer "+tc.key)
+			}
+			w := httptest.NewRecorder()
+			s.engine.ServeHTTP(w, r)
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
@@ -18,6 +18,10 @@ func (c *config) Burst() int { return 10 }
 
 func (*config) BindAddr() string { return bindAddr }
 
+func (*config) AuthEnabled() bool { return false }
+
+func (*config) AdminKey() string { return "" }
+
 func testServer(t testing.TB, srv controller.Service) *Controller {
 	t.Helper()
 	ctrl := &Controller{
@@ -26,6 +30,7 @@ func testServer(t testing.TB, srv controller.Service) *Controller {
 		cfg:     &config{},
 		srv:     srv,
 		rateCfg: &config{},
+		authCfg: &config{},
 	}
 	return ctrl
 }
@@ -37,18 +37,63 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // AssignOrders mocks base method.
-func (m *MockService) AssignOrders(ctx context.Context, date *datetime.Date) (*model.OrderAssignResponse, error) {
+func (m *MockService) AssignOrders(ctx context.Context, date *datetime.Date, opts model.AssignOpts) (*model.OrderAssignResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AssignOrders", ctx, date)
+	ret := m.ctrl.Call(m, "AssignOrders", ctx, date, opts)
 	ret0, _ := ret[0].(*model.OrderAssignResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AssignOrders indicates an expected call of AssignOrders.
-func (mr *MockServiceMockRecorder) AssignOrders(ctx, date interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) AssignOrders(ctx, date, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignOrders", reflect.TypeOf((*MockService)(nil).AssignOrders), ctx, date)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignOrders", reflect.TypeOf((*MockService)(nil).AssignOrders), ctx, date, opts)
+}
+
+// Authenticate mocks base method.
+func (m *MockService) Authenticate(ctx context.Context, key string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockServiceMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockService)(nil).Authenticate), ctx, key)
+}
+
+// CancelOrders mocks base method.
+func (m *MockService) CancelOrders(ctx context.Context, req *model.CancelOrderRequest) ([]*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrders", ctx, req)
+	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrders indicates an expected call of CancelOrders.
+func (mr *MockServiceMockRecorder) CancelOrders(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrders", reflect.TypeOf((*MockService)(nil).CancelOrders), ctx, req)
+}
+
+// ChangeOrderStatus mocks base method.
+func (m *MockService) ChangeOrderStatus(ctx context.Context, id string, req *model.ChangeOrderStatusRequest) (*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeOrderStatus", ctx, id, req)
+	ret0, _ := ret[0].(*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeOrderStatus indicates an expected call of ChangeOrderStatus.
+func (mr *MockServiceMockRecorder) ChangeOrderStatus(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeOrderStatus", reflect.TypeOf((*MockService)(nil).ChangeOrderStatus), ctx, id, req)
 }
 
 // CompleteOrders mocks base method.
@@ -66,6 +111,21 @@ func (mr *MockServiceMockRecorder) CompleteOrders(ctx, req interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteOrders", reflect.TypeOf((*MockService)(nil).CompleteOrders), ctx, req)
 }
 
+// CreateAPIKey mocks base method.
+func (m *MockService) CreateAPIKey(ctx context.Context, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, req)
+	ret0, _ := ret[0].(*model.CreateAPIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceMockRecorder) CreateAPIKey(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockService)(nil).CreateAPIKey), ctx, req)
+}
+
 // CreateCouriers mocks base method.
 func (m *MockService) CreateCouriers(ctx context.Context, request *model.CreateCourierRequest) (*model.CouriersCreateResponse, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +156,50 @@ func (mr *MockServiceMockRecorder) CreateOrders(ctx, req interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockService)(nil).CreateOrders), ctx, req)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, req)
+	ret0, _ := ret[0].(*model.WebhookDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), ctx, req)
+}
+
+// DeactivateCourier mocks base method.
+func (m *MockService) DeactivateCourier(ctx context.Context, id string) (*model.CourierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateCourier", ctx, id)
+	ret0, _ := ret[0].(*model.CourierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateCourier indicates an expected call of DeactivateCourier.
+func (mr *MockServiceMockRecorder) DeactivateCourier(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateCourier", reflect.TypeOf((*MockService)(nil).DeactivateCourier), ctx, id)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockService) DeleteWebhook(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockService)(nil).DeleteWebhook), ctx, id)
+}
+
 // GetCourierByID mocks base method.
 func (m *MockService) GetCourierByID(ctx context.Context, id string) (*model.CourierDTO, error) {
 	m.ctrl.T.Helper()
@@ -156,11 +260,26 @@ func (mr *MockServiceMockRecorder) GetOrderByID(ctx, id interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockService)(nil).GetOrderByID), ctx, id)
 }
 
+// GetOrderHistory mocks base method.
+func (m *MockService) GetOrderHistory(ctx context.Context, id string) (*model.OrderHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", ctx, id)
+	ret0, _ := ret[0].(*model.OrderHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockServiceMockRecorder) GetOrderHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockService)(nil).GetOrderHistory), ctx, id)
+}
+
 // GetOrders mocks base method.
-func (m *MockService) GetOrders(ctx context.Context, opts model.PaginationOpts) ([]*model.OrderDTO, error) {
+func (m *MockService) GetOrders(ctx context.Context, opts model.PaginationOpts) (*model.GetOrdersResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetOrders", ctx, opts)
-	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret0, _ := ret[0].(*model.GetOrdersResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -185,3 +304,115 @@ func (mr *MockServiceMockRecorder) GetOrdersAssign(ctx, date, id interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersAssign", reflect.TypeOf((*MockService)(nil).GetOrdersAssign), ctx, date, id)
 }
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockService) GetWebhookDeliveries(ctx context.Context, id string, opts model.PaginationOpts) (*model.GetWebhookDeliveriesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, id, opts)
+	ret0, _ := ret[0].(*model.GetWebhookDeliveriesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockServiceMockRecorder) GetWebhookDeliveries(ctx, id, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockService)(nil).GetWebhookDeliveries), ctx, id, opts)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockService) GetWebhooks(ctx context.Context) (*model.GetWebhooksResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].(*model.GetWebhooksResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockServiceMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockService)(nil).GetWebhooks), ctx)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockService) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockServiceMockRecorder) ReplayWebhookDelivery(ctx, webhookID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockService)(nil).ReplayWebhookDelivery), ctx, webhookID, deliveryID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockService) RevokeAPIKey(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockServiceMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockService)(nil).RevokeAPIKey), ctx, id)
+}
+
+// UpdateCourier mocks base method.
+func (m *MockService) UpdateCourier(ctx context.Context, id string, req *model.UpdateCourierRequest) (*model.CourierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourier", ctx, id, req)
+	ret0, _ := ret[0].(*model.CourierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourier indicates an expected call of UpdateCourier.
+func (mr *MockServiceMockRecorder) UpdateCourier(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourier", reflect.TypeOf((*MockService)(nil).UpdateCourier), ctx, id, req)
+}
+
+// MockBroker is a mock of Broker interface.
+type MockBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerMockRecorder
+}
+
+// MockBrokerMockRecorder is the mock recorder for MockBroker.
+type MockBrokerMockRecorder struct {
+	mock *MockBroker
+}
+
+// NewMockBroker creates a new mock instance.
+func NewMockBroker(ctrl *gomock.Controller) *MockBroker {
+	mock := &MockBroker{ctrl: ctrl}
+	mock.recorder = &MockBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroker) EXPECT() *MockBrokerMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockBroker) Subscribe(ctx context.Context, req *model.SubscribeEventsRequest) (<-chan model.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, req)
+	ret0, _ := ret[0].(<-chan model.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockBrokerMockRecorder) Subscribe(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockBroker)(nil).Subscribe), ctx, req)
+}
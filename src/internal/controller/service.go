@@ -12,11 +12,30 @@ type Service interface {
 	GetCourierByID(ctx context.Context, id string) (*model.CourierDTO, error)
 	CreateCouriers(ctx context.Context, request *model.CreateCourierRequest) (*model.CouriersCreateResponse, error)
 	GetCouriers(ctx context.Context, opts model.PaginationOpts) (*model.GetCouriersResponse, error)
+	UpdateCourier(ctx context.Context, id string, req *model.UpdateCourierRequest) (*model.CourierDTO, error)
+	DeactivateCourier(ctx context.Context, id string) (*model.CourierDTO, error)
 	GetCourierMetaInfo(ctx context.Context, req *model.GetCourierMetaInfoRequest) (*model.GetCourierMetaInfoResponse, error)
 	GetOrdersAssign(ctx context.Context, date *datetime.Date, id string) (*model.OrderAssignResponse, error)
 	GetOrderByID(ctx context.Context, id string) (*model.OrderDTO, error)
-	GetOrders(ctx context.Context, opts model.PaginationOpts) ([]*model.OrderDTO, error)
+	GetOrders(ctx context.Context, opts model.PaginationOpts) (*model.GetOrdersResponse, error)
 	CreateOrders(ctx context.Context, req *model.CreateOrderRequest) ([]*model.OrderDTO, error)
 	CompleteOrders(ctx context.Context, req *model.CompleteOrderRequest) ([]*model.OrderDTO, error)
-	AssignOrders(ctx context.Context, date *datetime.Date) (*model.OrderAssignResponse, error)
+	CancelOrders(ctx context.Context, req *model.CancelOrderRequest) ([]*model.OrderDTO, error)
+	ChangeOrderStatus(ctx context.Context, id string, req *model.ChangeOrderStatusRequest) (*model.OrderDTO, error)
+	GetOrderHistory(ctx context.Context, id string) (*model.OrderHistoryResponse, error)
+	AssignOrders(ctx context.Context, date *datetime.Date, opts model.AssignOpts) (*model.OrderAssignResponse, error)
+	CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookDTO, error)
+	GetWebhooks(ctx context.Context) (*model.GetWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	GetWebhookDeliveries(ctx context.Context, id string, opts model.PaginationOpts) (*model.GetWebhookDeliveriesResponse, error)
+	ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*model.WebhookDelivery, error)
+	CreateAPIKey(ctx context.Context, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, key string) (*model.APIKey, error)
+}
+
+// Broker streams order events to subscribed clients.
+type Broker interface {
+	// Subscribe returns channel of events selected by request which is closed when ctx is done or subscription ends.
+	Subscribe(ctx context.Context, req *model.SubscribeEventsRequest) (<-chan model.Event, error)
 }
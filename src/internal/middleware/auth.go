@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"github.com/labstack/echo/v4"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/fielderr"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"net/http"
+	"strings"
+)
+
+// bearerPrefix is scheme of Authorization header with API key.
+const bearerPrefix = "Bearer "
+
+var (
+	// ErrNoAPIKey is returned when request has no API key in Authorization header.
+	ErrNoAPIKey = fielderr.New("api key is not provided", model.BadRequestResponse{}, fielderr.CodeUnauthorized)
+	// ErrForbidden is returned when role of API key does not allow request.
+	ErrForbidden = fielderr.New("role of api key does not allow request", model.BadRequestResponse{}, fielderr.CodeForbidden)
+)
+
+// AuthConfig is config of authentication middleware.
+type AuthConfig interface {
+	// AuthEnabled returns false if requests must not be authenticated at all.
+	AuthEnabled() bool
+	// AdminKey returns key with admin role which is not stored in storage, so the first keys can be issued with it.
+	// Empty key authenticates nothing.
+	AdminKey() string
+}
+
+// Authenticator returns API key which is not revoked.
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (*model.APIKey, error)
+}
+
+// bearer returns API key from Authorization header of request.
+func bearer(r *http.Request) string {
+	h := r.Header.Get(echo.HeaderAuthorization)
+	if len(h) < len(bearerPrefix) || !strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(bearerPrefix):])
+}
+
+// authError writes response of request which failed authentication or authorization.
+func authError(c echo.Context, err error) error {
+	var fieldErr *fielderr.Error
+	if !errors.As(err, &fieldErr) {
+		fieldErr = ErrNoAPIKey
+	}
+	if fieldErr.CodeHTTP() == http.StatusUnauthorized {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+	}
+	return c.JSON(fieldErr.CodeHTTP(), fieldErr.Data())
+}
+
+// Authorize authenticates request with API key from "Authorization: Bearer <key>" header and allows it only if key
+// has one of provided roles.
+//
+// Key which authenticated request is put into context of request, see auth.FromContext. If authentication is disabled
+// by config then every request is allowed and its context has no key.
+func Authorize(cfg AuthConfig, a Authenticator, roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !cfg.AuthEnabled() {
+				return next(c)
+			}
+
+			r := c.Request()
+			token := bearer(r)
+			if token == "" {
+				return authError(c, ErrNoAPIKey)
+			}
+			var key *model.APIKey
+			if admin := cfg.AdminKey(); admin != "" && subtle.ConstantTimeCompare([]byte(token), []byte(admin)) == 1 {
+				key = &model.APIKey{Role: model.RoleAdmin}
+			} else {
+				var err error
+				if key, err = a.Authenticate(r.Context(), token); err != nil {
+					return authError(c, err)
+				}
+			}
+
+			for _, role := range roles {
+				if key.Role == role {
+					c.SetRequest(r.WithContext(auth.WithKey(r.Context(), key)))
+					return next(c)
+				}
+			}
+			return authError(c, ErrForbidden)
+		}
+	}
+}
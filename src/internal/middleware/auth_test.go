@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/fielderr"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const adminKey = "0123456789abcdef0123456789abcdef"
+
+type authConfig struct {
+	disabled bool
+}
+
+func (c authConfig) AuthEnabled() bool { return !c.disabled }
+
+func (authConfig) AdminKey() string { return adminKey }
+
+// keys authenticates keys from map.
+type keys map[string]*model.APIKey
+
+var errUnavailable = fielderr.New("unavailable", model.BadRequestResponse{}, fielderr.CodeUnavailable)
+
+func (k keys) Authenticate(_ context.Context, key string) (*model.APIKey, error) {
+	if key == "broken" {
+		return nil, errUnavailable
+	}
+	res, ok := k[key]
+	if !ok {
+		return nil, fielderr.New("unknown key", model.BadRequestResponse{}, fielderr.CodeUnauthorized)
+	}
+	return res, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	courier := int64(1)
+	a := keys{
+		"dispatcher": {KeyID: 1, Role: model.RoleDispatcher},
+		"courier":    {KeyID: 2, Role: model.RoleCourier, CourierID: &courier},
+	}
+	tt := []struct {
+		name   string
+		cfg    authConfig
+		header string
+		code   int
+		key    *model.APIKey
+	}{
+		{"disabled", authConfig{disabled: true}, "", http.StatusOK, nil},
+		{"no header", authConfig{}, "", http.StatusUnauthorized, nil},
+		{"not bearer", authConfig{}, "Basic dispatcher", http.StatusUnauthorized, nil},
+		{"empty bearer", authConfig{}, "Bearer ", http.StatusUnauthorized, nil},
+		{"unknown key", authConfig{}, "Bearer unknown", http.StatusUnauthorized, nil},
+		{"storage failure", authConfig{}, "Bearer broken", http.StatusServiceUnavailable, nil},
+		{"forbidden role", authConfig{}, "Bearer courier", http.StatusForbidden, nil},
+		{"allowed role", authConfig{}, "Bearer dispatcher", http.StatusOK, a["dispatcher"]},
+		{"lower case scheme", authConfig{}, "bearer dispatcher", http.StatusOK, a["dispatcher"]},
+		{"admin key", authConfig{}, "Bearer " + adminKey, http.StatusOK, &model.APIKey{Role: model.RoleAdmin}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			h := Authorize(tc.cfg, a, model.RoleAdmin, model.RoleDispatcher)(func(c echo.Context) error {
+				key, ok := auth.FromContext(c.Request().Context())
+				assert.Equal(t, tc.key != nil, ok)
+				assert.Equal(t, tc.key, key)
+				return c.NoContent(http.StatusOK)
+			})
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				r.Header.Set(echo.HeaderAuthorization, tc.header)
+			}
+			w := httptest.NewRecorder()
+			require.NoError(t, h(echo.New().NewContext(r, w)))
+			assert.Equal(t, tc.code, w.Code)
+			if tc.code == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", w.Header().Get(echo.HeaderWWWAuthenticate))
+			}
+		})
+	}
+}
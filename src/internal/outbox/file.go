@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"os"
+	"sync"
+)
+
+// FileConfig configures file sink.
+type FileConfig interface {
+	// FilePath is path of file which events are appended to.
+	FilePath() string
+}
+
+// FileSink appends events to file as JSON lines, one event per line.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSink returns sink which appends events to file from config, file is created if it does not exist.
+func NewFileSink(cfg FileConfig) (*FileSink, error) {
+	if cfg == nil {
+		return nil, ErrNilReference
+	}
+	return &FileSink{path: cfg.FilePath()}, nil
+}
+
+// Send appends all events to file with one write and flushes file to disk.
+func (s *FileSink) Send(_ context.Context, events []model.Event) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return fmt.Errorf("json: encode event %d: %w", events[i].ID, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", s.path, err)
+	}
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write %s: %w", s.path, err)
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync %s: %w", s.path, err)
+	}
+	return f.Close()
+}
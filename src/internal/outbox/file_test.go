@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fileConfig string
+
+func (c fileConfig) FilePath() string {
+	return string(c)
+}
+
+func TestNewFileSink_Negative(t *testing.T) {
+	s, err := NewFileSink(nil)
+	assert.ErrorIs(t, err, ErrNilReference)
+	assert.Nil(t, s)
+}
+
+func TestFileSink_Send(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	s, err := NewFileSink(fileConfig(path))
+	require.NoError(t, err)
+
+	events := []model.Event{
+		{ID: 1, Type: model.EventCourierCreated, Payload: json.RawMessage(`{"courier_id":1}`)},
+		{ID: 2, Type: model.EventOrderCreated, Payload: json.RawMessage(`{"order_id":1}`)},
+	}
+	require.NoError(t, s.Send(ctx, events[:1]))
+	require.NoError(t, s.Send(ctx, events[1:]))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, f.Close())
+	}()
+	var got []model.Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e model.Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &e))
+		got = append(got, e)
+	}
+	require.NoError(t, sc.Err())
+	if assert.Len(t, got, 2) {
+		for i := range events {
+			assert.Equal(t, events[i].ID, got[i].ID)
+			assert.Equal(t, events[i].Type, got[i].Type)
+			assert.JSONEq(t, string(events[i].Payload), string(got[i].Payload))
+		}
+	}
+}
+
+func TestFileSink_Send_Negative(t *testing.T) {
+	s, err := NewFileSink(fileConfig(filepath.Join(t.TempDir(), "missing", "events.jsonl")))
+	require.NoError(t, err)
+	assert.Error(t, s.Send(context.Background(), []model.Event{{ID: 1, Payload: json.RawMessage(`{}`)}}))
+}
@@ -0,0 +1,133 @@
+// Package outbox delivers domain events which storage writes into outbox to other systems.
+//
+// Storage writes events in transaction of change which caused them, Relay reads pending events in order of ids, sends
+// them to Sink and marks them delivered after sink accepted them. Delivery is at least once: if relay stops between
+// sending and marking, events are sent again, so consumers must drop events with ids which they already got.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrNilReference = errors.New("unexpectedly got nil reference in outbox")
+
+type (
+	// Store is storage with outbox of events.
+	Store interface {
+		// GetPendingEvents returns at most limit events which are not delivered yet ordered by id.
+		GetPendingEvents(ctx context.Context, limit int) ([]model.Event, error)
+		// MarkEventsDelivered marks events with provided ids as delivered.
+		MarkEventsDelivered(ctx context.Context, ids []int64) error
+	}
+	// Sink is destination of events.
+	Sink interface {
+		// Send returns nil only if sink accepted all events.
+		Send(ctx context.Context, events []model.Event) error
+	}
+	// Config configures relay.
+	Config interface {
+		// PollInterval is delay between checks of outbox when there are no pending events or delivery failed.
+		PollInterval() time.Duration
+		// BatchSize is maximum number of events which are sent to sink at once.
+		BatchSize() int
+	}
+)
+
+// Relay delivers pending events from outbox to sink in background.
+type Relay struct {
+	store  Store
+	sink   Sink
+	cfg    Config
+	log    *zap.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRelay returns relay of events from store to sink.
+func NewRelay(logger *zap.Logger, cfg Config, store Store, sink Sink) (*Relay, error) {
+	if logger == nil || cfg == nil || store == nil || sink == nil {
+		return nil, ErrNilReference
+	}
+	return &Relay{
+		store: store,
+		sink:  sink,
+		cfg:   cfg,
+		log:   logger,
+	}, nil
+}
+
+// Deliver sends one batch of pending events to sink and marks them delivered.
+//
+// It returns number of delivered events.
+func (r *Relay) Deliver(ctx context.Context) (int, error) {
+	events, err := r.store.GetPendingEvents(ctx, r.cfg.BatchSize())
+	if err != nil {
+		return 0, fmt.Errorf("get pending events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+	if err = r.sink.Send(ctx, events); err != nil {
+		return 0, fmt.Errorf("send events: %w", err)
+	}
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	if err = r.store.MarkEventsDelivered(ctx, ids); err != nil {
+		return 0, fmt.Errorf("mark events delivered: %w", err)
+	}
+	return len(events), nil
+}
+
+// run delivers events until ctx is done.
+//
+// Full batch means that outbox may have more pending events, so the next batch is delivered without delay.
+func (r *Relay) run(ctx context.Context) {
+	defer close(r.done)
+
+	for {
+		n, err := r.Deliver(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.log.Error("unable to deliver events", zap.Error(err))
+		}
+		if err == nil && n > 0 && n == r.cfg.BatchSize() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.cfg.PollInterval()):
+		}
+	}
+}
+
+// Start starts delivery of events in background.
+func (r *Relay) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	go r.run(ctx)
+	r.log.Info("starting outbox relay")
+	return nil
+}
+
+// Stop stops delivery of events and waits until batch which is being delivered is done.
+func (r *Relay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.log.Info("stopping outbox relay")
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
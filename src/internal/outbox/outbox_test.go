@@ -0,0 +1,168 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/memory"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"sync"
+	"testing"
+	"time"
+)
+
+type config struct {
+	batch int
+}
+
+func (c *config) PollInterval() time.Duration {
+	return time.Millisecond
+}
+
+func (c *config) BatchSize() int {
+	return c.batch
+}
+
+// sink remembers sent events and fails while err is set.
+type sink struct {
+	mu     sync.Mutex
+	err    error
+	events []model.Event
+}
+
+func (s *sink) Send(_ context.Context, events []model.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *sink) types() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]string, 0, len(s.events))
+	for _, e := range s.events {
+		res = append(res, e.Type)
+	}
+	return res
+}
+
+func hours() []*datetime.TimeInterval {
+	return []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 720}.TimeInterval()}
+}
+
+// seed creates courier and two orders, so outbox has three pending events.
+func seed(t *testing.T, s *memory.Store) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{{CourierType: "FOOT", Regions: []int32{1}, WorkingHours: hours()}})
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, []*model.OrderDTO{
+		{Weight: 1, Regions: 1, Cost: 1, DeliveryHours: hours()},
+		{Weight: 1, Regions: 1, Cost: 1, DeliveryHours: hours()},
+	}))
+}
+
+func TestNewRelay(t *testing.T) {
+	r, err := NewRelay(zap.L(), &config{batch: 1}, memory.New(), &sink{})
+	assert.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestNewRelay_Negative(t *testing.T) {
+	tt := []struct {
+		name  string
+		log   *zap.Logger
+		cfg   Config
+		store Store
+		sink  Sink
+	}{
+		{"nil logger", nil, &config{}, memory.New(), &sink{}},
+		{"nil config", zap.L(), nil, memory.New(), &sink{}},
+		{"nil store", zap.L(), &config{}, nil, &sink{}},
+		{"nil sink", zap.L(), &config{}, memory.New(), nil},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewRelay(tc.log, tc.cfg, tc.store, tc.sink)
+			assert.ErrorIs(t, err, ErrNilReference)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestRelay_Deliver(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	seed(t, s)
+	snk := &sink{}
+	r, err := NewRelay(zap.L(), &config{batch: 2}, s, snk)
+	require.NoError(t, err)
+
+	n, err := r.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = r.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = r.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	assert.Equal(t, []string{model.EventCourierCreated, model.EventOrderCreated, model.EventOrderCreated}, snk.types())
+	for i, e := range snk.events {
+		assert.Equal(t, int64(i+1), e.ID)
+	}
+}
+
+func TestRelay_Deliver_SinkFailed(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	seed(t, s)
+	snk := &sink{err: errors.New("unavailable")}
+	r, err := NewRelay(zap.L(), &config{batch: 10}, s, snk)
+	require.NoError(t, err)
+
+	n, err := r.Deliver(ctx)
+	assert.ErrorIs(t, err, snk.err)
+	assert.Equal(t, 0, n)
+
+	pending, err := s.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+
+	snk.err = nil
+	n, err = r.Deliver(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	pending, err = s.GetPendingEvents(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestRelay_StartStop(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	seed(t, s)
+	snk := &sink{}
+	r, err := NewRelay(zap.L(), &config{batch: 1}, s, snk)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(ctx))
+	assert.Eventually(t, func() bool {
+		return len(snk.types()) == 3
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, r.Stop(ctx))
+}
+
+func TestRelay_Stop_NotStarted(t *testing.T) {
+	r, err := NewRelay(zap.L(), &config{batch: 1}, memory.New(), &sink{})
+	require.NoError(t, err)
+	assert.NoError(t, r.Stop(context.Background()))
+}
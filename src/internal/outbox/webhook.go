@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/retryer"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrRejected is returned when webhook responded with status other than 2xx.
+var ErrRejected = errors.New("webhook rejected events")
+
+// WebhookConfig configures webhook sink.
+type WebhookConfig interface {
+	// WebhookURL is URL which events are posted to.
+	WebhookURL() string
+	// WebhookAttempts is number of attempts to post batch of events.
+	WebhookAttempts() uint
+	// WebhookDelay is delay between attempts.
+	WebhookDelay() time.Duration
+	// WebhookTimeout is timeout of one attempt.
+	WebhookTimeout() time.Duration
+}
+
+// WebhookSink posts batches of events to URL as JSON array.
+//
+// Batch is accepted when webhook responds with 2xx status, otherwise it is posted again up to configured number of
+// attempts.
+type WebhookSink struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// NewWebhookSink returns sink which posts events to webhook from config.
+func NewWebhookSink(cfg WebhookConfig) (*WebhookSink, error) {
+	if cfg == nil {
+		return nil, ErrNilReference
+	}
+	return &WebhookSink{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.WebhookTimeout()},
+	}, nil
+}
+
+// Send posts events to webhook with retries.
+func (s *WebhookSink) Send(ctx context.Context, events []model.Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("json: marshal events: %w", err)
+	}
+	return retryer.TryWithAttemptsCtx(ctx, func(ctx context.Context) error {
+		return s.post(ctx, body)
+	}, s.cfg.WebhookAttempts(), s.cfg.WebhookDelay())
+}
+
+// post makes one attempt to post body to webhook.
+func (s *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.WebhookURL(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post events: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status %d", ErrRejected, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type webhookConfig struct {
+	url      string
+	attempts uint
+}
+
+func (c *webhookConfig) WebhookURL() string {
+	return c.url
+}
+
+func (c *webhookConfig) WebhookAttempts() uint {
+	return c.attempts
+}
+
+func (c *webhookConfig) WebhookDelay() time.Duration {
+	return time.Millisecond
+}
+
+func (c *webhookConfig) WebhookTimeout() time.Duration {
+	return time.Second
+}
+
+func TestNewWebhookSink_Negative(t *testing.T) {
+	s, err := NewWebhookSink(nil)
+	assert.ErrorIs(t, err, ErrNilReference)
+	assert.Nil(t, s)
+}
+
+func TestWebhookSink_Send(t *testing.T) {
+	var (
+		calls atomic.Int32
+		got   []model.Event
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s, err := NewWebhookSink(&webhookConfig{url: srv.URL, attempts: 3})
+	require.NoError(t, err)
+	events := []model.Event{{ID: 1, Type: model.EventOrderCreated, Payload: json.RawMessage(`{"order_id":1}`)}}
+	assert.NoError(t, s.Send(context.Background(), events))
+	assert.Equal(t, int32(2), calls.Load())
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, int64(1), got[0].ID)
+		assert.Equal(t, model.EventOrderCreated, got[0].Type)
+	}
+}
+
+func TestWebhookSink_Send_Rejected(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s, err := NewWebhookSink(&webhookConfig{url: srv.URL, attempts: 2})
+	require.NoError(t, err)
+	err = s.Send(context.Background(), []model.Event{{ID: 1, Payload: json.RawMessage(`{}`)}})
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWebhookSink_Send_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	s, err := NewWebhookSink(&webhookConfig{url: srv.URL, attempts: 1})
+	require.NoError(t, err)
+	assert.Error(t, s.Send(context.Background(), []model.Event{{ID: 1, Payload: json.RawMessage(`{}`)}}))
+}
@@ -23,6 +23,7 @@ var (
 			WorkingHours: []*datetime.TimeInterval{
 				timeInterval1,
 			},
+			Active: true,
 		},
 		{
 			CourierID:   2,
@@ -31,6 +32,7 @@ var (
 			WorkingHours: []*datetime.TimeInterval{
 				timeInterval2,
 			},
+			Active: true,
 		},
 		{
 			CourierID:   3,
@@ -39,6 +41,7 @@ var (
 			WorkingHours: []*datetime.TimeInterval{
 				timeInterval3,
 			},
+			Active: true,
 		},
 	}
 	couriersMetaInfo = []*model.GetCourierMetaInfoResponse{
@@ -84,6 +87,7 @@ var (
 			},
 			Cost:          rand.Int31(),
 			CompletedTime: datetime.Time(time.Now()),
+			Status:        model.OrderStatusCompleted,
 		},
 		{
 			OrderID: 2,
@@ -95,6 +99,7 @@ var (
 			},
 			Cost:          rand.Int31(),
 			CompletedTime: datetime.Time(time.Now()),
+			Status:        model.OrderStatusCompleted,
 		},
 		{
 			OrderID: 3,
@@ -106,6 +111,7 @@ var (
 			},
 			Cost:          rand.Int31(),
 			CompletedTime: datetime.Time(time.Now()),
+			Status:        model.OrderStatusCompleted,
 		},
 	}
 )
@@ -135,6 +141,29 @@ func (service) GetCouriers(_ context.Context, opts model.PaginationOpts) (res *m
 	return
 }
 
+func (service) UpdateCourier(_ context.Context, _ string, req *model.UpdateCourierRequest) (*model.CourierDTO, error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	courier := couriers[rand.Int()%len(couriers)]
+	if req.CourierType != nil {
+		courier.CourierType = *req.CourierType
+	}
+	if req.Regions != nil {
+		courier.Regions = req.Regions
+	}
+	if req.WorkingHours != nil {
+		courier.WorkingHours = req.WorkingHours
+	}
+	return &courier, nil
+}
+
+func (service) DeactivateCourier(context.Context, string) (*model.CourierDTO, error) {
+	courier := couriers[rand.Int()%len(couriers)]
+	courier.Active = false
+	return &courier, nil
+}
+
 func (service) GetCourierMetaInfo(context.Context, *model.GetCourierMetaInfoRequest) (*model.GetCourierMetaInfoResponse, error) {
 	idx := rand.Int() % len(couriersMetaInfo)
 	return couriersMetaInfo[idx], nil
@@ -165,15 +194,17 @@ func (service) GetOrderByID(context.Context, string) (*model.OrderDTO, error) {
 	return &orders[rand.Int()%len(orders)], nil
 }
 
-func (service) GetOrders(context.Context, model.PaginationOpts) (res []*model.OrderDTO, err error) {
+func (service) GetOrders(context.Context, model.PaginationOpts) (res *model.GetOrdersResponse, err error) {
+	res = new(model.GetOrdersResponse)
 	for _, i := range orders {
-		res = append(res, &model.OrderDTO{
+		res.Orders = append(res.Orders, &model.OrderDTO{
 			OrderID:       i.OrderID,
 			Weight:        i.Weight,
 			Regions:       i.Regions,
 			DeliveryHours: i.DeliveryHours,
 			Cost:          i.Cost,
 			CompletedTime: i.CompletedTime,
+			Status:        i.Status,
 		})
 	}
 	return
@@ -190,7 +221,7 @@ func (service) CreateOrders(_ context.Context, req *model.CreateOrderRequest) (r
 			Regions:       o.Regions,
 			DeliveryHours: o.DeliveryHours,
 			Cost:          o.Cost,
-			CompletedTime: datetime.Time(time.Now()),
+			Status:        model.OrderStatusCreated,
 		})
 	}
 	return
@@ -210,12 +241,55 @@ func (service) CompleteOrders(_ context.Context, req *model.CompleteOrderRequest
 			},
 			Cost:          rand.Int31(),
 			CompletedTime: completeOrder.CompleteTime,
+			Status:        model.OrderStatusCompleted,
 		})
 	}
 	return
 }
 
-func (service) AssignOrders(_ context.Context, date *datetime.Date) (*model.OrderAssignResponse, error) {
+func (service) CancelOrders(_ context.Context, req *model.CancelOrderRequest) (res []*model.OrderDTO, err error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	for _, id := range req.OrderIDs {
+		res = append(res, &model.OrderDTO{
+			OrderID: id,
+			Weight:  rand.Float64(),
+			Regions: rand.Int31(),
+			DeliveryHours: []*datetime.TimeInterval{
+				timeInterval3,
+			},
+			Cost:         rand.Int31(),
+			Status:       model.OrderStatusCancelled,
+			CancelReason: req.Reason,
+		})
+	}
+	return
+}
+
+func (service) ChangeOrderStatus(_ context.Context, _ string, req *model.ChangeOrderStatusRequest) (*model.OrderDTO, error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	order := orders[rand.Int()%len(orders)]
+	order.CompletedTime = datetime.Time{}
+	order.Status = req.Status
+	return &order, nil
+}
+
+func (service) GetOrderHistory(context.Context, string) (*model.OrderHistoryResponse, error) {
+	order := orders[rand.Int()%len(orders)]
+	return &model.OrderHistoryResponse{
+		OrderID: order.OrderID,
+		History: []model.OrderStatusChange{
+			{Status: model.OrderStatusCreated, Actor: model.ActorAPI, ChangedAt: datetime.Time(time.Now().Add(-time.Hour))},
+			{Status: model.OrderStatusAssigned, Actor: model.ActorAssignment, ChangedAt: datetime.Time(time.Now().Add(-time.Hour / 2))},
+			{Status: model.OrderStatusCompleted, Actor: model.CourierActor(1), ChangedAt: order.CompletedTime},
+		},
+	}, nil
+}
+
+func (service) AssignOrders(_ context.Context, date *datetime.Date, _ model.AssignOpts) (*model.OrderAssignResponse, error) {
 	return &model.OrderAssignResponse{
 		Date: date.String(),
 		Couriers: []model.CourierGroupOrders{
@@ -235,3 +309,94 @@ func (service) AssignOrders(_ context.Context, date *datetime.Date) (*model.Orde
 		},
 	}, nil
 }
+
+func (service) CreateWebhook(_ context.Context, req *model.CreateWebhookRequest) (*model.WebhookDTO, error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = "4f1c2a9e0b7d4e3f8a6c5b2d1e0f9a8b"
+	}
+	return &model.WebhookDTO{
+		WebhookID:  rand.Int63(),
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		CreatedAt:  datetime.Time(time.Now()),
+	}, nil
+}
+
+func (service) GetWebhooks(context.Context) (*model.GetWebhooksResponse, error) {
+	return &model.GetWebhooksResponse{
+		Webhooks: []model.WebhookDTO{
+			{
+				WebhookID:  1,
+				URL:        "https://partner.example/lavka/events",
+				EventTypes: []string{model.EventOrderCreated, model.EventOrderCompleted},
+				CreatedAt:  datetime.Time(time.Now().Add(-time.Hour)),
+			},
+		},
+	}, nil
+}
+
+func (service) DeleteWebhook(context.Context, string) error {
+	return nil
+}
+
+func (service) GetWebhookDeliveries(_ context.Context, _ string, opts model.PaginationOpts) (*model.GetWebhookDeliveriesResponse, error) {
+	delivered := datetime.Time(time.Now())
+	return &model.GetWebhookDeliveriesResponse{
+		Deliveries: []model.WebhookDelivery{
+			{
+				DeliveryID:     1,
+				WebhookID:      1,
+				EventID:        1,
+				EventType:      model.EventOrderCreated,
+				Status:         model.DeliveryStatusDelivered,
+				Attempts:       1,
+				LastStatusCode: 200,
+				DeliveredAt:    &delivered,
+				CreatedAt:      delivered,
+			},
+		},
+		Limit:  opts.Limit(),
+		Offset: opts.Offset(),
+	}, nil
+}
+
+func (service) ReplayWebhookDelivery(context.Context, string, string) (*model.WebhookDelivery, error) {
+	next := datetime.Time(time.Now())
+	return &model.WebhookDelivery{
+		DeliveryID:    rand.Int63(),
+		WebhookID:     1,
+		EventID:       1,
+		EventType:     model.EventOrderCreated,
+		Status:        model.DeliveryStatusPending,
+		NextAttemptAt: &next,
+		CreatedAt:     next,
+	}, nil
+}
+
+func (service) CreateAPIKey(_ context.Context, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	return &model.CreateAPIKeyResponse{
+		APIKey: model.APIKey{
+			KeyID:     rand.Int63(),
+			Role:      req.Role,
+			CourierID: req.CourierID,
+			CreatedAt: datetime.Time(time.Now()),
+		},
+		Key: "9b2e4c6a8d0f1e3a5c7b9d1f2a4c6e8b0d2f4a6c8e0b2d4f6a8c0e2b4d6f8a0c",
+	}, nil
+}
+
+func (service) RevokeAPIKey(context.Context, string) error {
+	return nil
+}
+
+func (service) Authenticate(context.Context, string) (*model.APIKey, error) {
+	return &model.APIKey{Role: model.RoleAdmin}, nil
+}
@@ -0,0 +1,63 @@
+package production
+
+import (
+	"context"
+	"errors"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// CreateAPIKey issues API key with role from request.
+//
+// Only hash of key is stored, so key is returned only by this method.
+func (srv *Service) CreateAPIKey(ctx context.Context, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	if !req.Valid() {
+		srv.log.Debug("request didn't pass validation")
+		return nil, ErrBadRequest
+	}
+
+	key, err := auth.NewKey()
+	if err != nil {
+		return nil, ErrInternal.With(zap.NamedError("key_error", err))
+	}
+	resp := &model.CreateAPIKeyResponse{
+		APIKey: model.APIKey{Role: req.Role, CourierID: req.CourierID},
+		Key:    key,
+	}
+	if err = srv.storage.CreateAPIKey(ctx, &resp.APIKey, auth.Hash(key)); err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
+	srv.log.Info("api key issued", zap.Int64("key_id", resp.KeyID), zap.String("role", resp.Role))
+	return resp, nil
+}
+
+// RevokeAPIKey revokes API key with provided id, so it does not authenticate requests anymore.
+func (srv *Service) RevokeAPIKey(ctx context.Context, id string) error {
+	keyID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrBadRequest.With(zap.String("key_id", id))
+	}
+	if err = srv.storage.RevokeAPIKey(ctx, keyID); err != nil {
+		return storeError(err, ErrNotFound)
+	}
+	srv.log.Info("api key revoked", zap.Int64("key_id", keyID))
+	return nil
+}
+
+// Authenticate returns API key which is not revoked.
+func (srv *Service) Authenticate(ctx context.Context, key string) (*model.APIKey, error) {
+	if key == "" {
+		return nil, ErrUnauthorized
+	}
+	res, err := srv.storage.GetAPIKeyByHash(ctx, auth.Hash(key))
+	if err != nil {
+		if errors.Is(err, store.ErrDoesNotExists) {
+			return nil, ErrUnauthorized
+		}
+		return nil, storeError(err, ErrUnauthorized)
+	}
+	return res, nil
+}
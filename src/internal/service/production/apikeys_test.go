@@ -0,0 +1,110 @@
+package production
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production/mocks"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestService_CreateAPIKey(t *testing.T) {
+	courier := int64(1)
+	req := &model.CreateAPIKeyRequest{Role: model.RoleCourier, CourierID: &courier}
+	t.Run("bad request", func(t *testing.T) {
+		resp, err := testService(t, nil).CreateAPIKey(context.Background(), &model.CreateAPIKeyRequest{Role: model.RoleCourier})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("unknown courier", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("courier 1: %w", store.ErrForeignKeyViolation))
+
+		resp, err := testService(t, str).CreateAPIKey(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		var hash []byte
+		str.EXPECT().CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key *model.APIKey, h []byte) error {
+				key.KeyID, hash = 2, h
+				return nil
+			})
+
+		resp, err := testService(t, str).CreateAPIKey(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.KeyID)
+		assert.Equal(t, model.RoleCourier, resp.Role)
+		assert.Equal(t, &courier, resp.CourierID)
+		_, err = hex.DecodeString(resp.Key)
+		assert.NoError(t, err)
+		assert.Equal(t, auth.Hash(resp.Key), hash, "only hash of key must be stored")
+	})
+}
+
+func TestService_RevokeAPIKey(t *testing.T) {
+	t.Run("bad id", func(t *testing.T) {
+		assert.ErrorIs(t, testService(t, nil).RevokeAPIKey(context.Background(), "x"), ErrBadRequest)
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().RevokeAPIKey(gomock.Any(), int64(1)).Return(fmt.Errorf("api key 1: %w", store.ErrDoesNotExists))
+
+		assert.ErrorIs(t, testService(t, str).RevokeAPIKey(context.Background(), "1"), ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().RevokeAPIKey(gomock.Any(), int64(1)).Return(nil)
+
+		assert.NoError(t, testService(t, str).RevokeAPIKey(context.Background(), "1"))
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	t.Run("no key", func(t *testing.T) {
+		key, err := testService(t, nil).Authenticate(context.Background(), "")
+		assert.Nil(t, key)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetAPIKeyByHash(gomock.Any(), auth.Hash("key")).Return(nil, fmt.Errorf("api key: %w", store.ErrDoesNotExists))
+
+		key, err := testService(t, str).Authenticate(context.Background(), "key")
+		assert.Nil(t, key)
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+	t.Run("storage failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("conn: %w", store.ErrUnavailable))
+
+		key, err := testService(t, str).Authenticate(context.Background(), "key")
+		assert.Nil(t, key)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		want := &model.APIKey{KeyID: 1, Role: model.RoleDispatcher}
+		str.EXPECT().GetAPIKeyByHash(gomock.Any(), auth.Hash("key")).Return(want, nil)
+
+		key, err := testService(t, str).Authenticate(context.Background(), "key")
+		require.NoError(t, err)
+		assert.Equal(t, want, key)
+	})
+}
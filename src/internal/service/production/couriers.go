@@ -24,7 +24,7 @@ func (srv *Service) GetCourierByID(ctx context.Context, id string) (courier *mod
 
 	courier, err = srv.storage.GetCourierByID(ctx, courierID)
 	if err != nil {
-		return nil, ErrNotFound
+		return nil, storeError(err, ErrNotFound)
 	}
 
 	return courier, nil
@@ -41,7 +41,7 @@ func (srv *Service) CreateCouriers(ctx context.Context, req *model.CreateCourier
 	var couriers []model.CourierDTO
 	couriers, err = srv.storage.CreateCouriers(ctx, req.Couriers)
 	if err != nil {
-		return nil, ErrBadRequest.With(zap.NamedError("store_error", err))
+		return nil, storeError(err, ErrBadRequest)
 	}
 	return &model.CouriersCreateResponse{Couriers: couriers}, nil
 }
@@ -49,23 +49,84 @@ func (srv *Service) CreateCouriers(ctx context.Context, req *model.CreateCourier
 // GetCouriers return couriers with pagination options.
 //
 // If there are no couriers found by pagination opts then will be returned
-// empty slice of couriers.
+// empty slice of couriers. Page can be selected either by offset or by cursor from previous page.
 func (srv *Service) GetCouriers(ctx context.Context, opts model.PaginationOpts) (*model.GetCouriersResponse, error) {
 	if opts == nil {
 		return nil, ErrBadRequest
 	}
-	couriers, err := srv.storage.GetCouriers(ctx, opts.Limit(), opts.Offset())
+	afterID, byCursor, err := cursor(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var couriers []model.CourierDTO
+	if byCursor {
+		couriers, err = srv.storage.GetCouriersAfter(ctx, afterID, opts.Limit())
+	} else {
+		couriers, err = srv.storage.GetCouriers(ctx, opts.Limit(), opts.Offset())
+	}
 	if err != nil {
 		if !errors.Is(err, store.ErrNoContent) {
-			return nil, ErrBadRequest
+			return nil, storeError(err, ErrBadRequest)
 		}
 		couriers = []model.CourierDTO{}
 	}
-	return &model.GetCouriersResponse{
+
+	resp := &model.GetCouriersResponse{
 		Couriers: couriers,
 		Limit:    opts.Limit(),
 		Offset:   opts.Offset(),
-	}, nil
+	}
+	if len(couriers) > 0 {
+		resp.NextCursor = model.NextCursor(len(couriers), opts.Limit(), couriers[len(couriers)-1].CourierID)
+	}
+	return resp, nil
+}
+
+// UpdateCourier changes fields of courier which are provided in request and returns updated courier.
+//
+// Provided regions and working hours replace stored ones.
+func (srv *Service) UpdateCourier(ctx context.Context, id string, req *model.UpdateCourierRequest) (*model.CourierDTO, error) {
+	courierID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.NamedError("strconv_error", err))
+	}
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+
+	courier, err := srv.storage.UpdateCourier(ctx, courierID, req)
+	if err != nil {
+		if errors.Is(err, store.ErrDoesNotExists) {
+			return nil, ErrNotFound.With(zap.Int64("courier_id", courierID))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+	return courier, nil
+}
+
+// DeactivateCourier deactivates courier and returns it.
+//
+// Deactivated courier is not listed and does not get orders in next assignments, but it can still be got by id and
+// complete orders which it already has.
+func (srv *Service) DeactivateCourier(ctx context.Context, id string) (*model.CourierDTO, error) {
+	courierID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.NamedError("strconv_error", err))
+	}
+
+	if err = srv.storage.DeactivateCourier(ctx, courierID); err != nil {
+		if errors.Is(err, store.ErrDoesNotExists) {
+			return nil, ErrNotFound.With(zap.Int64("courier_id", courierID))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+
+	var courier *model.CourierDTO
+	if courier, err = srv.storage.GetCourierByID(ctx, courierID); err != nil {
+		return nil, storeError(err, ErrNotFound)
+	}
+	return courier, nil
 }
 
 // GetCourierMetaInfo return meta info of courier.
@@ -98,7 +159,7 @@ func (srv *Service) GetCourierMetaInfo(ctx context.Context, req *model.GetCourie
 
 	courier, err = srv.storage.GetCourierByID(ctx, req.CourierID)
 	if err != nil {
-		return nil, ErrNoContent.WithData(resp).With(zap.NamedError("storage_error", err))
+		return nil, storeError(err, ErrNoContent.WithData(resp))
 	}
 
 	resp.Regions = courier.Regions
@@ -107,7 +168,7 @@ func (srv *Service) GetCourierMetaInfo(ctx context.Context, req *model.GetCourie
 
 	resp.Earnings, count, err = srv.storage.GetCompletedOrdersPriceByCourier(ctx, courier.CourierID, start.Start(), end.End())
 	if err != nil {
-		return nil, ErrNoContent.WithData(resp).With(zap.NamedError("storage_error", err))
+		return nil, storeError(err, ErrNoContent.WithData(resp))
 	}
 	resp.Rating = int32((float64(count) / end.Start().Sub(start.Start()).Hours()) * float64(courier.RatingConst()))
 	resp.Earnings *= courier.EarningsConst()
@@ -3,6 +3,7 @@ package production
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -200,6 +201,16 @@ func TestService_CreateCouriers_Negative_StorageError(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
+func TestService_GetCourierByID_Negative_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	str.EXPECT().GetCourierByID(gomock.Any(), int64(1)).Return(nil, &store.Error{Kind: store.ErrUnavailable})
+
+	resp, err := testService(t, str).GetCourierByID(context.Background(), "1")
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
 func TestService_GetCouriers_NilOpts(t *testing.T) {
 	srv := testService(t, nil)
 	resp, err := srv.GetCouriers(context.Background(), nil)
@@ -263,6 +274,120 @@ func TestService_GetCouriers_Positive(t *testing.T) {
 	}
 }
 
+func TestService_GetCouriers_Positive_Cursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	srv := testService(t, str)
+
+	couriers := []model.CourierDTO{{CourierID: 3}, {CourierID: 4}}
+	str.EXPECT().GetCouriersAfter(gomock.Any(), int64(2), 2).Return(couriers, nil)
+
+	want := &model.GetCouriersResponse{
+		Couriers:   couriers,
+		Limit:      2,
+		NextCursor: model.EncodeCursor(4),
+	}
+
+	resp, err := srv.GetCouriers(context.Background(), http.NewPaginationOpts("2", "").WithCursor(model.EncodeCursor(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, want, resp)
+}
+
+func TestService_GetCouriers_Negative_BadCursor(t *testing.T) {
+	srv := testService(t, nil)
+	for name, opts := range map[string]*http.PaginationOpts{
+		"malformed":   http.NewPaginationOpts("", "").WithCursor("!"),
+		"with offset": http.NewPaginationOpts("", "1").WithCursor(model.EncodeCursor(5)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := srv.GetCouriers(context.Background(), opts)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadRequest)
+		})
+	}
+}
+
+func TestService_UpdateCourier(t *testing.T) {
+	bike := model.BikeCourierTypeString
+	req := &model.UpdateCourierRequest{CourierType: &bike}
+	tt := []struct {
+		name  string
+		id    string
+		req   *model.UpdateCourierRequest
+		store error
+		want  error
+	}{
+		{"bad id", "id", req, nil, ErrBadRequest},
+		{"bad request", "1", &model.UpdateCourierRequest{}, nil, ErrBadRequest},
+		{"not found", "1", req, fmt.Errorf("courier 1: %w", store.ErrDoesNotExists), ErrNotFound},
+		{"check violation", "1", req, &store.Error{Kind: store.ErrCheckViolation}, ErrBadRequest},
+		{"unavailable", "1", req, &store.Error{Kind: store.ErrUnavailable}, ErrUnavailable},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			if tc.store != nil {
+				str.EXPECT().UpdateCourier(gomock.Any(), int64(1), tc.req).Return(nil, tc.store)
+			}
+
+			resp, err := testService(t, str).UpdateCourier(context.Background(), tc.id, tc.req)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		want := &model.CourierDTO{CourierID: 1, CourierType: bike, Active: true}
+		str.EXPECT().UpdateCourier(gomock.Any(), int64(1), req).Return(want, nil)
+
+		resp, err := testService(t, str).UpdateCourier(context.Background(), "1", req)
+		assert.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+}
+
+func TestService_DeactivateCourier(t *testing.T) {
+	t.Run("bad id", func(t *testing.T) {
+		resp, err := testService(t, nil).DeactivateCourier(context.Background(), "id")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().DeactivateCourier(gomock.Any(), int64(1)).Return(fmt.Errorf("courier 1: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).DeactivateCourier(context.Background(), "1")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().DeactivateCourier(gomock.Any(), int64(1)).Return(&store.Error{Kind: store.ErrUnavailable})
+
+		resp, err := testService(t, str).DeactivateCourier(context.Background(), "1")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		want := &model.CourierDTO{CourierID: 1, CourierType: model.FootCourierTypeString}
+		gomock.InOrder(
+			str.EXPECT().DeactivateCourier(gomock.Any(), int64(1)).Return(nil),
+			str.EXPECT().GetCourierByID(gomock.Any(), int64(1)).Return(want, nil),
+		)
+
+		resp, err := testService(t, str).DeactivateCourier(context.Background(), "1")
+		assert.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+}
+
 func TestService_GetCourierMetaInfo_Negative_NilReq(t *testing.T) {
 	srv := testService(t, nil)
 	resp, err := srv.GetCourierMetaInfo(context.Background(), nil)
@@ -2,8 +2,10 @@ package production
 
 import (
 	"errors"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/fielderr"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
 )
 
 var (
@@ -12,4 +14,38 @@ var (
 	ErrBadRequest     = fielderr.New("bad request", model.BadRequestResponse{}, fielderr.CodeBadRequest)
 	ErrNotFound       = fielderr.New("not found", model.BadRequestResponse{}, fielderr.CodeNotFound)
 	ErrNoContent      = fielderr.New("no content to return", model.GetCourierMetaInfoResponse{}, fielderr.CodeOK)
+	ErrNotAssigned    = fielderr.New("orders were not assigned at date", model.BadRequestResponse{}, fielderr.CodeNotFound)
+	ErrAssignConflict = fielderr.New("orders were concurrently assigned", model.BadRequestResponse{}, fielderr.CodeConflict)
+	ErrNotToday       = fielderr.New("orders can be distributed only for today", model.BadRequestResponse{}, fielderr.CodeBadRequest)
+	ErrConflict       = fielderr.New("conflict", model.BadRequestResponse{}, fielderr.CodeConflict)
+	ErrBadTransition  = fielderr.New("order can not get status from its current status", model.BadRequestResponse{}, fielderr.CodeConflict)
+	ErrUnavailable    = fielderr.New("storage is unavailable", model.BadRequestResponse{}, fielderr.CodeUnavailable)
+	ErrInternal       = fielderr.New("internal error", model.BadRequestResponse{}, fielderr.CodeInternal)
+	ErrUnauthorized   = fielderr.New("unknown or revoked api key", model.BadRequestResponse{}, fielderr.CodeUnauthorized)
+	ErrForbidden      = fielderr.New("api key does not allow request", model.BadRequestResponse{}, fielderr.CodeForbidden)
 )
+
+// storeError returns service error for error of storage.
+//
+// Failures of storage are mapped by their kind, other errors, for example store.ErrDoesNotExists, mean different
+// things for different methods, so they are reported as fallback.
+func storeError(err error, fallback *fielderr.Error) *fielderr.Error {
+	fields := []zap.Field{zap.NamedError("storage_error", err)}
+	if constraint := store.Constraint(err); constraint != "" {
+		fields = append(fields, zap.String("constraint", constraint))
+	}
+
+	switch {
+	case errors.Is(err, store.ErrUnavailable):
+		return ErrUnavailable.With(fields...)
+	case errors.Is(err, store.ErrInternal):
+		return ErrInternal.With(fields...)
+	case errors.Is(err, store.ErrUniqueViolation), errors.Is(err, store.ErrSerialization):
+		return ErrConflict.With(fields...)
+	case errors.Is(err, store.ErrForeignKeyViolation):
+		return ErrNotFound.With(fields...)
+	case errors.Is(err, store.ErrCheckViolation):
+		return ErrBadRequest.With(fields...)
+	}
+	return fallback.With(fields...)
+}
@@ -0,0 +1,48 @@
+package production
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/fielderr"
+	"go.uber.org/zap"
+	"net/http"
+	"testing"
+)
+
+func TestStoreError(t *testing.T) {
+	tt := []struct {
+		name   string
+		err    error
+		want   error
+		status int
+	}{
+		{"unavailable", &store.Error{Kind: store.ErrUnavailable}, ErrUnavailable, http.StatusServiceUnavailable},
+		{"internal", &store.Error{Kind: store.ErrInternal}, ErrInternal, http.StatusInternalServerError},
+		{"unique", &store.Error{Kind: store.ErrUniqueViolation}, ErrConflict, http.StatusConflict},
+		{"serialization", &store.Error{Kind: store.ErrSerialization}, ErrConflict, http.StatusConflict},
+		{"foreign key", &store.Error{Kind: store.ErrForeignKeyViolation}, ErrNotFound, http.StatusNotFound},
+		{"check", &store.Error{Kind: store.ErrCheckViolation}, ErrBadRequest, http.StatusBadRequest},
+		{"does not exist", fmt.Errorf("order 1: %w", store.ErrDoesNotExists), ErrNotAssigned, http.StatusNotFound},
+		{"unknown", errors.New("unknown"), ErrNotAssigned, http.StatusNotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := storeError(tc.err, ErrNotAssigned)
+			assert.ErrorIs(t, err, tc.want)
+			assert.Equal(t, tc.status, err.CodeHTTP())
+		})
+	}
+}
+
+func TestStoreError_Constraint(t *testing.T) {
+	err := storeError(fmt.Errorf("insert: %w", &store.Error{Kind: store.ErrUniqueViolation, Constraint: "couriers_pkey"}), ErrBadRequest)
+	assert.Contains(t, err.Fields(), zap.String("constraint", "couriers_pkey"))
+
+	err = storeError(errors.New("unknown"), ErrBadRequest)
+	for _, f := range err.Fields() {
+		assert.NotEqual(t, "constraint", f.Key)
+	}
+	assert.Equal(t, fielderr.CodeBadRequest, err.Code())
+}
@@ -36,6 +36,34 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CancelOrders mocks base method.
+func (m *MockStore) CancelOrders(ctx context.Context, ids []int64, reason, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrders", ctx, ids, reason, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrders indicates an expected call of CancelOrders.
+func (mr *MockStoreMockRecorder) CancelOrders(ctx, ids, reason, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrders", reflect.TypeOf((*MockStore)(nil).CancelOrders), ctx, ids, reason, actor)
+}
+
+// ChangeOrderStatus mocks base method.
+func (m *MockStore) ChangeOrderStatus(ctx context.Context, id, courier int64, from, to, actor string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeOrderStatus", ctx, id, courier, from, to, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeOrderStatus indicates an expected call of ChangeOrderStatus.
+func (mr *MockStoreMockRecorder) ChangeOrderStatus(ctx, id, courier, from, to, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeOrderStatus", reflect.TypeOf((*MockStore)(nil).ChangeOrderStatus), ctx, id, courier, from, to, actor)
+}
+
 // CompleteOrders mocks base method.
 func (m *MockStore) CompleteOrders(ctx context.Context, info []model.CompleteOrder) error {
 	m.ctrl.T.Helper()
@@ -50,6 +78,20 @@ func (mr *MockStoreMockRecorder) CompleteOrders(ctx, info interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteOrders", reflect.TypeOf((*MockStore)(nil).CompleteOrders), ctx, info)
 }
 
+// CreateAPIKey mocks base method.
+func (m *MockStore) CreateAPIKey(ctx context.Context, key *model.APIKey, hash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key, hash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStoreMockRecorder) CreateAPIKey(ctx, key, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStore)(nil).CreateAPIKey), ctx, key, hash)
+}
+
 // CreateCouriers mocks base method.
 func (m *MockStore) CreateCouriers(ctx context.Context, couriers []model.CreateCourierDTO) ([]model.CourierDTO, error) {
 	m.ctrl.T.Helper()
@@ -79,6 +121,93 @@ func (mr *MockStoreMockRecorder) CreateOrders(ctx, orders interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrders", reflect.TypeOf((*MockStore)(nil).CreateOrders), ctx, orders)
 }
 
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(ctx context.Context, w *model.WebhookDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(ctx, w interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), ctx, w)
+}
+
+// DeactivateCourier mocks base method.
+func (m *MockStore) DeactivateCourier(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateCourier", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateCourier indicates an expected call of DeactivateCourier.
+func (mr *MockStoreMockRecorder) DeactivateCourier(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateCourier", reflect.TypeOf((*MockStore)(nil).DeactivateCourier), ctx, id)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), ctx, id)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockStore) GetAPIKeyByHash(ctx context.Context, hash []byte) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockStoreMockRecorder) GetAPIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockStore)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetActiveCouriers mocks base method.
+func (m *MockStore) GetActiveCouriers(ctx context.Context) ([]model.CourierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveCouriers", ctx)
+	ret0, _ := ret[0].([]model.CourierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveCouriers indicates an expected call of GetActiveCouriers.
+func (mr *MockStoreMockRecorder) GetActiveCouriers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveCouriers", reflect.TypeOf((*MockStore)(nil).GetActiveCouriers), ctx)
+}
+
+// GetAssignedOrders mocks base method.
+func (m *MockStore) GetAssignedOrders(ctx context.Context, date string) ([]*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAssignedOrders", ctx, date)
+	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAssignedOrders indicates an expected call of GetAssignedOrders.
+func (mr *MockStoreMockRecorder) GetAssignedOrders(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAssignedOrders", reflect.TypeOf((*MockStore)(nil).GetAssignedOrders), ctx, date)
+}
+
 // GetCompletedOrdersPriceByCourier mocks base method.
 func (m *MockStore) GetCompletedOrdersPriceByCourier(ctx context.Context, id int64, start, end time.Time) (int32, int32, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +254,21 @@ func (mr *MockStoreMockRecorder) GetCouriers(ctx, limit, offset interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriers", reflect.TypeOf((*MockStore)(nil).GetCouriers), ctx, limit, offset)
 }
 
+// GetCouriersAfter mocks base method.
+func (m *MockStore) GetCouriersAfter(ctx context.Context, afterID int64, limit int) ([]model.CourierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouriersAfter", ctx, afterID, limit)
+	ret0, _ := ret[0].([]model.CourierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouriersAfter indicates an expected call of GetCouriersAfter.
+func (mr *MockStoreMockRecorder) GetCouriersAfter(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouriersAfter", reflect.TypeOf((*MockStore)(nil).GetCouriersAfter), ctx, afterID, limit)
+}
+
 // GetOrderByID mocks base method.
 func (m *MockStore) GetOrderByID(ctx context.Context, id int64) (*model.OrderDTO, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +284,21 @@ func (mr *MockStoreMockRecorder) GetOrderByID(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockStore)(nil).GetOrderByID), ctx, id)
 }
 
+// GetOrderHistory mocks base method.
+func (m *MockStore) GetOrderHistory(ctx context.Context, id int64) ([]model.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderHistory", ctx, id)
+	ret0, _ := ret[0].([]model.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderHistory indicates an expected call of GetOrderHistory.
+func (mr *MockStoreMockRecorder) GetOrderHistory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderHistory", reflect.TypeOf((*MockStore)(nil).GetOrderHistory), ctx, id)
+}
+
 // GetOrders mocks base method.
 func (m *MockStore) GetOrders(ctx context.Context, limit, offset int) ([]*model.OrderDTO, error) {
 	m.ctrl.T.Helper()
@@ -155,6 +314,36 @@ func (mr *MockStoreMockRecorder) GetOrders(ctx, limit, offset interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockStore)(nil).GetOrders), ctx, limit, offset)
 }
 
+// GetOrdersAfter mocks base method.
+func (m *MockStore) GetOrdersAfter(ctx context.Context, afterID int64, limit int) ([]*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrdersAfter", ctx, afterID, limit)
+	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrdersAfter indicates an expected call of GetOrdersAfter.
+func (mr *MockStoreMockRecorder) GetOrdersAfter(ctx, afterID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersAfter", reflect.TypeOf((*MockStore)(nil).GetOrdersAfter), ctx, afterID, limit)
+}
+
+// GetOrdersAssign mocks base method.
+func (m *MockStore) GetOrdersAssign(ctx context.Context, date string, courierID int64) (*model.OrderAssignResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrdersAssign", ctx, date, courierID)
+	ret0, _ := ret[0].(*model.OrderAssignResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrdersAssign indicates an expected call of GetOrdersAssign.
+func (mr *MockStoreMockRecorder) GetOrdersAssign(ctx, date, courierID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersAssign", reflect.TypeOf((*MockStore)(nil).GetOrdersAssign), ctx, date, courierID)
+}
+
 // GetOrdersByIDs mocks base method.
 func (m *MockStore) GetOrdersByIDs(ctx context.Context, ids []int64) ([]*model.OrderDTO, error) {
 	m.ctrl.T.Helper()
@@ -169,3 +358,186 @@ func (mr *MockStoreMockRecorder) GetOrdersByIDs(ctx, ids interface{}) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrdersByIDs", reflect.TypeOf((*MockStore)(nil).GetOrdersByIDs), ctx, ids)
 }
+
+// GetSubOrders mocks base method.
+func (m *MockStore) GetSubOrders(ctx context.Context, id int64) ([]*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubOrders", ctx, id)
+	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubOrders indicates an expected call of GetSubOrders.
+func (mr *MockStoreMockRecorder) GetSubOrders(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubOrders", reflect.TypeOf((*MockStore)(nil).GetSubOrders), ctx, id)
+}
+
+// GetUnassignedOrders mocks base method.
+func (m *MockStore) GetUnassignedOrders(ctx context.Context) ([]*model.OrderDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnassignedOrders", ctx)
+	ret0, _ := ret[0].([]*model.OrderDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnassignedOrders indicates an expected call of GetUnassignedOrders.
+func (mr *MockStoreMockRecorder) GetUnassignedOrders(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnassignedOrders", reflect.TypeOf((*MockStore)(nil).GetUnassignedOrders), ctx)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockStore) GetWebhookDeliveries(ctx context.Context, id int64, limit, offset int) ([]model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, id, limit, offset)
+	ret0, _ := ret[0].([]model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(ctx, id, limit, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), ctx, id, limit, offset)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockStore) GetWebhooks(ctx context.Context) ([]model.WebhookDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx)
+	ret0, _ := ret[0].([]model.WebhookDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockStoreMockRecorder) GetWebhooks(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockStore)(nil).GetWebhooks), ctx)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockStore) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", ctx, webhookID, deliveryID)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockStoreMockRecorder) ReplayWebhookDelivery(ctx, webhookID, deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockStore)(nil).ReplayWebhookDelivery), ctx, webhookID, deliveryID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockStore) RevokeAPIKey(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockStoreMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockStore)(nil).RevokeAPIKey), ctx, id)
+}
+
+// SaveOrdersAssign mocks base method.
+func (m *MockStore) SaveOrdersAssign(ctx context.Context, resp *model.OrderAssignResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOrdersAssign", ctx, resp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOrdersAssign indicates an expected call of SaveOrdersAssign.
+func (mr *MockStoreMockRecorder) SaveOrdersAssign(ctx, resp interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOrdersAssign", reflect.TypeOf((*MockStore)(nil).SaveOrdersAssign), ctx, resp)
+}
+
+// UpdateCourier mocks base method.
+func (m *MockStore) UpdateCourier(ctx context.Context, id int64, req *model.UpdateCourierRequest) (*model.CourierDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourier", ctx, id, req)
+	ret0, _ := ret[0].(*model.CourierDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourier indicates an expected call of UpdateCourier.
+func (mr *MockStoreMockRecorder) UpdateCourier(ctx, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourier", reflect.TypeOf((*MockStore)(nil).UpdateCourier), ctx, id, req)
+}
+
+// WithAssignLock mocks base method.
+func (m *MockStore) WithAssignLock(ctx context.Context, date string, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithAssignLock", ctx, date, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithAssignLock indicates an expected call of WithAssignLock.
+func (mr *MockStoreMockRecorder) WithAssignLock(ctx, date, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithAssignLock", reflect.TypeOf((*MockStore)(nil).WithAssignLock), ctx, date, fn)
+}
+
+// MockConfig is a mock of Config interface.
+type MockConfig struct {
+	ctrl     *gomock.Controller
+	recorder *MockConfigMockRecorder
+}
+
+// MockConfigMockRecorder is the mock recorder for MockConfig.
+type MockConfigMockRecorder struct {
+	mock *MockConfig
+}
+
+// NewMockConfig creates a new mock instance.
+func NewMockConfig(ctrl *gomock.Controller) *MockConfig {
+	mock := &MockConfig{ctrl: ctrl}
+	mock.recorder = &MockConfigMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConfig) EXPECT() *MockConfigMockRecorder {
+	return m.recorder
+}
+
+// DefaultStrategy mocks base method.
+func (m *MockConfig) DefaultStrategy() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DefaultStrategy")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// DefaultStrategy indicates an expected call of DefaultStrategy.
+func (mr *MockConfigMockRecorder) DefaultStrategy() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DefaultStrategy", reflect.TypeOf((*MockConfig)(nil).DefaultStrategy))
+}
+
+// SearchBudget mocks base method.
+func (m *MockConfig) SearchBudget() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBudget")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// SearchBudget indicates an expected call of SearchBudget.
+func (mr *MockConfigMockRecorder) SearchBudget() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBudget", reflect.TypeOf((*MockConfig)(nil).SearchBudget))
+}
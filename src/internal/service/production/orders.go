@@ -3,21 +3,268 @@ package production
 import (
 	"context"
 	"errors"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/zap"
+	"sort"
 	"strconv"
 )
 
-func (srv *Service) AssignOrders(context.Context, *datetime.Date) (*model.OrderAssignResponse, error) {
-	return nil, ErrNotImplemented
+// AssignOrders distributes all unassigned orders between couriers and stores result.
+//
+// Orders do not have date of delivery, so every unassigned order belongs to assignment of today: orders can be
+// distributed only for today, for other dates only stored assignment is returned and ErrNotToday is returned if there
+// is none. Strategy of assignment is taken from opts, if it is not provided then default strategy of service is used. In dry run mode assignment is only computed in memory and storage is not
+// modified.
+//
+// Assignments of one date are serialized. If orders were already assigned at date then stored assignment is returned,
+// with force option orders of stored assignment which couriers have not taken yet are distributed again together with
+// unassigned ones, groups in which courier has already taken orders are kept.
+//
+// In incremental mode groups of stored assignment are kept and unassigned orders are appended to them or put into new
+// groups, orders which couriers already have are never moved. Incremental mode can not be used with force option.
+func (srv *Service) AssignOrders(ctx context.Context, date *datetime.Date, opts model.AssignOpts) (resp *model.OrderAssignResponse, err error) {
+	if date == nil {
+		return nil, ErrBadRequest
+	}
+
+	strategy := srv.strategy
+	if opts != nil && opts.Strategy() != "" {
+		strategy = opts.Strategy()
+	}
+	assigner, ok := srv.assigners[strategy]
+	if !ok {
+		return nil, ErrBadRequest.With(zap.String("strategy", strategy))
+	}
+	dryRun, force := opts != nil && opts.DryRun(), opts != nil && opts.Force()
+	incremental := opts != nil && opts.Incremental()
+	if incremental && force {
+		return nil, ErrBadRequest.With(zap.Bool("force", force), zap.Bool("incremental", incremental))
+	}
+	today := date.String() == datetime.Today().String()
+	if !today && (force || incremental) {
+		return nil, ErrNotToday.With(zap.String("date", date.String()))
+	}
+
+	err = srv.storage.WithAssignLock(ctx, date.String(), func(ctx context.Context) (err error) {
+		if !force && !incremental {
+			resp, err = srv.storage.GetOrdersAssign(ctx, date.String(), 0)
+			if err == nil {
+				resp.TotalCost = resp.Cost()
+				resp.DryRun = dryRun
+				return nil
+			}
+			if !errors.Is(err, store.ErrDoesNotExists) {
+				return err
+			}
+			if !today {
+				return ErrNotToday
+			}
+		}
+
+		if incremental {
+			resp, err = srv.extendOrders(ctx, date.String(), assigner)
+		} else {
+			resp, err = srv.assignOrders(ctx, date.String(), assigner, force)
+		}
+		if err != nil {
+			return err
+		}
+		resp.Strategy = strategy
+		if dryRun {
+			resp.DryRun = true
+			return nil
+		}
+		return srv.storage.SaveOrdersAssign(ctx, resp)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotToday) {
+			return nil, ErrNotToday.With(zap.String("date", date.String()))
+		}
+		if errors.Is(err, store.ErrAlreadyAssigned) {
+			return nil, ErrAssignConflict.With(zap.NamedError("storage_error", err))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+
+	srv.log.Debug(
+		"successful assigned orders",
+		zap.String("date", resp.Date),
+		zap.String("strategy", resp.Strategy),
+		zap.Bool("dry_run", resp.DryRun),
+		zap.Bool("force", force),
+		zap.Bool("incremental", incremental),
+		zap.Int("unassigned", len(resp.Unassigned)),
+		zap.Int("couriers", len(resp.Couriers)),
+		zap.Float64("total_cost", resp.TotalCost),
+	)
+	return resp, nil
+}
+
+// assignOrders loads couriers and orders and distributes orders with assigner.
+//
+// If reassign is true then orders which were assigned at date and which courier has not taken yet are distributed too.
+// Groups in which courier has already taken orders are kept as is and are extended like in incremental mode.
+func (srv *Service) assignOrders(ctx context.Context, date string, assigner assign.Assigner, reassign bool) (*model.OrderAssignResponse, error) {
+	couriers, err := srv.storage.GetActiveCouriers(ctx)
+	if err != nil && !errors.Is(err, store.ErrNoContent) {
+		return nil, err
+	}
+
+	var orders []*model.OrderDTO
+	orders, err = srv.storage.GetUnassignedOrders(ctx)
+	if err != nil && !errors.Is(err, store.ErrNoContent) {
+		return nil, err
+	}
+
+	kept := &model.OrderAssignResponse{Date: date}
+	if reassign {
+		if kept, err = srv.startedGroups(ctx, date); err != nil {
+			return nil, err
+		}
+		keptOrders := make(map[int64]bool)
+		for _, courier := range kept.Couriers {
+			for _, group := range courier.Orders {
+				for _, order := range group.Orders {
+					keptOrders[order.OrderID] = true
+				}
+			}
+		}
+
+		var assigned []*model.OrderDTO
+		assigned, err = srv.storage.GetAssignedOrders(ctx, date)
+		if err != nil && !errors.Is(err, store.ErrNoContent) {
+			return nil, err
+		}
+		for _, order := range assigned {
+			if !keptOrders[order.OrderID] {
+				orders = append(orders, order)
+			}
+		}
+		sort.Slice(orders, func(i, j int) bool {
+			return orders[i].OrderID < orders[j].OrderID
+		})
+	}
+
+	ctx, cancel := srv.searchContext(ctx)
+	defer cancel()
+	if len(kept.Couriers) > 0 {
+		return assigner.Extend(ctx, kept, couriers, orders), nil
+	}
+	return assigner.Assign(ctx, date, couriers, orders), nil
 }
 
-func (srv *Service) GetOrdersAssign(context.Context, *datetime.Date, string) (order *model.OrderAssignResponse, err error) {
-	return nil, ErrNotImplemented
+// startedGroups returns groups of stored assignment of date in which courier has already taken at least one order.
+//
+// If orders were never assigned at date then assignment without groups is returned.
+func (srv *Service) startedGroups(ctx context.Context, date string) (*model.OrderAssignResponse, error) {
+	res := &model.OrderAssignResponse{Date: date}
+	stored, err := srv.storage.GetOrdersAssign(ctx, date, 0)
+	if err != nil {
+		if errors.Is(err, store.ErrDoesNotExists) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	for _, courier := range stored.Couriers {
+		var groups []model.GroupOrders
+		for _, group := range courier.Orders {
+			for _, order := range group.Orders {
+				if order.Status != model.OrderStatusAssigned {
+					groups = append(groups, group)
+					break
+				}
+			}
+		}
+		if len(groups) > 0 {
+			res.Couriers = append(res.Couriers, model.CourierGroupOrders{CourierID: courier.CourierID, Orders: groups})
+		}
+	}
+	return res, nil
+}
+
+// extendOrders loads stored assignment of date, couriers and unassigned orders and extends assignment with assigner.
+//
+// If orders were never assigned at date then unassigned orders are distributed from scratch.
+func (srv *Service) extendOrders(ctx context.Context, date string, assigner assign.Assigner) (*model.OrderAssignResponse, error) {
+	existing, err := srv.storage.GetOrdersAssign(ctx, date, 0)
+	if err != nil {
+		if !errors.Is(err, store.ErrDoesNotExists) {
+			return nil, err
+		}
+		existing = &model.OrderAssignResponse{Date: date}
+	}
+
+	var couriers []model.CourierDTO
+	couriers, err = srv.storage.GetActiveCouriers(ctx)
+	if err != nil && !errors.Is(err, store.ErrNoContent) {
+		return nil, err
+	}
+
+	var orders []*model.OrderDTO
+	orders, err = srv.storage.GetUnassignedOrders(ctx)
+	if err != nil && !errors.Is(err, store.ErrNoContent) {
+		return nil, err
+	}
+
+	ctx, cancel := srv.searchContext(ctx)
+	defer cancel()
+	return assigner.Extend(ctx, existing, couriers, orders), nil
+}
+
+// searchContext returns ctx limited by budget of search of assignment.
+func (srv *Service) searchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if srv.budget <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, srv.budget)
+}
+
+// GetOrdersAssign returns orders that were assigned at date.
+//
+// If id is empty string then groups of all couriers are returned. If orders were never assigned at date then
+// ErrNotAssigned will be returned. Courier without assigned orders is returned with empty slice of groups.
+func (srv *Service) GetOrdersAssign(ctx context.Context, date *datetime.Date, id string) (resp *model.OrderAssignResponse, err error) {
+	if date == nil {
+		return nil, ErrBadRequest
+	}
+
+	var courierID int64
+	if id != "" {
+		courierID, err = strconv.ParseInt(id, 10, 64)
+		if err != nil || courierID <= 0 {
+			return nil, ErrBadRequest.With(zap.String("courier_id", id))
+		}
+		if _, err = srv.storage.GetCourierByID(ctx, courierID); err != nil {
+			return nil, storeError(err, ErrNotFound)
+		}
+	}
+
+	resp, err = srv.storage.GetOrdersAssign(ctx, date.String(), courierID)
+	if err != nil {
+		if errors.Is(err, store.ErrDoesNotExists) {
+			return nil, ErrNotAssigned.With(zap.String("date", date.String()))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+
+	if courierID != 0 && len(resp.Couriers) == 0 {
+		resp.Couriers = append(resp.Couriers, model.CourierGroupOrders{
+			CourierID: courierID,
+			Orders:    []model.GroupOrders{},
+		})
+	}
+	resp.TotalCost = resp.Cost()
+	return resp, nil
 }
 
+// GetOrderByID returns order with provided id.
+//
+// If order was split then its sub-orders are returned too.
 func (srv *Service) GetOrderByID(ctx context.Context, id string) (order *model.OrderDTO, err error) {
 	var orderID int64
 	orderID, err = strconv.ParseInt(id, 10, 64)
@@ -27,26 +274,58 @@ func (srv *Service) GetOrderByID(ctx context.Context, id string) (order *model.O
 
 	order, err = srv.storage.GetOrderByID(ctx, orderID)
 	if err != nil {
-		return nil, ErrNotFound
+		return nil, storeError(err, ErrNotFound)
+	}
+	if order == nil {
+		return
+	}
+
+	order.SubOrders, err = srv.storage.GetSubOrders(ctx, orderID)
+	if err != nil && !errors.Is(err, store.ErrNoContent) {
+		return nil, storeError(err, ErrBadRequest)
 	}
-	return
+	return order, nil
 }
 
-func (srv *Service) GetOrders(ctx context.Context, opts model.PaginationOpts) ([]*model.OrderDTO, error) {
+// GetOrders returns orders with pagination options.
+//
+// Page can be selected either by offset or by cursor from previous page.
+func (srv *Service) GetOrders(ctx context.Context, opts model.PaginationOpts) (*model.GetOrdersResponse, error) {
 	if opts == nil {
 		return nil, ErrBadRequest
 	}
+	afterID, byCursor, err := cursor(opts)
+	if err != nil {
+		return nil, err
+	}
 
-	orders, err := srv.storage.GetOrders(ctx, opts.Limit(), opts.Offset())
+	var orders []*model.OrderDTO
+	if byCursor {
+		orders, err = srv.storage.GetOrdersAfter(ctx, afterID, opts.Limit())
+	} else {
+		orders, err = srv.storage.GetOrders(ctx, opts.Limit(), opts.Offset())
+	}
 	if err != nil {
-		if errors.Is(err, store.ErrNoContent) {
-			return []*model.OrderDTO{}, nil
+		if !errors.Is(err, store.ErrNoContent) {
+			return nil, storeError(err, ErrBadRequest)
 		}
-		return nil, ErrBadRequest.With(zap.NamedError("storage_error", err))
+		orders = []*model.OrderDTO{}
 	}
-	return orders, nil
+
+	resp := &model.GetOrdersResponse{
+		Orders: orders,
+		Limit:  opts.Limit(),
+		Offset: opts.Offset(),
+	}
+	if len(orders) > 0 {
+		resp.NextCursor = model.NextCursor(len(orders), opts.Limit(), orders[len(orders)-1].OrderID)
+	}
+	return resp, nil
 }
 
+// CreateOrders stores orders.
+//
+// Orders which are heavier than AUTO courier can carry are split into sub-orders which are assigned independently.
 func (srv *Service) CreateOrders(ctx context.Context, req *model.CreateOrderRequest) ([]*model.OrderDTO, error) {
 	if !req.Valid() {
 		srv.log.Debug("request didn't pass validation")
@@ -55,39 +334,171 @@ func (srv *Service) CreateOrders(ctx context.Context, req *model.CreateOrderRequ
 
 	var orders []*model.OrderDTO
 	for _, order := range req.Orders {
-		orders = append(orders, &model.OrderDTO{
+		dto := &model.OrderDTO{
 			Weight:        order.Weight,
 			Regions:       order.Regions,
 			DeliveryHours: order.DeliveryHours,
 			Cost:          order.Cost,
-		})
+		}
+		for _, sub := range order.SubOrders() {
+			dto.SubOrders = append(dto.SubOrders, &model.OrderDTO{
+				Weight:        sub.Weight,
+				Regions:       sub.Regions,
+				DeliveryHours: sub.DeliveryHours,
+				Cost:          sub.Cost,
+			})
+		}
+		orders = append(orders, dto)
 	}
 	if err := srv.storage.CreateOrders(ctx, orders); err != nil {
-		return nil, ErrBadRequest.With(zap.NamedError("storage_error", err))
+		return nil, storeError(err, ErrBadRequest)
 	}
 	srv.log.Debug("successful created orders")
 	return orders, nil
 }
 
+// CompleteOrders completes orders by couriers they are assigned to.
+//
+// Only orders which are assigned to courier or are delivered by courier can be completed, completing of already
+// completed order changes nothing. Request which is authenticated with courier key can complete only orders of courier
+// who owns key.
 func (srv *Service) CompleteOrders(ctx context.Context, req *model.CompleteOrderRequest) ([]*model.OrderDTO, error) {
 	if !req.Valid() {
 		srv.log.Debug("request didn't pass validation")
 		return nil, ErrBadRequest
 	}
 
-	if err := srv.storage.CompleteOrders(ctx, req.CompleteInfo); err != nil {
-		return nil, ErrBadRequest.With(zap.NamedError("storage_error", err))
-	}
-
+	key, authenticated := auth.FromContext(ctx)
 	var ids []int64
 	for _, c := range req.CompleteInfo {
+		if authenticated && !key.CanComplete(c.CourierID) {
+			return nil, ErrForbidden.With(zap.Int64("key_id", key.KeyID), zap.Int64("courier_id", c.CourierID))
+		}
 		ids = append(ids, c.OrderID)
 	}
 
 	orders, err := srv.storage.GetOrdersByIDs(ctx, ids)
 	if err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
+	for _, o := range orders {
+		if o.Status != model.OrderStatusCompleted && !model.CanChangeOrderStatus(o.Status, model.OrderStatusCompleted) {
+			return nil, ErrBadTransition.With(zap.Int64("order_id", o.OrderID), zap.String("status", o.Status))
+		}
+	}
+
+	if err = srv.storage.CompleteOrders(ctx, req.CompleteInfo); err != nil {
+		if errors.Is(err, store.ErrStatusChanged) {
+			return nil, ErrBadTransition.With(zap.NamedError("storage_error", err))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+
+	orders, err = srv.storage.GetOrdersByIDs(ctx, ids)
+	if err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
+
+	return orders, nil
+}
+
+// CancelOrders cancels orders which customers withdrew and takes them back from couriers.
+//
+// Order which was split is cancelled with all of its sub-orders, sub-order can not be cancelled alone. Cancelling of
+// already cancelled order changes nothing. Orders which are completed or are delivered by courier can not be
+// cancelled.
+func (srv *Service) CancelOrders(ctx context.Context, req *model.CancelOrderRequest) ([]*model.OrderDTO, error) {
+	if !req.Valid() {
+		srv.log.Debug("request didn't pass validation")
 		return nil, ErrBadRequest
 	}
 
+	orders, err := srv.storage.GetOrdersByIDs(ctx, req.OrderIDs)
+	if err != nil {
+		return nil, storeError(err, ErrNotFound)
+	}
+	for _, o := range orders {
+		if o.ParentOrderID != 0 {
+			return nil, ErrBadRequest.With(zap.Int64("order_id", o.OrderID), zap.Int64("parent_order_id", o.ParentOrderID))
+		}
+		if o.Status != model.OrderStatusCancelled && !model.CanChangeOrderStatus(o.Status, model.OrderStatusCancelled) {
+			return nil, ErrBadTransition.With(zap.Int64("order_id", o.OrderID), zap.String("status", o.Status))
+		}
+	}
+
+	if err = srv.storage.CancelOrders(ctx, req.OrderIDs, req.Reason, model.ActorAPI); err != nil {
+		switch {
+		case errors.Is(err, store.ErrStatusChanged):
+			return nil, ErrBadTransition.With(zap.NamedError("storage_error", err))
+		case errors.Is(err, store.ErrDoesNotExists):
+			return nil, ErrNotFound.With(zap.NamedError("storage_error", err))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+	srv.log.Debug("orders cancelled", zap.Int64s("order_ids", req.OrderIDs))
+
+	if orders, err = srv.storage.GetOrdersByIDs(ctx, req.OrderIDs); err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
 	return orders, nil
 }
+
+// ChangeOrderStatus moves order with provided id to status from request.
+//
+// Change must be allowed from current status of order, see model.CanChangeOrderStatus. Request which is authenticated
+// with courier key can change status only of orders which are assigned to courier who owns key, change is recorded on
+// behalf of courier then.
+func (srv *Service) ChangeOrderStatus(ctx context.Context, id string, req *model.ChangeOrderStatusRequest) (*model.OrderDTO, error) {
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.String("order_id", id))
+	}
+	if !req.Valid() {
+		srv.log.Debug("request didn't pass validation")
+		return nil, ErrBadRequest
+	}
+
+	courier, actor := int64(0), model.ActorAPI
+	if key, ok := auth.FromContext(ctx); ok && key.Role == model.RoleCourier {
+		if key.CourierID == nil {
+			return nil, ErrForbidden.With(zap.Int64("key_id", key.KeyID))
+		}
+		courier, actor = *key.CourierID, model.CourierActor(*key.CourierID)
+	}
+
+	var order *model.OrderDTO
+	if order, err = srv.storage.GetOrderByID(ctx, orderID); err != nil {
+		return nil, storeError(err, ErrNotFound)
+	}
+	if !model.CanChangeOrderStatus(order.Status, req.Status) {
+		return nil, ErrBadTransition.With(zap.Int64("order_id", orderID), zap.String("from", order.Status), zap.String("to", req.Status))
+	}
+
+	if err = srv.storage.ChangeOrderStatus(ctx, orderID, courier, order.Status, req.Status, actor); err != nil {
+		switch {
+		case errors.Is(err, store.ErrStatusChanged):
+			return nil, ErrBadTransition.With(zap.NamedError("storage_error", err))
+		case errors.Is(err, store.ErrDoesNotExists):
+			return nil, ErrNotFound.With(zap.NamedError("storage_error", err))
+		}
+		return nil, storeError(err, ErrBadRequest)
+	}
+	srv.log.Debug("order status changed", zap.Int64("order_id", orderID), zap.String("from", order.Status), zap.String("to", req.Status))
+
+	order.Status = req.Status
+	return order, nil
+}
+
+// GetOrderHistory returns history of status of order with provided id.
+func (srv *Service) GetOrderHistory(ctx context.Context, id string) (*model.OrderHistoryResponse, error) {
+	orderID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.String("order_id", id))
+	}
+
+	var history []model.OrderStatusChange
+	if history, err = srv.storage.GetOrderHistory(ctx, orderID); err != nil {
+		return nil, storeError(err, ErrNotFound)
+	}
+	return &model.OrderHistoryResponse{OrderID: orderID, History: history}, nil
+}
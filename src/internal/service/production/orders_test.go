@@ -3,8 +3,12 @@ package production
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/auth"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller/http"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production/mocks"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
@@ -15,24 +19,581 @@ import (
 	"time"
 )
 
-func TestService_GetOrdersAssign(t *testing.T) {
+func TestService_GetOrdersAssign_Negative_BadRequest(t *testing.T) {
 	srv := testService(t, nil)
 	resp, err := srv.GetOrdersAssign(context.Background(), nil, "")
 	assert.Nil(t, resp)
-	if assert.Error(t, err) {
-		assert.ErrorIs(t, err, ErrNotImplemented)
+	assert.ErrorIs(t, err, ErrBadRequest)
+
+	for _, id := range []string{"random string", "1.1", "0", "-1"} {
+		t.Run(id, func(t *testing.T) {
+			resp, err = srv.GetOrdersAssign(context.Background(), datetime.Today(), id)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadRequest)
+		})
+	}
+}
+
+func TestService_GetOrdersAssign_Negative_UnknownCourier(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	str := mocks.NewMockStore(ctrl)
+	str.EXPECT().GetCourierByID(ctx, int64(1)).Return(nil, errors.New(""))
+
+	resp, err := testService(t, str).GetOrdersAssign(ctx, datetime.Today(), "1")
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestService_GetOrdersAssign_Negative_ErrInStorage(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	tt := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"not assigned", store.ErrDoesNotExists, ErrNotAssigned},
+		{"unknown", errors.New(""), ErrBadRequest},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(nil, tc.err)
+
+			resp, err := testService(t, str).GetOrdersAssign(ctx, date, "")
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, tc.want)
+		})
 	}
 }
 
-func TestService_AssignOrders(t *testing.T) {
+func TestService_GetOrdersAssign_Positive(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+
+	t.Run("all couriers", func(t *testing.T) {
+		want := &model.OrderAssignResponse{
+			Date: date.String(),
+			Couriers: []model.CourierGroupOrders{{
+				CourierID: 1,
+				Orders:    []model.GroupOrders{{GroupOrderID: 1, Orders: []model.OrderDTO{{OrderID: 1}}}},
+			}},
+		}
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(want, nil)
+
+		resp, err := testService(t, str).GetOrdersAssign(ctx, date, "")
+		assert.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+	t.Run("courier without orders", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetCourierByID(ctx, int64(2)).Return(&model.CourierDTO{CourierID: 2}, nil)
+		str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(2)).Return(&model.OrderAssignResponse{
+			Date:     date.String(),
+			Couriers: []model.CourierGroupOrders{},
+		}, nil)
+
+		resp, err := testService(t, str).GetOrdersAssign(ctx, date, "2")
+		assert.NoError(t, err)
+		assert.Equal(t, &model.OrderAssignResponse{
+			Date:     date.String(),
+			Couriers: []model.CourierGroupOrders{{CourierID: 2, Orders: []model.GroupOrders{}}},
+		}, resp)
+	})
+}
+
+// expectNewAssign expects assignment of orders at date which were not assigned yet.
+func expectNewAssign(str *mocks.MockStore, date *datetime.Date) {
+	expectAssignLock(str, date)
+	str.EXPECT().GetOrdersAssign(gomock.Any(), date.String(), int64(0)).Return(nil, store.ErrDoesNotExists)
+}
+
+// expectAssignLock expects that assignment lock of date will be taken.
+func expectAssignLock(str *mocks.MockStore, date *datetime.Date) {
+	str.EXPECT().WithAssignLock(gomock.Any(), date.String(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+}
+
+func TestService_AssignOrders_Negative_NilDate(t *testing.T) {
 	srv := testService(t, nil)
-	resp, err := srv.AssignOrders(context.Background(), nil)
+	resp, err := srv.AssignOrders(context.Background(), nil, nil)
 	assert.Nil(t, resp)
 	if assert.Error(t, err) {
-		assert.ErrorIs(t, err, ErrNotImplemented)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	}
+}
+
+func TestService_AssignOrders_NotToday(t *testing.T) {
+	ctx := context.Background()
+	date, err := datetime.ParseDate("2023-01-01")
+	require.NoError(t, err)
+
+	for name, opts := range map[string]testAssignOpts{
+		"force":       {force: true},
+		"incremental": {incremental: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := testService(t, nil).AssignOrders(ctx, date, opts)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrNotToday)
+		})
+	}
+	t.Run("not assigned", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectNewAssign(str, date)
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotToday)
+	})
+	t.Run("stored", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectAssignLock(str, date)
+		want := &model.OrderAssignResponse{Date: date.String(), Couriers: []model.CourierGroupOrders{}}
+		str.EXPECT().GetOrdersAssign(gomock.Any(), date.String(), int64(0)).Return(want, nil)
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+}
+
+func TestService_AssignOrders_Negative_ErrInStorage(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+
+	t.Run("couriers", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectNewAssign(str, date)
+		str.EXPECT().GetActiveCouriers(ctx).Return(nil, errors.New(""))
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("orders", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectNewAssign(str, date)
+		str.EXPECT().GetActiveCouriers(ctx).Return(nil, store.ErrNoContent)
+		str.EXPECT().GetUnassignedOrders(ctx).Return(nil, errors.New(""))
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("save", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectNewAssign(str, date)
+		str.EXPECT().GetActiveCouriers(ctx).Return(nil, nil)
+		str.EXPECT().GetUnassignedOrders(ctx).Return(nil, nil)
+		str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(errors.New(""))
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestService_AssignOrders_Positive(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	orders := []*model.OrderDTO{
+		{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{OrderID: 2, Weight: 1, Regions: 2, DeliveryHours: hours, Cost: 100},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectNewAssign(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(orders, nil)
+	str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, resp *model.OrderAssignResponse) error {
+		resp.Couriers[0].Orders[0].GroupOrderID = 42
+		return nil
+	})
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, date.String(), resp.Date)
+		if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+			group := resp.Couriers[0].Orders[0]
+			assert.Equal(t, int64(42), group.GroupOrderID)
+			if assert.Len(t, group.Orders, 1) {
+				assert.Equal(t, int64(1), group.Orders[0].OrderID)
+			}
+		}
+	}
+}
+
+func TestService_AssignOrders_Strategy(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+		{CourierID: 2, CourierType: model.BikeCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	orders := func() []*model.OrderDTO {
+		var res []*model.OrderDTO
+		for i := int64(1); i <= 4; i++ {
+			res = append(res, &model.OrderDTO{OrderID: i, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100})
+		}
+		return res
+	}
+
+	tt := []struct {
+		name     string
+		opts     model.AssignOpts
+		wantName string
+		wantCost float64
+	}{
+		{"default", nil, assign.GreedyStrategy, 360},
+		{"empty", testAssignOpts{strategy: ""}, assign.GreedyStrategy, 360},
+		{"greedy", testAssignOpts{strategy: assign.GreedyStrategy}, assign.GreedyStrategy, 360},
+		{"optimal", testAssignOpts{strategy: assign.OptimalStrategy}, assign.OptimalStrategy, 340},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			expectNewAssign(str, date)
+			str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+			str.EXPECT().GetUnassignedOrders(ctx).Return(orders(), nil)
+			str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(nil)
+
+			resp, err := testService(t, str).AssignOrders(ctx, date, tc.opts)
+			assert.NoError(t, err)
+			if assert.NotNil(t, resp) {
+				assert.Equal(t, tc.wantName, resp.Strategy)
+				assert.Equal(t, tc.wantCost, resp.TotalCost)
+			}
+		})
+	}
+	t.Run("unknown", func(t *testing.T) {
+		resp, err := testService(t, nil).AssignOrders(ctx, date, testAssignOpts{strategy: "unknown"})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestService_AssignOrders_DryRun(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	orders := []*model.OrderDTO{
+		{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{OrderID: 2, Weight: 1, Regions: 2, DeliveryHours: hours, Cost: 100},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectNewAssign(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(orders, nil)
+	str.EXPECT().SaveOrdersAssign(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{dryRun: true})
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.True(t, resp.DryRun)
+		assert.Equal(t, []model.UnassignedOrder{{OrderID: 2, Reason: model.UnassignedReasonNoRegion}}, resp.Unassigned)
+		if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+			assert.Zero(t, resp.Couriers[0].Orders[0].GroupOrderID)
+		}
 	}
 }
 
+func TestService_AssignOrders_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	stored := &model.OrderAssignResponse{
+		Date:     date.String(),
+		Strategy: assign.OptimalStrategy,
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{{
+				GroupOrderID: 1,
+				Orders:       []model.OrderDTO{{OrderID: 1, Cost: 100}, {OrderID: 2, Cost: 100}},
+			}},
+		}},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectAssignLock(str, date)
+	str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(stored, nil)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, stored, resp)
+		assert.Equal(t, float64(180), resp.TotalCost)
+		assert.Equal(t, assign.OptimalStrategy, resp.Strategy)
+	}
+}
+
+func TestService_AssignOrders_Force(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.BikeCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	unassigned := []*model.OrderDTO{
+		{OrderID: 3, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+	assigned := []*model.OrderDTO{
+		{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, Status: model.OrderStatusAssigned},
+	}
+	stored := &model.OrderAssignResponse{
+		Date: date.String(),
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders:    []model.GroupOrders{{GroupOrderID: 7, Orders: []model.OrderDTO{*assigned[0]}}},
+		}},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectAssignLock(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(unassigned, nil)
+	str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(stored, nil)
+	str.EXPECT().GetAssignedOrders(ctx, date.String()).Return(assigned, nil)
+	str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(nil)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{force: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{1: {1, 3}}, assignedOrderIDs(resp))
+	if assert.NotNil(t, resp) && assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		assert.Zero(t, resp.Couriers[0].Orders[0].GroupOrderID)
+	}
+}
+
+func TestService_AssignOrders_Force_PartlyDelivered(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	delivered, planned := datetime.Minute(612), datetime.Minute(620)
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.BikeCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	started := model.GroupOrders{
+		GroupOrderID:   7,
+		DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 620}.TimeInterval(),
+		Orders: []model.OrderDTO{
+			{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, DeliveryTime: &delivered, Status: model.OrderStatusCompleted},
+			{OrderID: 2, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, DeliveryTime: &planned, Status: model.OrderStatusAssigned},
+		},
+	}
+	stored := &model.OrderAssignResponse{
+		Date: date.String(),
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{started, {
+				GroupOrderID: 8,
+				Orders: []model.OrderDTO{
+					{OrderID: 4, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, Status: model.OrderStatusAssigned},
+				},
+			}},
+		}},
+	}
+	assigned := []*model.OrderDTO{
+		{OrderID: 2, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, Status: model.OrderStatusAssigned},
+		{OrderID: 4, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, Status: model.OrderStatusAssigned},
+	}
+	unassigned := []*model.OrderDTO{
+		{OrderID: 3, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectAssignLock(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(unassigned, nil)
+	str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(stored, nil)
+	str.EXPECT().GetAssignedOrders(ctx, date.String()).Return(assigned, nil)
+	str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(nil)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{force: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{1: {1, 2, 3, 4}}, assignedOrderIDs(resp))
+	if assert.NotNil(t, resp) && assert.Len(t, resp.Couriers, 1) && assert.NotEmpty(t, resp.Couriers[0].Orders) {
+		group := resp.Couriers[0].Orders[0]
+		assert.Equal(t, int64(7), group.GroupOrderID)
+		if assert.GreaterOrEqual(t, len(group.Orders), 2) {
+			assert.Equal(t, []int64{1, 2}, []int64{group.Orders[0].OrderID, group.Orders[1].OrderID})
+			assert.Equal(t, delivered, *group.Orders[0].DeliveryTime)
+			assert.Equal(t, planned, *group.Orders[1].DeliveryTime)
+		}
+		for _, g := range resp.Couriers[0].Orders[1:] {
+			assert.Zero(t, g.GroupOrderID)
+		}
+	}
+}
+
+func TestService_AssignOrders_Incremental(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	delivered := datetime.Minute(612)
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.BikeCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	stored := &model.OrderAssignResponse{
+		Date: date.String(),
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: 1,
+			Orders: []model.GroupOrders{{
+				GroupOrderID:   7,
+				DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 612}.TimeInterval(),
+				Orders: []model.OrderDTO{
+					{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100, DeliveryTime: &delivered},
+				},
+			}},
+		}},
+	}
+	unassigned := []*model.OrderDTO{
+		{OrderID: 2, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectAssignLock(str, date)
+	str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(stored, nil)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(unassigned, nil)
+	str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(nil)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{incremental: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]int64{1: {1, 2}}, assignedOrderIDs(resp))
+	if assert.NotNil(t, resp) && assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		group := resp.Couriers[0].Orders[0]
+		assert.Equal(t, int64(7), group.GroupOrderID)
+		assert.Equal(t, delivered, *group.Orders[0].DeliveryTime)
+	}
+}
+
+func TestService_AssignOrders_Incremental_NotAssigned(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+	couriers := []model.CourierDTO{
+		{CourierID: 1, CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	}
+	unassigned := []*model.OrderDTO{
+		{OrderID: 1, Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectNewAssign(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(couriers, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(unassigned, nil)
+	str.EXPECT().SaveOrdersAssign(gomock.Any(), gomock.Any()).Times(0)
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{incremental: true, dryRun: true})
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.True(t, resp.DryRun)
+		assert.Equal(t, date.String(), resp.Date)
+		assert.Equal(t, map[int64][]int64{1: {1}}, assignedOrderIDs(resp))
+	}
+}
+
+func TestService_AssignOrders_Incremental_Negative(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+
+	t.Run("force", func(t *testing.T) {
+		resp, err := testService(t, nil).AssignOrders(ctx, date, testAssignOpts{incremental: true, force: true})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("stored assignment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		expectAssignLock(str, date)
+		str.EXPECT().GetOrdersAssign(ctx, date.String(), int64(0)).Return(nil, errors.New(""))
+
+		resp, err := testService(t, str).AssignOrders(ctx, date, testAssignOpts{incremental: true})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestService_AssignOrders_Negative_Conflict(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	expectNewAssign(str, date)
+	str.EXPECT().GetActiveCouriers(ctx).Return(nil, nil)
+	str.EXPECT().GetUnassignedOrders(ctx).Return(nil, nil)
+	str.EXPECT().SaveOrdersAssign(ctx, gomock.Any()).Return(fmt.Errorf("order 1: %w", store.ErrAlreadyAssigned))
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrAssignConflict)
+}
+
+func TestService_AssignOrders_Negative_Lock(t *testing.T) {
+	ctx := context.Background()
+	date := datetime.Today()
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	str.EXPECT().WithAssignLock(ctx, date.String(), gomock.Any()).Return(errors.New(""))
+
+	resp, err := testService(t, str).AssignOrders(ctx, date, nil)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+// assignedOrderIDs returns ids of assigned orders by courier id.
+func assignedOrderIDs(resp *model.OrderAssignResponse) map[int64][]int64 {
+	res := make(map[int64][]int64)
+	for _, c := range resp.Couriers {
+		for _, g := range c.Orders {
+			for _, o := range g.Orders {
+				res[c.CourierID] = append(res[c.CourierID], o.OrderID)
+			}
+		}
+	}
+	return res
+}
+
 func TestService_GetOrderByID_Negative_UnParsableID(t *testing.T) {
 	tt := []string{"random string", "1.1", "1,1", ""}
 	for _, tc := range tt {
@@ -90,6 +651,7 @@ func TestService_GetOrderByID_Positive_NonNilResp(t *testing.T) {
 		CompletedTime: datetime.Time{},
 	}
 	str.EXPECT().GetOrderByID(ctx, int64(123)).Return(want, nil)
+	str.EXPECT().GetSubOrders(ctx, int64(123)).Return(nil, nil)
 
 	srv := testService(t, str)
 
@@ -100,6 +662,39 @@ func TestService_GetOrderByID_Positive_NonNilResp(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestService_GetOrderByID_SubOrders(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+
+		subs := []*model.OrderDTO{
+			{OrderID: 124, ParentOrderID: 123, Weight: 30, Cost: 50},
+			{OrderID: 125, ParentOrderID: 123, Weight: 30, Cost: 50},
+		}
+		str.EXPECT().GetOrderByID(ctx, int64(123)).Return(&model.OrderDTO{OrderID: 123, Weight: 60, Cost: 100}, nil)
+		str.EXPECT().GetSubOrders(ctx, int64(123)).Return(subs, nil)
+
+		resp, err := testService(t, str).GetOrderByID(ctx, "123")
+		assert.NoError(t, err)
+		if assert.NotNil(t, resp) {
+			assert.Equal(t, subs, resp.SubOrders)
+		}
+	})
+	t.Run("err in storage", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+
+		str.EXPECT().GetOrderByID(ctx, int64(123)).Return(&model.OrderDTO{OrderID: 123}, nil)
+		str.EXPECT().GetSubOrders(ctx, int64(123)).Return(nil, errors.New(""))
+
+		resp, err := testService(t, str).GetOrderByID(ctx, "123")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
 func TestService_GetOrders_Negative_NilReference(t *testing.T) {
 	srv := testService(t, nil)
 	resp, err := srv.GetOrders(context.Background(), nil)
@@ -134,10 +729,8 @@ func TestService_GetOrders_Positive_NoContentError(t *testing.T) {
 
 	srv := testService(t, str)
 	resp, err := srv.GetOrders(ctx, http.NewPaginationOpts("", ""))
-	if assert.NotNil(t, resp) {
-		assert.Empty(t, resp)
-	}
 	assert.NoError(t, err)
+	assert.Equal(t, &model.GetOrdersResponse{Orders: []*model.OrderDTO{}, Limit: 1}, resp)
 }
 
 func TestService_GetOrders_Positive_1(t *testing.T) {
@@ -150,8 +743,11 @@ func TestService_GetOrders_Positive_1(t *testing.T) {
 
 	srv := testService(t, str)
 	resp, err := srv.GetOrders(ctx, http.NewPaginationOpts("", ""))
-	assert.Nil(t, resp)
 	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Empty(t, resp.Orders)
+		assert.Empty(t, resp.NextCursor)
+	}
 }
 
 func TestService_GetOrders_Positive_2(t *testing.T) {
@@ -160,7 +756,7 @@ func TestService_GetOrders_Positive_2(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	str := mocks.NewMockStore(ctrl)
 
-	str.EXPECT().GetOrders(ctx, 1, 0).Return([]*model.OrderDTO{
+	str.EXPECT().GetOrders(ctx, 2, 4).Return([]*model.OrderDTO{
 		{
 			OrderID:       1,
 			Weight:        2,
@@ -169,15 +765,76 @@ func TestService_GetOrders_Positive_2(t *testing.T) {
 			Cost:          3,
 			CompletedTime: datetime.Time{},
 		},
-		new(model.OrderDTO),
+		{OrderID: 5},
 	}, nil)
 
 	srv := testService(t, str)
-	resp, err := srv.GetOrders(ctx, http.NewPaginationOpts("", ""))
+	resp, err := srv.GetOrders(ctx, http.NewPaginationOpts("2", "4"))
+	assert.NoError(t, err)
 	if assert.NotNil(t, resp) {
-		assert.NotEmpty(t, resp)
+		assert.Len(t, resp.Orders, 2)
+		assert.Equal(t, 2, resp.Limit)
+		assert.Equal(t, 4, resp.Offset)
+		assert.Equal(t, model.EncodeCursor(5), resp.NextCursor)
 	}
+}
+
+func TestService_GetOrders_Positive_Cursor(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+
+	str.EXPECT().GetOrdersAfter(ctx, int64(5), 2).Return([]*model.OrderDTO{{OrderID: 6}}, nil)
+
+	srv := testService(t, str)
+	resp, err := srv.GetOrders(ctx, http.NewPaginationOpts("2", "").WithCursor(model.EncodeCursor(5)))
 	assert.NoError(t, err)
+	assert.Equal(t, &model.GetOrdersResponse{Orders: []*model.OrderDTO{{OrderID: 6}}, Limit: 2}, resp)
+}
+
+func TestService_GetOrders_Negative_BadCursor(t *testing.T) {
+	srv := testService(t, nil)
+	for name, opts := range map[string]*http.PaginationOpts{
+		"malformed":   http.NewPaginationOpts("", "").WithCursor("!"),
+		"with offset": http.NewPaginationOpts("", "1").WithCursor(model.EncodeCursor(5)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := srv.GetOrders(context.Background(), opts)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadRequest)
+		})
+	}
+}
+
+func TestService_CreateOrders_Negative_StoreFailure(t *testing.T) {
+	tt := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unavailable", &store.Error{Kind: store.ErrUnavailable}, ErrUnavailable},
+		{"check", &store.Error{Kind: store.ErrCheckViolation, Constraint: "check_numerics"}, ErrBadRequest},
+		{"internal", &store.Error{Kind: store.ErrInternal}, ErrInternal},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			str.EXPECT().CreateOrders(gomock.Any(), gomock.Any()).Return(tc.err)
+
+			resp, err := testService(t, str).CreateOrders(context.Background(), &model.CreateOrderRequest{
+				Orders: []model.CreateOrderDTO{{
+					Weight:        1,
+					Regions:       1,
+					DeliveryHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 12, End: 33}.TimeInterval()},
+					Cost:          1,
+				}},
+			})
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, tc.want)
+		})
+	}
 }
 
 func TestService_CreateOrders_Negative_NonValid(t *testing.T) {
@@ -269,6 +926,39 @@ func TestService_CreateOrders_Positive(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestService_CreateOrders_Split(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	str := mocks.NewMockStore(ctrl)
+	srv := testService(t, str)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	req := &model.CreateOrderRequest{
+		Orders: []model.CreateOrderDTO{
+			{Weight: 90, Regions: 1, DeliveryHours: hours, Cost: 100},
+		},
+	}
+
+	str.EXPECT().CreateOrders(ctx, gomock.Any()).Return(nil)
+
+	resp, err := srv.CreateOrders(ctx, req)
+	assert.NoError(t, err)
+	if assert.Len(t, resp, 1) {
+		order := resp[0]
+		assert.Equal(t, float64(90), order.Weight)
+		assert.Equal(t, int32(100), order.Cost)
+		if assert.Len(t, order.SubOrders, 3) {
+			for i, want := range []int32{34, 33, 33} {
+				assert.Equal(t, float64(30), order.SubOrders[i].Weight)
+				assert.Equal(t, want, order.SubOrders[i].Cost)
+				assert.Equal(t, int32(1), order.SubOrders[i].Regions)
+				assert.Equal(t, hours, order.SubOrders[i].DeliveryHours)
+			}
+		}
+	}
+}
+
 func TestService_CompleteOrders_NegativeBadRequest(t *testing.T) {
 	srv := testService(t, nil)
 	resp, err := srv.CompleteOrders(context.Background(), nil)
@@ -278,6 +968,37 @@ func TestService_CompleteOrders_NegativeBadRequest(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
+func TestService_CompleteOrders_CourierKey(t *testing.T) {
+	courier := int64(123)
+	ctx := auth.WithKey(context.Background(), &model.APIKey{KeyID: 1, Role: model.RoleCourier, CourierID: &courier})
+	now := datetime.Time(time.Now())
+	req := func(courierID int64) *model.CompleteOrderRequest {
+		return &model.CompleteOrderRequest{CompleteInfo: []model.CompleteOrder{
+			{CourierID: 123, OrderID: 321, CompleteTime: now},
+			{CourierID: courierID, OrderID: 322, CompleteTime: now},
+		}}
+	}
+	t.Run("orders of another courier", func(t *testing.T) {
+		resp, err := testService(t, nil).CompleteOrders(ctx, req(124))
+		assert.ErrorIs(t, err, ErrForbidden)
+		assert.Nil(t, resp)
+	})
+	t.Run("own orders", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		orders := []*model.OrderDTO{{OrderID: 321, Status: model.OrderStatusAssigned}, {OrderID: 322, Status: model.OrderStatusAssigned}}
+		gomock.InOrder(
+			str.EXPECT().GetOrdersByIDs(ctx, []int64{321, 322}).Return(orders, nil),
+			str.EXPECT().CompleteOrders(ctx, req(123).CompleteInfo).Return(nil),
+			str.EXPECT().GetOrdersByIDs(ctx, []int64{321, 322}).Return(orders, nil),
+		)
+
+		resp, err := testService(t, str).CompleteOrders(ctx, req(123))
+		assert.NoError(t, err)
+		assert.Equal(t, orders, resp)
+	})
+}
+
 func TestService_CompleteOrders_Negative_CompleteOrders(t *testing.T) {
 	ctx := context.Background()
 
@@ -295,6 +1016,7 @@ func TestService_CompleteOrders_Negative_CompleteOrders(t *testing.T) {
 		},
 	}
 
+	str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return([]*model.OrderDTO{{OrderID: 321, Status: model.OrderStatusAssigned}}, nil)
 	str.EXPECT().CompleteOrders(ctx, req.CompleteInfo).Return(errors.New(""))
 
 	resp, err := srv.CompleteOrders(ctx, req)
@@ -321,8 +1043,11 @@ func TestService_CompleteOrders_Negative_GetOrders(t *testing.T) {
 		},
 	}
 
-	str.EXPECT().CompleteOrders(ctx, req.CompleteInfo).Return(nil)
-	str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return(nil, errors.New(""))
+	gomock.InOrder(
+		str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return([]*model.OrderDTO{{OrderID: 321, Status: model.OrderStatusAssigned}}, nil),
+		str.EXPECT().CompleteOrders(ctx, req.CompleteInfo).Return(nil),
+		str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return(nil, errors.New("")),
+	)
 
 	resp, err := srv.CompleteOrders(ctx, req)
 	if assert.Error(t, err) {
@@ -358,8 +1083,11 @@ func TestService_CompleteOrders_Positive(t *testing.T) {
 		},
 	}
 
-	str.EXPECT().CompleteOrders(ctx, req.CompleteInfo).Return(nil)
-	str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return(expected, nil)
+	gomock.InOrder(
+		str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return([]*model.OrderDTO{{OrderID: 321, Status: model.OrderStatusInDelivery}}, nil),
+		str.EXPECT().CompleteOrders(ctx, req.CompleteInfo).Return(nil),
+		str.EXPECT().GetOrdersByIDs(ctx, []int64{321}).Return(expected, nil),
+	)
 
 	resp, err := srv.CompleteOrders(ctx, req)
 	assert.NoError(t, err)
@@ -367,3 +1095,240 @@ func TestService_CompleteOrders_Positive(t *testing.T) {
 		assert.Equal(t, expected, resp)
 	}
 }
+
+func TestService_CompleteOrders_Negative_Status(t *testing.T) {
+	req := &model.CompleteOrderRequest{
+		CompleteInfo: []model.CompleteOrder{{CourierID: 1, OrderID: 2, CompleteTime: datetime.Time(time.Now())}},
+	}
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersByIDs(gomock.Any(), []int64{2}).Return(nil, fmt.Errorf("order 2: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).CompleteOrders(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	for _, status := range []string{model.OrderStatusCreated, model.OrderStatusCancelled, model.OrderStatusFailed} {
+		t.Run(status, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			str.EXPECT().GetOrdersByIDs(gomock.Any(), []int64{2}).Return([]*model.OrderDTO{{OrderID: 2, Status: status}}, nil)
+
+			resp, err := testService(t, str).CompleteOrders(context.Background(), req)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadTransition)
+		})
+	}
+	t.Run("changed concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersByIDs(gomock.Any(), []int64{2}).Return([]*model.OrderDTO{{OrderID: 2, Status: model.OrderStatusAssigned}}, nil)
+		str.EXPECT().CompleteOrders(gomock.Any(), req.CompleteInfo).Return(fmt.Errorf("order 2 is FAILED: %w", store.ErrStatusChanged))
+
+		resp, err := testService(t, str).CompleteOrders(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadTransition)
+	})
+}
+
+func TestService_ChangeOrderStatus(t *testing.T) {
+	req := &model.ChangeOrderStatusRequest{Status: model.OrderStatusInDelivery}
+	t.Run("bad id", func(t *testing.T) {
+		resp, err := testService(t, nil).ChangeOrderStatus(context.Background(), "id", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("bad request", func(t *testing.T) {
+		resp, err := testService(t, nil).ChangeOrderStatus(context.Background(), "1", &model.ChangeOrderStatusRequest{Status: model.OrderStatusCompleted})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("order 1: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).ChangeOrderStatus(context.Background(), "1", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("bad transition", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(&model.OrderDTO{OrderID: 1, Status: model.OrderStatusCreated}, nil)
+
+		resp, err := testService(t, str).ChangeOrderStatus(context.Background(), "1", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadTransition)
+	})
+	t.Run("changed concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(&model.OrderDTO{OrderID: 1, Status: model.OrderStatusAssigned}, nil)
+		str.EXPECT().
+			ChangeOrderStatus(gomock.Any(), int64(1), int64(0), model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI).
+			Return(fmt.Errorf("order 1 is not ASSIGNED: %w", store.ErrStatusChanged))
+
+		resp, err := testService(t, str).ChangeOrderStatus(context.Background(), "1", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadTransition)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(&model.OrderDTO{OrderID: 1, Status: model.OrderStatusAssigned}, nil)
+		str.EXPECT().
+			ChangeOrderStatus(gomock.Any(), int64(1), int64(0), model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI).
+			Return(nil)
+
+		resp, err := testService(t, str).ChangeOrderStatus(context.Background(), "1", req)
+		require.NoError(t, err)
+		assert.Equal(t, &model.OrderDTO{OrderID: 1, Status: model.OrderStatusInDelivery}, resp)
+	})
+	t.Run("courier key", func(t *testing.T) {
+		courier := int64(2)
+		ctx := auth.WithKey(context.Background(), &model.APIKey{KeyID: 1, Role: model.RoleCourier, CourierID: &courier})
+
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(&model.OrderDTO{OrderID: 1, Status: model.OrderStatusAssigned}, nil)
+		str.EXPECT().
+			ChangeOrderStatus(gomock.Any(), int64(1), courier, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.CourierActor(courier)).
+			Return(nil)
+
+		resp, err := testService(t, str).ChangeOrderStatus(ctx, "1", req)
+		require.NoError(t, err)
+		assert.Equal(t, &model.OrderDTO{OrderID: 1, Status: model.OrderStatusInDelivery}, resp)
+	})
+	t.Run("order of another courier", func(t *testing.T) {
+		courier := int64(2)
+		ctx := auth.WithKey(context.Background(), &model.APIKey{KeyID: 1, Role: model.RoleCourier, CourierID: &courier})
+
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderByID(gomock.Any(), int64(1)).Return(&model.OrderDTO{OrderID: 1, Status: model.OrderStatusAssigned}, nil)
+		str.EXPECT().
+			ChangeOrderStatus(gomock.Any(), int64(1), courier, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.CourierActor(courier)).
+			Return(fmt.Errorf("order 1 of courier 2: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).ChangeOrderStatus(ctx, "1", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("courier key without courier", func(t *testing.T) {
+		ctx := auth.WithKey(context.Background(), &model.APIKey{KeyID: 1, Role: model.RoleCourier})
+
+		resp, err := testService(t, nil).ChangeOrderStatus(ctx, "1", req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrForbidden)
+	})
+}
+
+func TestService_GetOrderHistory(t *testing.T) {
+	t.Run("bad id", func(t *testing.T) {
+		resp, err := testService(t, nil).GetOrderHistory(context.Background(), "id")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrderHistory(gomock.Any(), int64(1)).Return(nil, fmt.Errorf("order 1: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).GetOrderHistory(context.Background(), "1")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		history := []model.OrderStatusChange{
+			{Status: model.OrderStatusCreated, Actor: model.ActorAPI, ChangedAt: datetime.Time(time.Now())},
+		}
+		str.EXPECT().GetOrderHistory(gomock.Any(), int64(1)).Return(history, nil)
+
+		resp, err := testService(t, str).GetOrderHistory(context.Background(), "1")
+		require.NoError(t, err)
+		assert.Equal(t, &model.OrderHistoryResponse{OrderID: 1, History: history}, resp)
+	})
+}
+
+func TestService_CancelOrders(t *testing.T) {
+	req := &model.CancelOrderRequest{OrderIDs: []int64{1, 2}, Reason: "withdrew"}
+	t.Run("bad request", func(t *testing.T) {
+		resp, err := testService(t, nil).CancelOrders(context.Background(), &model.CancelOrderRequest{OrderIDs: []int64{1}})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return(nil, fmt.Errorf("order 2: %w", store.ErrDoesNotExists))
+
+		resp, err := testService(t, str).CancelOrders(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("sub-order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return([]*model.OrderDTO{
+			{OrderID: 1, Status: model.OrderStatusCreated},
+			{OrderID: 2, Status: model.OrderStatusCreated, ParentOrderID: 1},
+		}, nil)
+
+		resp, err := testService(t, str).CancelOrders(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	for _, status := range []string{model.OrderStatusCompleted, model.OrderStatusInDelivery, model.OrderStatusFailed} {
+		t.Run(status, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			str := mocks.NewMockStore(ctrl)
+			str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return([]*model.OrderDTO{
+				{OrderID: 1, Status: model.OrderStatusAssigned},
+				{OrderID: 2, Status: status},
+			}, nil)
+
+			resp, err := testService(t, str).CancelOrders(context.Background(), req)
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadTransition)
+		})
+	}
+	t.Run("sub-order is completed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return([]*model.OrderDTO{
+			{OrderID: 1, Status: model.OrderStatusCreated},
+			{OrderID: 2, Status: model.OrderStatusCreated},
+		}, nil)
+		str.EXPECT().
+			CancelOrders(gomock.Any(), req.OrderIDs, req.Reason, model.ActorAPI).
+			Return(fmt.Errorf("order 3 is COMPLETED: %w", store.ErrStatusChanged))
+
+		resp, err := testService(t, str).CancelOrders(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadTransition)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		want := []*model.OrderDTO{
+			{OrderID: 1, Status: model.OrderStatusCancelled, CancelReason: req.Reason},
+			{OrderID: 2, Status: model.OrderStatusCancelled, CancelReason: req.Reason},
+		}
+		gomock.InOrder(
+			str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return([]*model.OrderDTO{
+				{OrderID: 1, Status: model.OrderStatusAssigned},
+				{OrderID: 2, Status: model.OrderStatusCancelled},
+			}, nil),
+			str.EXPECT().CancelOrders(gomock.Any(), req.OrderIDs, req.Reason, model.ActorAPI).Return(nil),
+			str.EXPECT().GetOrdersByIDs(gomock.Any(), req.OrderIDs).Return(want, nil),
+		)
+
+		resp, err := testService(t, str).CancelOrders(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+}
@@ -2,6 +2,8 @@ package production
 
 import (
 	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/zap"
@@ -17,32 +19,118 @@ type Store interface {
 	GetCourierByID(ctx context.Context, id int64) (*model.CourierDTO, error)
 	CreateCouriers(ctx context.Context, couriers []model.CreateCourierDTO) ([]model.CourierDTO, error)
 	GetCouriers(ctx context.Context, limit int, offset int) ([]model.CourierDTO, error)
+	// GetCouriersAfter returns at most limit couriers with id greater than afterID ordered by id.
+	GetCouriersAfter(ctx context.Context, afterID int64, limit int) ([]model.CourierDTO, error)
+	// GetActiveCouriers returns all couriers which are not deactivated ordered by id.
+	GetActiveCouriers(ctx context.Context) ([]model.CourierDTO, error)
+	// UpdateCourier changes fields of courier which are provided in request and returns updated courier.
+	UpdateCourier(ctx context.Context, id int64, req *model.UpdateCourierRequest) (*model.CourierDTO, error)
+	// DeactivateCourier marks courier as deactivated, courier stays in storage with its orders.
+	DeactivateCourier(ctx context.Context, id int64) error
 
 	// Order methods
 
 	GetOrderByID(ctx context.Context, id int64) (*model.OrderDTO, error)
 	GetOrders(ctx context.Context, limit int, offset int) ([]*model.OrderDTO, error)
+	// GetOrdersAfter returns at most limit orders with id greater than afterID ordered by id.
+	GetOrdersAfter(ctx context.Context, afterID int64, limit int) ([]*model.OrderDTO, error)
 	CreateOrders(ctx context.Context, orders []*model.OrderDTO) error
 	GetCompletedOrdersPriceByCourier(ctx context.Context, id int64, start time.Time, end time.Time) (sum int32, count int32, err error)
 	CompleteOrders(ctx context.Context, info []model.CompleteOrder) error
+	// CancelOrders cancels orders with their sub-orders and takes them back from couriers.
+	//
+	// Cancelling of already cancelled order changes nothing. If any order can not be cancelled from its status then
+	// store.ErrStatusChanged is returned and none of orders is cancelled.
+	CancelOrders(ctx context.Context, ids []int64, reason, actor string) error
 	GetOrdersByIDs(ctx context.Context, ids []int64) ([]*model.OrderDTO, error)
+	GetSubOrders(ctx context.Context, id int64) ([]*model.OrderDTO, error)
+	// ChangeOrderStatus changes status of order from one status to another and records change in history.
+	//
+	// If order has status other than from or is split into sub-orders then store.ErrStatusChanged is returned. If
+	// courier is not zero then order must be assigned to courier, otherwise store.ErrDoesNotExists is returned.
+	ChangeOrderStatus(ctx context.Context, id, courier int64, from, to, actor string) error
+	// GetOrderHistory returns changes of order status from the oldest one to the newest one.
+	GetOrderHistory(ctx context.Context, id int64) ([]model.OrderStatusChange, error)
+
+	// Assignment methods
+
+	GetUnassignedOrders(ctx context.Context) ([]*model.OrderDTO, error)
+	GetAssignedOrders(ctx context.Context, date string) ([]*model.OrderDTO, error)
+	WithAssignLock(ctx context.Context, date string, fn func(ctx context.Context) error) error
+	SaveOrdersAssign(ctx context.Context, resp *model.OrderAssignResponse) error
+	GetOrdersAssign(ctx context.Context, date string, courierID int64) (*model.OrderAssignResponse, error)
+
+	// Webhook methods
+
+	// CreateWebhook stores subscription of webhook and fills its id and creation time.
+	CreateWebhook(ctx context.Context, w *model.WebhookDTO) error
+	// GetWebhooks returns all active webhooks with their secrets ordered by id.
+	GetWebhooks(ctx context.Context) ([]model.WebhookDTO, error)
+	// DeleteWebhook deactivates webhook and fails its pending deliveries.
+	DeleteWebhook(ctx context.Context, id int64) error
+	// GetWebhookDeliveries returns page of deliveries of active webhook from the newest one.
+	GetWebhookDeliveries(ctx context.Context, id int64, limit int, offset int) ([]model.WebhookDelivery, error)
+	// ReplayWebhookDelivery schedules event of delivery to be delivered to its webhook again and returns new delivery.
+	ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (*model.WebhookDelivery, error)
+
+	// API key methods
+
+	// CreateAPIKey stores API key with hash and fills its id and creation time.
+	CreateAPIKey(ctx context.Context, key *model.APIKey, hash []byte) error
+	// GetAPIKeyByHash returns API key which is not revoked by hash of key.
+	GetAPIKeyByHash(ctx context.Context, hash []byte) (*model.APIKey, error)
+	// RevokeAPIKey revokes API key, revoked key stays in storage.
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+// Config configures service.
+type Config interface {
+	// DefaultStrategy returns name of assignment strategy which is used if request does not specify it.
+	DefaultStrategy() string
+	// SearchBudget returns time limit of search of assignment. Not positive budget disables limit.
+	SearchBudget() time.Duration
 }
 
 var _ controller.Service = (*Service)(nil)
 
 type Service struct {
-	log     *zap.Logger
-	storage Store
+	log       *zap.Logger
+	storage   Store
+	assigners map[string]assign.Assigner
+	strategy  string
+	budget    time.Duration
 }
 
 // New returns service with provided params.
-func New(log *zap.Logger, storage Store) (*Service, error) {
-	if log == nil || storage == nil {
+func New(log *zap.Logger, storage Store, cfg Config) (*Service, error) {
+	if log == nil || storage == nil || cfg == nil {
 		return nil, ErrNilReference
 	}
 	s := &Service{
-		log:     log,
-		storage: storage,
+		log:       log,
+		storage:   storage,
+		assigners: assign.Strategies,
+		strategy:  cfg.DefaultStrategy(),
+		budget:    cfg.SearchBudget(),
+	}
+	if _, ok := s.assigners[s.strategy]; !ok {
+		return nil, fmt.Errorf("%w: %q", assign.ErrUnknownStrategy, s.strategy)
 	}
 	return s, nil
 }
+
+// cursor returns id after which page of records starts if opts select page by cursor.
+//
+// Cursor can not be combined with offset.
+func cursor(opts model.PaginationOpts) (afterID int64, ok bool, err error) {
+	if opts.Cursor() == "" {
+		return 0, false, nil
+	}
+	if opts.Offset() != 0 {
+		return 0, false, ErrBadRequest.With(zap.String("cursor", opts.Cursor()), zap.Int("offset", opts.Offset()))
+	}
+	if afterID, err = model.DecodeCursor(opts.Cursor()); err != nil {
+		return 0, false, ErrBadRequest.With(zap.NamedError("cursor_error", err))
+	}
+	return afterID, true, nil
+}
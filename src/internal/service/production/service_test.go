@@ -2,44 +2,98 @@ package production
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production/mocks"
 	"go.uber.org/zap"
 	"testing"
+	"time"
 )
 
 func testService(t testing.TB, str Store) *Service {
 	t.Helper()
 	return &Service{
-		log:     zap.L(),
-		storage: str,
+		log:       zap.L(),
+		storage:   str,
+		assigners: assign.Strategies,
+		strategy:  assign.GreedyStrategy,
 	}
 }
 
+// testConfig is config with provided default strategy.
+type testConfig string
+
+func (cfg testConfig) DefaultStrategy() string {
+	return string(cfg)
+}
+
+func (cfg testConfig) SearchBudget() time.Duration {
+	return time.Second
+}
+
+// testAssignOpts is assign opts with provided fields.
+type testAssignOpts struct {
+	strategy    string
+	dryRun      bool
+	force       bool
+	incremental bool
+}
+
+func (opts testAssignOpts) Strategy() string {
+	return opts.strategy
+}
+
+func (opts testAssignOpts) DryRun() bool {
+	return opts.dryRun
+}
+
+func (opts testAssignOpts) Force() bool {
+	return opts.force
+}
+
+func (opts testAssignOpts) Incremental() bool {
+	return opts.incremental
+}
+
 func TestService_ImplementsInterface(t *testing.T) {
 	assert.Implements(t, new(controller.Service), new(Service))
 }
 
 func TestNew(t *testing.T) {
 	t.Run("nil logger", func(t *testing.T) {
-		s, err := New(nil, &mocks.MockStore{})
+		s, err := New(nil, &mocks.MockStore{}, testConfig(assign.GreedyStrategy))
 		assert.Nil(t, s)
 		if assert.Error(t, err) {
 			assert.ErrorIs(t, err, ErrNilReference)
 		}
 	})
 	t.Run("nil storage", func(t *testing.T) {
-		s, err := New(zap.L(), nil)
+		s, err := New(zap.L(), nil, testConfig(assign.GreedyStrategy))
+		assert.Nil(t, s)
+		if assert.Error(t, err) {
+			assert.ErrorIs(t, err, ErrNilReference)
+		}
+	})
+	t.Run("nil config", func(t *testing.T) {
+		s, err := New(zap.L(), &mocks.MockStore{}, nil)
 		assert.Nil(t, s)
 		if assert.Error(t, err) {
 			assert.ErrorIs(t, err, ErrNilReference)
 		}
 	})
+	t.Run("unknown strategy", func(t *testing.T) {
+		s, err := New(zap.L(), &mocks.MockStore{}, testConfig("unknown"))
+		assert.Nil(t, s)
+		if assert.Error(t, err) {
+			assert.ErrorIs(t, err, assign.ErrUnknownStrategy)
+		}
+	})
 	t.Run("positive", func(t *testing.T) {
-		s, err := New(zap.L(), &mocks.MockStore{})
+		s, err := New(zap.L(), &mocks.MockStore{}, testConfig(assign.OptimalStrategy))
 		assert.NoError(t, err)
 		if assert.NotNil(t, s) {
 			assert.Implements(t, new(controller.Service), s)
+			assert.Equal(t, assign.OptimalStrategy, s.strategy)
 		}
 	})
 }
@@ -0,0 +1,138 @@
+package production
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+// webhookSecretSize is number of random bytes in generated secret of webhook.
+const webhookSecretSize = 32
+
+// newWebhookSecret returns random secret of webhook encoded in hex.
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// CreateWebhook subscribes callback URL to events of types from request.
+//
+// If request does not contain secret then random one is generated. Secret is returned only by this method, clients
+// must keep it to verify signatures of deliveries.
+func (srv *Service) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.WebhookDTO, error) {
+	if !req.Valid() {
+		srv.log.Debug("request didn't pass validation")
+		return nil, ErrBadRequest
+	}
+
+	w := &model.WebhookDTO{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	}
+	if w.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, ErrInternal.With(zap.NamedError("secret_error", err))
+		}
+		w.Secret = secret
+	}
+	if err := srv.storage.CreateWebhook(ctx, w); err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
+	srv.log.Debug("webhook created", zap.Int64("webhook_id", w.WebhookID), zap.Strings("event_types", w.EventTypes))
+	return w, nil
+}
+
+// GetWebhooks returns all webhooks without their secrets.
+func (srv *Service) GetWebhooks(ctx context.Context) (*model.GetWebhooksResponse, error) {
+	webhooks, err := srv.storage.GetWebhooks(ctx)
+	if err != nil {
+		return nil, storeError(err, ErrBadRequest)
+	}
+	if webhooks == nil {
+		webhooks = []model.WebhookDTO{}
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return &model.GetWebhooksResponse{Webhooks: webhooks}, nil
+}
+
+// DeleteWebhook unsubscribes webhook with provided id from events.
+//
+// Pending deliveries of webhook are failed, delivered ones stay in history.
+func (srv *Service) DeleteWebhook(ctx context.Context, id string) error {
+	webhookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrBadRequest.With(zap.String("webhook_id", id))
+	}
+	if err = srv.storage.DeleteWebhook(ctx, webhookID); err != nil {
+		return storeError(err, ErrNotFound)
+	}
+	srv.log.Debug("webhook deleted", zap.Int64("webhook_id", webhookID))
+	return nil
+}
+
+// GetWebhookDeliveries returns deliveries of webhook from the newest one with pagination options.
+//
+// Page can be selected only by offset.
+func (srv *Service) GetWebhookDeliveries(ctx context.Context, id string, opts model.PaginationOpts) (*model.GetWebhookDeliveriesResponse, error) {
+	webhookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.String("webhook_id", id))
+	}
+	if opts == nil {
+		return nil, ErrBadRequest
+	}
+	if opts.Cursor() != "" {
+		return nil, ErrBadRequest.With(zap.String("cursor", opts.Cursor()))
+	}
+
+	deliveries, err := srv.storage.GetWebhookDeliveries(ctx, webhookID, opts.Limit(), opts.Offset())
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrDoesNotExists):
+			return nil, ErrNotFound.With(zap.NamedError("storage_error", err))
+		case !errors.Is(err, store.ErrNoContent):
+			return nil, storeError(err, ErrBadRequest)
+		}
+	}
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+	return &model.GetWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Limit:      opts.Limit(),
+		Offset:     opts.Offset(),
+	}, nil
+}
+
+// ReplayWebhookDelivery schedules event of delivery to be delivered to webhook again.
+//
+// Replay creates new pending delivery, delivery which is replayed is kept with its attempts.
+func (srv *Service) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID string) (*model.WebhookDelivery, error) {
+	wID, err := strconv.ParseInt(webhookID, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.String("webhook_id", webhookID))
+	}
+	dID, err := strconv.ParseInt(deliveryID, 10, 64)
+	if err != nil {
+		return nil, ErrBadRequest.With(zap.String("delivery_id", deliveryID))
+	}
+
+	d, err := srv.storage.ReplayWebhookDelivery(ctx, wID, dID)
+	if err != nil {
+		return nil, storeError(err, ErrNotFound)
+	}
+	srv.log.Debug("webhook delivery replayed", zap.Int64("delivery_id", dID), zap.Int64("replay_id", d.DeliveryID))
+	return d, nil
+}
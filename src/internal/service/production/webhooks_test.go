@@ -0,0 +1,197 @@
+package production
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/controller/http"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production/mocks"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	req := &model.CreateWebhookRequest{
+		URL:        "http://127.0.0.1:8081/events",
+		EventTypes: []string{model.EventOrderCreated, model.EventOrderCancelled},
+	}
+	t.Run("bad request", func(t *testing.T) {
+		resp, err := testService(t, nil).CreateWebhook(context.Background(), &model.CreateWebhookRequest{
+			URL:        "ftp://127.0.0.1/events",
+			EventTypes: req.EventTypes,
+		})
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("storage failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(fmt.Errorf("conn: %w", store.ErrUnavailable))
+
+		resp, err := testService(t, str).CreateWebhook(context.Background(), req)
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrUnavailable)
+	})
+	t.Run("generated secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, w *model.WebhookDTO) error {
+			w.WebhookID = 3
+			return nil
+		})
+
+		resp, err := testService(t, str).CreateWebhook(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.WebhookID)
+		assert.Equal(t, req.URL, resp.URL)
+		assert.Equal(t, req.EventTypes, resp.EventTypes)
+		secret, err := hex.DecodeString(resp.Secret)
+		require.NoError(t, err)
+		assert.Len(t, secret, webhookSecretSize)
+	})
+	t.Run("provided secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(nil)
+
+		withSecret := *req
+		withSecret.Secret = "0123456789abcdef"
+		resp, err := testService(t, str).CreateWebhook(context.Background(), &withSecret)
+		require.NoError(t, err)
+		assert.Equal(t, withSecret.Secret, resp.Secret)
+	})
+}
+
+func TestService_GetWebhooks(t *testing.T) {
+	t.Run("storage failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetWebhooks(gomock.Any()).Return(nil, errors.New(""))
+
+		resp, err := testService(t, str).GetWebhooks(context.Background())
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrBadRequest)
+	})
+	t.Run("empty", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetWebhooks(gomock.Any()).Return(nil, nil)
+
+		resp, err := testService(t, str).GetWebhooks(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &model.GetWebhooksResponse{Webhooks: []model.WebhookDTO{}}, resp)
+	})
+	t.Run("secrets are hidden", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetWebhooks(gomock.Any()).Return([]model.WebhookDTO{
+			{WebhookID: 1, URL: "http://a", Secret: "secret"},
+			{WebhookID: 2, URL: "http://b", Secret: "secret"},
+		}, nil)
+
+		resp, err := testService(t, str).GetWebhooks(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &model.GetWebhooksResponse{Webhooks: []model.WebhookDTO{
+			{WebhookID: 1, URL: "http://a"},
+			{WebhookID: 2, URL: "http://b"},
+		}}, resp)
+	})
+}
+
+func TestService_DeleteWebhook(t *testing.T) {
+	for _, id := range []string{"", "x", "1.5"} {
+		t.Run("bad id "+id, func(t *testing.T) {
+			assert.ErrorIs(t, testService(t, nil).DeleteWebhook(context.Background(), id), ErrBadRequest)
+		})
+	}
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().DeleteWebhook(gomock.Any(), int64(1)).Return(fmt.Errorf("webhook 1: %w", store.ErrDoesNotExists))
+
+		assert.ErrorIs(t, testService(t, str).DeleteWebhook(context.Background(), "1"), ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().DeleteWebhook(gomock.Any(), int64(1)).Return(nil)
+
+		assert.NoError(t, testService(t, str).DeleteWebhook(context.Background(), "1"))
+	})
+}
+
+func TestService_GetWebhookDeliveries(t *testing.T) {
+	t.Run("bad request", func(t *testing.T) {
+		srv := testService(t, nil)
+		for name, tc := range map[string]struct {
+			id   string
+			opts model.PaginationOpts
+		}{
+			"bad id":      {"x", http.NewPaginationOpts("", "")},
+			"nil options": {"1", nil},
+			"cursor":      {"1", http.NewPaginationOpts("", "").WithCursor(model.EncodeCursor(1))},
+		} {
+			t.Run(name, func(t *testing.T) {
+				resp, err := srv.GetWebhookDeliveries(context.Background(), tc.id, tc.opts)
+				assert.Nil(t, resp)
+				assert.ErrorIs(t, err, ErrBadRequest)
+			})
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().GetWebhookDeliveries(gomock.Any(), int64(1), 1, 0).Return(nil, store.ErrDoesNotExists)
+
+		resp, err := testService(t, str).GetWebhookDeliveries(context.Background(), "1", http.NewPaginationOpts("", ""))
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		deliveries := []model.WebhookDelivery{{DeliveryID: 3, WebhookID: 1}, {DeliveryID: 2, WebhookID: 1}}
+		str.EXPECT().GetWebhookDeliveries(gomock.Any(), int64(1), 2, 4).Return(deliveries, nil)
+
+		resp, err := testService(t, str).GetWebhookDeliveries(context.Background(), "1", http.NewPaginationOpts("2", "4"))
+		require.NoError(t, err)
+		assert.Equal(t, &model.GetWebhookDeliveriesResponse{Deliveries: deliveries, Limit: 2, Offset: 4}, resp)
+	})
+}
+
+func TestService_ReplayWebhookDelivery(t *testing.T) {
+	for name, ids := range map[string][2]string{
+		"bad webhook id":  {"x", "1"},
+		"bad delivery id": {"1", "x"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, err := testService(t, nil).ReplayWebhookDelivery(context.Background(), ids[0], ids[1])
+			assert.Nil(t, resp)
+			assert.ErrorIs(t, err, ErrBadRequest)
+		})
+	}
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		str.EXPECT().ReplayWebhookDelivery(gomock.Any(), int64(1), int64(2)).Return(nil, store.ErrDoesNotExists)
+
+		resp, err := testService(t, str).ReplayWebhookDelivery(context.Background(), "1", "2")
+		assert.Nil(t, resp)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+	t.Run("positive", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		str := mocks.NewMockStore(ctrl)
+		want := &model.WebhookDelivery{DeliveryID: 5, WebhookID: 1, Status: model.DeliveryStatusPending}
+		str.EXPECT().ReplayWebhookDelivery(gomock.Any(), int64(1), int64(2)).Return(want, nil)
+
+		resp, err := testService(t, str).ReplayWebhookDelivery(context.Background(), "1", "2")
+		require.NoError(t, err)
+		assert.Equal(t, want, resp)
+	})
+}
@@ -1,8 +1,61 @@
 package store
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrNoContent     = errors.New("")
 	ErrDoesNotExists = errors.New("record does not exists")
+	// ErrAlreadyAssigned is returned when order was assigned or completed by someone else during assignment.
+	ErrAlreadyAssigned = errors.New("order is already assigned")
+	// ErrStatusChanged is returned when status of order is not the one which change was made from.
+	ErrStatusChanged = errors.New("order status was changed")
+	// ErrUniqueViolation is returned when record duplicates unique key of existing one.
+	ErrUniqueViolation = errors.New("record violates unique constraint")
+	// ErrForeignKeyViolation is returned when record references record which does not exist.
+	ErrForeignKeyViolation = errors.New("record violates foreign key constraint")
+	// ErrCheckViolation is returned when record or argument of query has value which storage does not accept.
+	ErrCheckViolation = errors.New("record violates check constraint")
+	// ErrSerialization is returned when transaction conflicts with concurrent one and can be retried.
+	ErrSerialization = errors.New("could not serialize access due to concurrent update")
+	// ErrUnavailable is returned when storage can not be reached.
+	ErrUnavailable = errors.New("storage is unavailable")
+	// ErrInternal is returned when storage failed by reason which is not caused by request.
+	ErrInternal = errors.New("internal storage error")
 )
+
+// Error is error of storage of known kind.
+//
+// errors.Is matches both its kind and original error.
+type Error struct {
+	// Kind is one of errors of this package.
+	Kind error
+	// Constraint is name of violated constraint if storage reports it.
+	Constraint string
+	// Err is original error.
+	Err error
+}
+
+// Error returns error message.
+func (e *Error) Error() string {
+	if e.Constraint != "" {
+		return fmt.Sprintf("%v: %s: %v", e.Kind, e.Constraint, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Unwrap returns kind and original error.
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// Constraint returns name of constraint violated in err or empty string if there is no such.
+func Constraint(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Constraint
+	}
+	return ""
+}
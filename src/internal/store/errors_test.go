@@ -0,0 +1,25 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	cause := errors.New("cause")
+	err := fmt.Errorf("insert: %w", &Error{Kind: ErrUniqueViolation, Constraint: "couriers_pkey", Err: cause})
+
+	assert.ErrorIs(t, err, ErrUniqueViolation)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, ErrCheckViolation)
+	assert.Equal(t, "insert: record violates unique constraint: couriers_pkey: cause", err.Error())
+	assert.Equal(t, "storage is unavailable: cause", (&Error{Kind: ErrUnavailable, Err: cause}).Error())
+}
+
+func TestConstraint(t *testing.T) {
+	assert.Equal(t, "couriers_pkey", Constraint(fmt.Errorf("insert: %w", &Error{Kind: ErrUniqueViolation, Constraint: "couriers_pkey"})))
+	assert.Empty(t, Constraint(&Error{Kind: ErrUnavailable}))
+	assert.Empty(t, Constraint(errors.New("other")))
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"time"
+)
+
+// apiKeyDTO returns copy of key.
+func apiKeyDTO(k *apiKey) *model.APIKey {
+	res := k.APIKey
+	if k.CourierID != nil {
+		id := *k.CourierID
+		res.CourierID = &id
+	}
+	return &res
+}
+
+// CreateAPIKey stores API key with hash and fills its id and creation time.
+//
+// If key belongs to courier who does not exist then store.ErrForeignKeyViolation is returned.
+func (s *Store) CreateAPIKey(_ context.Context, key *model.APIKey, hash []byte) error {
+	if key == nil {
+		return ErrNilReference
+	}
+	if !model.ValidRole(key.Role) || (key.Role == model.RoleCourier) != (key.CourierID != nil) {
+		return fmt.Errorf("api key with role %q: %w", key.Role, store.ErrCheckViolation)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key.CourierID != nil {
+		if _, ok := s.couriers[*key.CourierID]; !ok {
+			return fmt.Errorf("courier %d: %w", *key.CourierID, store.ErrForeignKeyViolation)
+		}
+	}
+	if _, ok := s.apiKeyIndex[string(hash)]; ok {
+		return fmt.Errorf("api key hash: %w", store.ErrUniqueViolation)
+	}
+	key.KeyID = int64(len(s.apiKeys)) + 1
+	key.CreatedAt = datetime.Time(time.Now())
+	stored := &apiKey{APIKey: *key, hash: string(hash)}
+	stored.APIKey = *apiKeyDTO(stored)
+	s.apiKeys = append(s.apiKeys, stored)
+	s.apiKeyIndex[stored.hash] = stored
+	return nil
+}
+
+// GetAPIKeyByHash returns API key which is not revoked by hash of key.
+//
+// If there is no such key then store.ErrDoesNotExists is returned.
+func (s *Store) GetAPIKeyByHash(_ context.Context, hash []byte) (*model.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	k, ok := s.apiKeyIndex[string(hash)]
+	if !ok || k.revoked {
+		return nil, fmt.Errorf("api key: %w", store.ErrDoesNotExists)
+	}
+	return apiKeyDTO(k), nil
+}
+
+// RevokeAPIKey revokes API key, revoked key stays in storage.
+//
+// If key does not exist or is already revoked then store.ErrDoesNotExists is returned.
+func (s *Store) RevokeAPIKey(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id <= 0 || id > int64(len(s.apiKeys)) || s.apiKeys[id-1].revoked {
+		return fmt.Errorf("api key %d: %w", id, store.ErrDoesNotExists)
+	}
+	s.apiKeys[id-1].revoked = true
+	return nil
+}
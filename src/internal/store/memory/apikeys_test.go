@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestStore_CreateAPIKey_Negative(t *testing.T) {
+	s := New()
+	courier := int64(1)
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), nil, []byte("hash")), ErrNilReference)
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: "root"}, []byte("hash")), store.ErrCheckViolation)
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleCourier}, []byte("hash")), store.ErrCheckViolation)
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleCourier, CourierID: &courier}, []byte("hash")), store.ErrForeignKeyViolation)
+
+	require.NoError(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleAdmin}, []byte("hash")))
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleAdmin}, []byte("hash")), store.ErrUniqueViolation)
+}
+
+func TestStore_GetAPIKeyByHash_Copy(t *testing.T) {
+	s := New()
+	_, err := s.CreateCouriers(context.Background(), []model.CreateCourierDTO{{CourierType: model.FootCourierTypeString, Regions: []int32{1}}})
+	require.NoError(t, err)
+	courier := int64(1)
+	require.NoError(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleCourier, CourierID: &courier}, []byte("hash")))
+
+	key, err := s.GetAPIKeyByHash(context.Background(), []byte("hash"))
+	require.NoError(t, err)
+	*key.CourierID = 2
+	key, err = s.GetAPIKeyByHash(context.Background(), []byte("hash"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), *key.CourierID, "returned key must not share memory with stored one")
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+)
+
+// GetUnassignedOrders returns orders which wait for assignment.
+//
+// Orders which were split into sub-orders are never assigned, their sub-orders are returned instead.
+func (s *Store) GetUnassignedOrders(_ context.Context) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]*model.OrderDTO, 0)
+	for _, id := range s.orderIDs {
+		o := s.orders[id]
+		if o.Status == model.OrderStatusCreated && len(s.subOrders[id]) == 0 {
+			res = append(res, o.dto())
+		}
+	}
+	return res, nil
+}
+
+// GetAssignedOrders returns orders which were assigned at date and which courier has not taken yet.
+func (s *Store) GetAssignedOrders(_ context.Context, date string) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]*model.OrderDTO, 0)
+	for _, id := range s.orderIDs {
+		o := s.orders[id]
+		if g, ok := s.groups[o.group]; ok && g.date == date && o.Status == model.OrderStatusAssigned {
+			res = append(res, o.dto())
+		}
+	}
+	return res, nil
+}
+
+// WithAssignLock runs fn while holding lock of assignment at date.
+//
+// If ctx is done before lock is taken then error of ctx is returned. Lock is released when fn returns.
+func (s *Store) WithAssignLock(ctx context.Context, date string, fn func(ctx context.Context) error) error {
+	if fn == nil {
+		return ErrNilReference
+	}
+
+	s.locksMu.Lock()
+	lock, ok := s.locks[date]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.locks[date] = lock
+	}
+	s.locksMu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("unable to take assign lock: %w", ctx.Err())
+	}
+	defer func() {
+		<-lock
+	}()
+
+	return fn(ctx)
+}
+
+// releaseOrdersAssign takes back orders which were assigned at date and which courier has not taken yet and deletes
+// emptied groups.
+//
+// Groups with orders taken by courier and groups with provided ids are kept.
+func (s *Store) releaseOrdersAssign(u *undo, date string, keep map[int64]bool) {
+	used := make(map[int64]bool)
+	for _, o := range s.orders {
+		g, ok := s.groups[o.group]
+		if !ok || g.date != date {
+			continue
+		}
+		if o.Status != model.OrderStatusAssigned || keep[o.group] {
+			used[o.group] = true
+			continue
+		}
+		u.order(o)
+		o.courier, o.group, o.DeliveryTime = 0, 0, nil
+		s.setStatus(o, model.OrderStatusCreated, model.ActorAssignment)
+	}
+	for id, g := range s.groups {
+		if g.date == date && !used[id] {
+			u.group(id)
+			delete(s.groups, id)
+		}
+	}
+}
+
+// saveGroup stores group of orders of courier and fills created group id.
+//
+// Group with non-zero id must already exist at date, it is updated and new orders are added to it. If any new order of
+// group is already assigned to another group or does not wait for assignment then store.ErrAlreadyAssigned will be
+// returned.
+func (s *Store) saveGroup(u *undo, date string, courier int64, g *model.GroupOrders) error {
+	if g.GroupOrderID != 0 {
+		stored, ok := s.groups[g.GroupOrderID]
+		if !ok || stored.date != date || stored.courier != courier {
+			return fmt.Errorf("group %d: %w", g.GroupOrderID, store.ErrAlreadyAssigned)
+		}
+		u.group(stored.id)
+		stored.window = g.DeliveryWindow
+	} else {
+		if _, ok := s.couriers[courier]; !ok {
+			return fmt.Errorf("courier %d: %w", courier, store.ErrForeignKeyViolation)
+		}
+		s.groupSeq++
+		u.group(s.groupSeq)
+		s.groups[s.groupSeq] = &group{id: s.groupSeq, date: date, courier: courier, window: g.DeliveryWindow}
+		g.GroupOrderID = s.groupSeq
+	}
+
+	events := make([]any, 0, len(g.Orders))
+	for _, o := range g.Orders {
+		stored, ok := s.orders[o.OrderID]
+		if !ok || !((stored.group == 0 && stored.Status == model.OrderStatusCreated) || stored.group == g.GroupOrderID) {
+			return fmt.Errorf("order %d: %w", o.OrderID, store.ErrAlreadyAssigned)
+		}
+		u.order(stored)
+		assigned := stored.group == 0
+		if assigned {
+			s.setStatus(stored, model.OrderStatusAssigned, model.ActorAssignment)
+		}
+		stored.courier, stored.group, stored.UnassignedReason = courier, g.GroupOrderID, ""
+		stored.DeliveryTime = nil
+		if o.DeliveryTime != nil {
+			t := *o.DeliveryTime
+			stored.DeliveryTime = &t
+		}
+		if assigned {
+			events = append(events, &model.OrderAssignedEvent{
+				OrderID:      stored.OrderID,
+				CourierID:    courier,
+				GroupOrderID: g.GroupOrderID,
+				Region:       stored.Regions,
+				Date:         date,
+				DeliveryTime: stored.DeliveryTime,
+			})
+		}
+	}
+	return s.publish(model.EventOrderAssigned, events...)
+}
+
+// SaveOrdersAssign stores all groups of orders from assignment and reasons of unassigned orders or nothing.
+//
+// If orders were already assigned at date then previous assignment is replaced: orders which courier has not taken yet
+// are taken back from their groups before new groups are stored. Groups of response with non-zero id are kept and extended with
+// new orders. Ids of created groups are written into provided response.
+func (s *Store) SaveOrdersAssign(_ context.Context, resp *model.OrderAssignResponse) (err error) {
+	if resp == nil {
+		return ErrNilReference
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.begin()
+	defer func() {
+		if err != nil {
+			u.rollback()
+		}
+	}()
+
+	u.assignment(resp.Date)
+	s.assignments[resp.Date] = assignment{strategy: resp.Strategy}
+
+	keep := make(map[int64]bool)
+	for _, courier := range resp.Couriers {
+		for _, g := range courier.Orders {
+			if g.GroupOrderID != 0 {
+				keep[g.GroupOrderID] = true
+			}
+		}
+	}
+	s.releaseOrdersAssign(u, resp.Date, keep)
+
+	for i := range resp.Couriers {
+		courier := &resp.Couriers[i]
+		for j := range courier.Orders {
+			if err = s.saveGroup(u, resp.Date, courier.CourierID, &courier.Orders[j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, unassigned := range resp.Unassigned {
+		if o, ok := s.orders[unassigned.OrderID]; ok {
+			u.order(o)
+			o.UnassignedReason = unassigned.Reason
+		}
+	}
+	return s.publish(model.EventOrdersAssigned, resp)
+}
+
+// GetOrdersAssign returns groups of orders that were assigned at date with strategy which was used.
+//
+// If courierID is zero then groups of all couriers will be returned. If orders were never assigned at date then
+// store.ErrDoesNotExists will be returned. Couriers without groups are not included into response.
+func (s *Store) GetOrdersAssign(_ context.Context, date string, courierID int64) (*model.OrderAssignResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.assignments[date]
+	if !ok {
+		return nil, store.ErrDoesNotExists
+	}
+
+	byGroup := make(map[int64][]*order)
+	for _, o := range s.orders {
+		if g, ok := s.groups[o.group]; ok && g.date == date && (courierID == 0 || g.courier == courierID) {
+			byGroup[o.group] = append(byGroup[o.group], o)
+		}
+	}
+	groups := make([]*group, 0, len(byGroup))
+	for id := range byGroup {
+		groups = append(groups, s.groups[id])
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].courier != groups[j].courier {
+			return groups[i].courier < groups[j].courier
+		}
+		return groups[i].id < groups[j].id
+	})
+
+	resp := &model.OrderAssignResponse{
+		Date:     date,
+		Strategy: a.strategy,
+		Couriers: []model.CourierGroupOrders{},
+	}
+	for _, g := range groups {
+		if n := len(resp.Couriers); n == 0 || resp.Couriers[n-1].CourierID != g.courier {
+			resp.Couriers = append(resp.Couriers, model.CourierGroupOrders{CourierID: g.courier})
+		}
+		courier := &resp.Couriers[len(resp.Couriers)-1]
+
+		orders := byGroup[g.id]
+		sort.Slice(orders, func(i, j int) bool {
+			oi, oj := offset(g, orders[i]), offset(g, orders[j])
+			if oi != oj {
+				return oi < oj
+			}
+			return orders[i].OrderID < orders[j].OrderID
+		})
+		res := model.GroupOrders{GroupOrderID: g.id, DeliveryWindow: g.window}
+		for _, o := range orders {
+			res.Orders = append(res.Orders, *o.dto())
+		}
+		courier.Orders = append(courier.Orders, res)
+	}
+	return resp, nil
+}
+
+// offset returns minutes between start of group delivery window and delivery of order.
+func offset(g *group, o *order) datetime.Minute {
+	var start, delivery datetime.Minute
+	if g.window != nil {
+		start = g.window.Start()
+	}
+	if o.DeliveryTime != nil {
+		delivery = *o.DeliveryTime
+	}
+	return (delivery - start + 1440) % 1440
+}
@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func minute(m datetime.Minute) *datetime.Minute {
+	return &m
+}
+
+func TestStore_SaveOrdersAssign(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+
+	_, err = s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+
+	window := datetime.TimeIntervalAlias{Start: 600, End: 700}.TimeInterval()
+	resp := &model.OrderAssignResponse{
+		Date:     "2023-01-01",
+		Strategy: "greedy",
+		Couriers: []model.CourierGroupOrders{
+			{CourierID: 2, Orders: []model.GroupOrders{{
+				DeliveryWindow: window,
+				Orders: []model.OrderDTO{
+					{OrderID: 3, DeliveryTime: minute(650)},
+					{OrderID: 1, DeliveryTime: minute(610)},
+				},
+			}}},
+			{CourierID: 1, Orders: []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 4}}}}},
+		},
+		Unassigned: []model.UnassignedOrder{{OrderID: 5, Reason: "overweight"}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, resp))
+	assert.NotZero(t, resp.Couriers[0].Orders[0].GroupOrderID)
+	assert.NotZero(t, resp.Couriers[1].Orders[0].GroupOrderID)
+
+	got, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "greedy", got.Strategy)
+	if assert.Len(t, got.Couriers, 2) {
+		assert.Equal(t, int64(1), got.Couriers[0].CourierID)
+		assert.Equal(t, int64(2), got.Couriers[1].CourierID)
+		group := got.Couriers[1].Orders[0]
+		assert.Equal(t, window, group.DeliveryWindow)
+		if assert.Len(t, group.Orders, 2) {
+			assert.Equal(t, int64(1), group.Orders[0].OrderID)
+			assert.Equal(t, int64(3), group.Orders[1].OrderID)
+		}
+	}
+
+	got, err = s.GetOrdersAssign(ctx, "2023-01-01", 1)
+	require.NoError(t, err)
+	assert.Len(t, got.Couriers, 1)
+	got, err = s.GetOrdersAssign(ctx, "2023-01-01", 3)
+	require.NoError(t, err)
+	assert.NotNil(t, got.Couriers)
+	assert.Empty(t, got.Couriers)
+
+	o, err := s.GetOrderByID(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "overweight", o.UnassignedReason)
+
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, unassigned, 1) {
+		assert.Equal(t, int64(5), unassigned[0].OrderID)
+	}
+	assigned, err := s.GetAssignedOrders(ctx, "2023-01-01")
+	require.NoError(t, err)
+	assert.Len(t, assigned, 3)
+	assigned, err = s.GetAssignedOrders(ctx, "2023-01-02")
+	require.NoError(t, err)
+	assert.Empty(t, assigned)
+}
+
+func TestStore_SaveOrdersAssign_Replace(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+
+	first := assignAll(t, s, "2023-01-01", 2)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(time.Now())},
+	}))
+
+	// not completed orders of replaced assignment are taken back.
+	require.NoError(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{
+		Date:     "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{CourierID: 1, Orders: []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 3}}}}}},
+	}))
+	got, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, got.Couriers, 2) {
+		completed := got.Couriers[1].Orders[0]
+		assert.Equal(t, first.Couriers[0].Orders[0].GroupOrderID, completed.GroupOrderID)
+		if assert.Len(t, completed.Orders, 1) {
+			assert.Equal(t, int64(1), completed.Orders[0].OrderID)
+		}
+	}
+
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, unassigned, 2)
+}
+
+func TestStore_SaveOrdersAssign_Extend(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()[:1]))
+
+	first := assignAll(t, s, "2023-01-01", 2)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()[:1]))
+
+	group := first.Couriers[0].Orders[0]
+	group.Orders = append(group.Orders, model.OrderDTO{OrderID: 2})
+	require.NoError(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{
+		Date:     "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{CourierID: 2, Orders: []model.GroupOrders{group}}},
+	}))
+
+	got, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, got.Couriers, 1) && assert.Len(t, got.Couriers[0].Orders, 1) {
+		assert.Equal(t, group.GroupOrderID, got.Couriers[0].Orders[0].GroupOrderID)
+		assert.Len(t, got.Couriers[0].Orders[0].Orders, 2)
+	}
+}
+
+func TestStore_SaveOrdersAssign_Negative(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	assert.ErrorIs(t, s.SaveOrdersAssign(ctx, nil), ErrNilReference)
+
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+	first := assignAll(t, s, "2023-01-01", 2)
+
+	tt := []struct {
+		name string
+		resp *model.OrderAssignResponse
+		err  error
+	}{
+		{
+			name: "order of another date",
+			resp: &model.OrderAssignResponse{
+				Date:     "2023-01-02",
+				Couriers: []model.CourierGroupOrders{{CourierID: 1, Orders: []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 1}}}}}},
+			},
+			err: store.ErrAlreadyAssigned,
+		},
+		{
+			name: "unknown order",
+			resp: &model.OrderAssignResponse{
+				Date:     "2023-01-01",
+				Couriers: []model.CourierGroupOrders{{CourierID: 1, Orders: []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 100}}}}}},
+			},
+			err: store.ErrAlreadyAssigned,
+		},
+		{
+			name: "group of another courier",
+			resp: &model.OrderAssignResponse{
+				Date: "2023-01-01",
+				Couriers: []model.CourierGroupOrders{{CourierID: 1, Orders: []model.GroupOrders{{
+					GroupOrderID: first.Couriers[0].Orders[0].GroupOrderID,
+				}}}},
+			},
+			err: store.ErrAlreadyAssigned,
+		},
+		{
+			name: "unknown courier",
+			resp: &model.OrderAssignResponse{
+				Date:     "2023-01-01",
+				Couriers: []model.CourierGroupOrders{{CourierID: 100, Orders: []model.GroupOrders{{Orders: []model.OrderDTO{{OrderID: 1}}}}}},
+			},
+			err: store.ErrForeignKeyViolation,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			before, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, s.SaveOrdersAssign(ctx, tc.resp), tc.err)
+
+			after, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+			require.NoError(t, err)
+			assert.Equal(t, before, after)
+			_, err = s.GetOrdersAssign(ctx, "2023-01-02", 0)
+			assert.ErrorIs(t, err, store.ErrDoesNotExists)
+		})
+	}
+}
+
+func TestStore_WithAssignLock(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	assert.ErrorIs(t, s.WithAssignLock(ctx, "2023-01-01", nil), ErrNilReference)
+
+	var (
+		wg      sync.WaitGroup
+		running int32
+		max     int32
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.WithAssignLock(ctx, "2023-01-01", func(context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&max)
+					if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), max)
+
+	// lock of another date is independent.
+	assert.NoError(t, s.WithAssignLock(ctx, "2023-01-01", func(ctx context.Context) error {
+		return s.WithAssignLock(ctx, "2023-01-02", func(context.Context) error { return nil })
+	}))
+
+	// ctx is checked while waiting for lock.
+	assert.NoError(t, s.WithAssignLock(ctx, "2023-01-01", func(context.Context) error {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		err := s.WithAssignLock(cancelled, "2023-01-01", func(context.Context) error { return nil })
+		assert.ErrorIs(t, err, context.Canceled)
+		return nil
+	}))
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+)
+
+// courierDTO returns copy of stored courier.
+func courierDTO(c *model.CourierDTO) model.CourierDTO {
+	res := *c
+	res.Regions = make([]int32, len(c.Regions))
+	copy(res.Regions, c.Regions)
+	res.WorkingHours = intervals(c.WorkingHours)
+	return res
+}
+
+func (s *Store) GetCourierByID(_ context.Context, id int64) (*model.CourierDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.couriers[id]
+	if !ok {
+		return nil, fmt.Errorf("courier %d: %w", id, store.ErrDoesNotExists)
+	}
+	res := courierDTO(c)
+	return &res, nil
+}
+
+// CreateCouriers stores all couriers or none of them and returns them with ids in order of input.
+func (s *Store) CreateCouriers(_ context.Context, couriers []model.CreateCourierDTO) (res []model.CourierDTO, err error) {
+	for _, c := range couriers {
+		if err = checkCourierType(c.CourierType); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.begin()
+	defer func() {
+		if err != nil {
+			u.rollback()
+		}
+	}()
+
+	res = make([]model.CourierDTO, 0, len(couriers))
+	for _, c := range couriers {
+		s.courierSeq++
+		courier := &model.CourierDTO{
+			CourierID:    s.courierSeq,
+			CourierType:  c.CourierType,
+			Regions:      c.Regions,
+			WorkingHours: c.WorkingHours,
+			Active:       true,
+		}
+		stored := courierDTO(courier)
+		s.couriers[courier.CourierID] = &stored
+		s.courierIDs = append(s.courierIDs, courier.CourierID)
+		res = append(res, *courier)
+	}
+	events := make([]any, 0, len(res))
+	for i := range res {
+		events = append(events, &res[i])
+	}
+	if err = s.publish(model.EventCourierCreated, events...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetCouriers returns page of active couriers ordered by id.
+func (s *Store) GetCouriers(_ context.Context, limit int, offset int) ([]model.CourierDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := s.activeCourierIDs()
+	from, to, err := page(len(ids), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get couriers: %w", err)
+	}
+	return s.couriersByIDs(ids[from:to]), nil
+}
+
+// GetCouriersAfter returns at most limit active couriers with id greater than afterID ordered by id.
+func (s *Store) GetCouriersAfter(_ context.Context, afterID int64, limit int) ([]model.CourierDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids, err := after(s.activeCourierIDs(), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get couriers: %w", err)
+	}
+	return s.couriersByIDs(ids), nil
+}
+
+// GetActiveCouriers returns all couriers which are not deactivated ordered by id.
+func (s *Store) GetActiveCouriers(_ context.Context) ([]model.CourierDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.couriersByIDs(s.activeCourierIDs()), nil
+}
+
+// UpdateCourier changes fields of courier which are provided in request and returns updated courier.
+//
+// Provided regions and working hours replace stored ones. If courier does not exist then store.ErrDoesNotExists is
+// returned.
+func (s *Store) UpdateCourier(_ context.Context, id int64, req *model.UpdateCourierRequest) (*model.CourierDTO, error) {
+	if req == nil {
+		return nil, ErrNilReference
+	}
+	if req.CourierType != nil {
+		if err := checkCourierType(*req.CourierType); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.couriers[id]
+	if !ok {
+		return nil, fmt.Errorf("courier %d: %w", id, store.ErrDoesNotExists)
+	}
+	updated := *c
+	if req.CourierType != nil {
+		updated.CourierType = *req.CourierType
+	}
+	if req.Regions != nil {
+		updated.Regions = req.Regions
+	}
+	if req.WorkingHours != nil {
+		updated.WorkingHours = req.WorkingHours
+	}
+	stored := courierDTO(&updated)
+	s.couriers[id] = &stored
+
+	res := courierDTO(&stored)
+	return &res, nil
+}
+
+// DeactivateCourier marks courier as deactivated. Deactivation of deactivated courier does nothing.
+//
+// If courier does not exist then store.ErrDoesNotExists is returned.
+func (s *Store) DeactivateCourier(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.couriers[id]
+	if !ok {
+		return fmt.Errorf("courier %d: %w", id, store.ErrDoesNotExists)
+	}
+	c.Active = false
+	return nil
+}
+
+// activeCourierIDs returns ids of couriers which are not deactivated ordered by id.
+func (s *Store) activeCourierIDs() []int64 {
+	res := make([]int64, 0, len(s.courierIDs))
+	for _, id := range s.courierIDs {
+		if s.couriers[id].Active {
+			res = append(res, id)
+		}
+	}
+	return res
+}
+
+// checkCourierType returns store.ErrCheckViolation if courier type is unknown.
+func checkCourierType(t string) error {
+	switch t {
+	case model.FootCourierTypeString, model.BikeCourierTypeString, model.AutoCourierTypeString:
+		return nil
+	}
+	return fmt.Errorf("courier type %q: %w", t, store.ErrCheckViolation)
+}
+
+func (s *Store) couriersByIDs(ids []int64) []model.CourierDTO {
+	res := make([]model.CourierDTO, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, courierDTO(s.couriers[id]))
+	}
+	return res
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func testCouriers() []model.CreateCourierDTO {
+	return []model.CreateCourierDTO{
+		{
+			CourierType:  model.FootCourierTypeString,
+			Regions:      []int32{1},
+			WorkingHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 720}.TimeInterval()},
+		},
+		{
+			CourierType:  model.AutoCourierTypeString,
+			Regions:      []int32{1, 2, 3},
+			WorkingHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 540, End: 1200}.TimeInterval()},
+		},
+		{
+			CourierType:  model.BikeCourierTypeString,
+			Regions:      []int32{},
+			WorkingHours: []*datetime.TimeInterval{},
+		},
+	}
+}
+
+func TestStore_CreateCouriers(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	resp, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.Len(t, resp, 3)
+	for i, c := range resp {
+		assert.Equal(t, int64(i+1), c.CourierID)
+		got, err := s.GetCourierByID(ctx, c.CourierID)
+		require.NoError(t, err)
+		assert.Equal(t, c, *got)
+	}
+
+	// returned courier must not share memory with stored one.
+	got, err := s.GetCourierByID(ctx, 2)
+	require.NoError(t, err)
+	got.Regions[0] = 100
+	got, err = s.GetCourierByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{1, 2, 3}, got.Regions)
+}
+
+func TestStore_CreateCouriers_Negative(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	couriers := append(testCouriers(), model.CreateCourierDTO{CourierType: "unknown type"})
+	resp, err := s.CreateCouriers(ctx, couriers)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	assert.Nil(t, resp)
+
+	all, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestStore_GetCourierByID_Negative(t *testing.T) {
+	c, err := New().GetCourierByID(context.Background(), 1)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, c)
+}
+
+func TestStore_GetCouriers(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	couriers, err := s.GetCouriers(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, couriers)
+	assert.Empty(t, couriers)
+
+	created, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+
+	couriers, err = s.GetCouriers(ctx, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, created[1:3], couriers)
+
+	couriers, err = s.GetCouriers(ctx, 2, 10)
+	require.NoError(t, err)
+	assert.Empty(t, couriers)
+
+	couriers, err = s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, created, couriers)
+
+	_, err = s.GetCouriers(ctx, 1, -1)
+	assert.ErrorIs(t, err, ErrBadPagination)
+}
+
+func TestStore_UpdateCourier(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	created, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+
+	bike, unknown := model.BikeCourierTypeString, "unknown"
+	req := &model.UpdateCourierRequest{CourierType: &bike, Regions: []int32{4, 5}}
+	updated, err := s.UpdateCourier(ctx, created[0].CourierID, req)
+	require.NoError(t, err)
+	want := created[0]
+	want.CourierType, want.Regions = bike, []int32{4, 5}
+	assert.Equal(t, &want, updated)
+
+	req.Regions[0] = 100
+	got, err := s.GetCourierByID(ctx, created[0].CourierID)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got, "stored courier must not share memory with request")
+
+	_, err = s.UpdateCourier(ctx, created[0].CourierID, &model.UpdateCourierRequest{CourierType: &unknown})
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	_, err = s.UpdateCourier(ctx, 100, req)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	_, err = s.UpdateCourier(ctx, created[0].CourierID, nil)
+	assert.ErrorIs(t, err, ErrNilReference)
+}
+
+func TestStore_DeactivateCourier(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	created, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeactivateCourier(ctx, created[1].CourierID))
+	require.NoError(t, s.DeactivateCourier(ctx, created[1].CourierID))
+	assert.ErrorIs(t, s.DeactivateCourier(ctx, 100), store.ErrDoesNotExists)
+
+	active := []model.CourierDTO{created[0], created[2]}
+	couriers, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, active, couriers)
+
+	couriers, err = s.GetCouriers(ctx, 10, 1)
+	require.NoError(t, err)
+	assert.Equal(t, active[1:], couriers)
+
+	couriers, err = s.GetCouriersAfter(ctx, created[0].CourierID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, active[1:], couriers)
+
+	got, err := s.GetCourierByID(ctx, created[1].CourierID)
+	require.NoError(t, err)
+	assert.False(t, got.Active)
+}
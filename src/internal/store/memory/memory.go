@@ -0,0 +1,279 @@
+// Package memory contains in-memory implementation of storage.
+//
+// It is safe for concurrent use and follows semantics of postgres storage, so the whole service can run without
+// database. Data is lost when process exits.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ production.Store = (*Store)(nil)
+
+	ErrNilReference = errors.New("unexpectedly got nil reference in storage")
+	// ErrBadPagination is returned when limit or offset is negative.
+	ErrBadPagination = fmt.Errorf("limit and offset must not be negative: %w", store.ErrCheckViolation)
+)
+
+type (
+	// order is stored order with its assignment state.
+	order struct {
+		model.OrderDTO
+		courier int64
+		group   int64
+	}
+	// group is group of orders which courier delivers in one trip.
+	group struct {
+		id      int64
+		date    string
+		courier int64
+		window  *datetime.TimeInterval
+	}
+	// assignment is run of assignment at some date.
+	assignment struct {
+		strategy string
+	}
+	// event is event of outbox with its delivery state.
+	event struct {
+		model.Event
+		delivered bool
+	}
+	// webhook is subscription of webhook, deleted webhook stays with its deliveries.
+	webhook struct {
+		model.WebhookDTO
+		active bool
+	}
+	// apiKey is API key with hash of key, revoked key stays in storage.
+	apiKey struct {
+		model.APIKey
+		hash    string
+		revoked bool
+	}
+	// delivery is delivery of event to webhook.
+	delivery struct {
+		id             int64
+		webhook        int64
+		event          int64
+		eventType      string
+		status         string
+		attempts       int32
+		lastStatusCode int32
+		lastError      string
+		nextAttempt    time.Time
+		deliveredAt    time.Time
+		createdAt      time.Time
+	}
+)
+
+// Store is in-memory storage.
+//
+// All methods take one lock, so every method call is atomic. Batch methods either apply all changes or none of them.
+type Store struct {
+	mu sync.RWMutex
+
+	couriers   map[int64]*model.CourierDTO
+	courierIDs []int64
+	courierSeq int64
+
+	orders    map[int64]*order
+	orderIDs  []int64
+	orderSeq  int64
+	subOrders map[int64][]int64
+	history   map[int64][]model.OrderStatusChange
+
+	groups      map[int64]*group
+	groupSeq    int64
+	assignments map[string]assignment
+
+	outbox   []*event
+	eventSeq int64
+
+	webhooks    map[int64]*webhook
+	webhookIDs  []int64
+	deliveries  []*delivery
+	deliverySeq int64
+
+	apiKeys     []*apiKey
+	apiKeyIndex map[string]*apiKey
+
+	locksMu sync.Mutex
+	locks   map[string]chan struct{}
+}
+
+// New returns empty storage.
+func New() *Store {
+	return &Store{
+		couriers:    make(map[int64]*model.CourierDTO),
+		orders:      make(map[int64]*order),
+		subOrders:   make(map[int64][]int64),
+		history:     make(map[int64][]model.OrderStatusChange),
+		groups:      make(map[int64]*group),
+		assignments: make(map[string]assignment),
+		webhooks:    make(map[int64]*webhook),
+		apiKeyIndex: make(map[string]*apiKey),
+		locks:       make(map[string]chan struct{}),
+	}
+}
+
+// page returns bounds of page of n records.
+func page(n, limit, offset int) (from, to int, err error) {
+	if limit < 0 || offset < 0 {
+		return 0, 0, ErrBadPagination
+	}
+	from, to = offset, offset+limit
+	if from > n {
+		from = n
+	}
+	if to > n || to < from {
+		to = n
+	}
+	return from, to, nil
+}
+
+// after returns at most limit ids from sorted ids which are greater than id.
+func after(ids []int64, id int64, limit int) ([]int64, error) {
+	if limit < 0 {
+		return nil, ErrBadPagination
+	}
+	from := sort.Search(len(ids), func(i int) bool {
+		return ids[i] > id
+	})
+	to := from + limit
+	if to > len(ids) {
+		to = len(ids)
+	}
+	return ids[from:to], nil
+}
+
+// completed returns true if order is completed.
+func (o *order) completed() bool {
+	return o.Status == model.OrderStatusCompleted
+}
+
+// setStatus sets status of order and records change into history of order statuses.
+//
+// State of order must be remembered by undo before.
+func (s *Store) setStatus(o *order, status, actor string) {
+	o.Status = status
+	s.history[o.OrderID] = append(s.history[o.OrderID], model.OrderStatusChange{
+		Status:    status,
+		Actor:     actor,
+		ChangedAt: datetime.Time(time.Now()),
+	})
+}
+
+// intervals returns copy of intervals, it is never nil.
+func intervals(src []*datetime.TimeInterval) []*datetime.TimeInterval {
+	res := make([]*datetime.TimeInterval, len(src))
+	copy(res, src)
+	return res
+}
+
+// undo remembers state of records changed by batch method to restore it if batch fails.
+//
+// It must be used only while write lock of storage is held.
+type undo struct {
+	s           *Store
+	couriers    int
+	orders      map[int64]order
+	orderIDs    int
+	history     map[int64]int
+	groups      map[int64]*group
+	assignments map[string]*assignment
+	outbox      int
+	deliveries  int
+}
+
+func (s *Store) begin() *undo {
+	return &undo{
+		s:           s,
+		couriers:    len(s.courierIDs),
+		orders:      make(map[int64]order),
+		orderIDs:    len(s.orderIDs),
+		history:     make(map[int64]int),
+		groups:      make(map[int64]*group),
+		assignments: make(map[string]*assignment),
+		outbox:      len(s.outbox),
+		deliveries:  len(s.deliveries),
+	}
+}
+
+// order remembers state of order and length of its status history before they are changed.
+func (u *undo) order(o *order) {
+	if _, ok := u.orders[o.OrderID]; !ok {
+		u.orders[o.OrderID] = *o
+		u.history[o.OrderID] = len(u.s.history[o.OrderID])
+	}
+}
+
+// group remembers state of group with id before it is changed, created or deleted.
+func (u *undo) group(id int64) {
+	if _, ok := u.groups[id]; ok {
+		return
+	}
+	var prev *group
+	if g, ok := u.s.groups[id]; ok {
+		c := *g
+		prev = &c
+	}
+	u.groups[id] = prev
+}
+
+// assignment remembers assignment at date before it is changed.
+func (u *undo) assignment(date string) {
+	if _, ok := u.assignments[date]; ok {
+		return
+	}
+	var prev *assignment
+	if a, ok := u.s.assignments[date]; ok {
+		prev = &a
+	}
+	u.assignments[date] = prev
+}
+
+// rollback restores all remembered records and removes records which were created after begin.
+//
+// Ids of removed records are not reused, same as with postgres sequences.
+func (u *undo) rollback() {
+	for id, o := range u.orders {
+		*u.s.orders[id] = o
+	}
+	for id, n := range u.history {
+		u.s.history[id] = u.s.history[id][:n]
+	}
+	for id, g := range u.groups {
+		if g == nil {
+			delete(u.s.groups, id)
+			continue
+		}
+		u.s.groups[id] = g
+	}
+	for date, a := range u.assignments {
+		if a == nil {
+			delete(u.s.assignments, date)
+			continue
+		}
+		u.s.assignments[date] = *a
+	}
+	for _, id := range u.s.courierIDs[u.couriers:] {
+		delete(u.s.couriers, id)
+	}
+	u.s.courierIDs = u.s.courierIDs[:u.couriers]
+	for _, id := range u.s.orderIDs[u.orderIDs:] {
+		delete(u.s.orders, id)
+		delete(u.s.subOrders, id)
+		delete(u.s.history, id)
+	}
+	u.s.orderIDs = u.s.orderIDs[:u.orderIDs]
+	u.s.outbox = u.s.outbox[:u.outbox]
+	u.s.deliveries = u.s.deliveries[:u.deliveries]
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	tt := []struct {
+		name          string
+		n             int
+		limit, offset int
+		from, to      int
+		err           error
+	}{
+		{"all", 5, 10, 0, 0, 5, nil},
+		{"middle", 5, 2, 1, 1, 3, nil},
+		{"offset out of range", 5, 2, 10, 5, 5, nil},
+		{"zero limit", 5, 0, 1, 1, 1, nil},
+		{"negative limit", 5, -1, 0, 0, 0, ErrBadPagination},
+		{"negative offset", 5, 1, -1, 0, 0, ErrBadPagination},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to, err := page(tc.n, tc.limit, tc.offset)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.from, from)
+			assert.Equal(t, tc.to, to)
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	ids := []int64{1, 3, 5, 7}
+	tt := []struct {
+		name  string
+		id    int64
+		limit int
+		want  []int64
+		err   error
+	}{
+		{"from start", 0, 2, []int64{1, 3}, nil},
+		{"between ids", 4, 10, []int64{5, 7}, nil},
+		{"after last", 7, 2, []int64{}, nil},
+		{"zero limit", 0, 0, []int64{}, nil},
+		{"negative limit", 0, -1, nil, ErrBadPagination},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := after(ids, tc.id, tc.limit)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUndo_Rollback(t *testing.T) {
+	s := New()
+	s.orders[1] = &order{}
+	s.orders[1].OrderID = 1
+	s.groups[1] = &group{id: 1, date: "2023-01-01"}
+	s.groupSeq = 1
+	s.assignments["2023-01-01"] = assignment{strategy: "greedy"}
+
+	u := s.begin()
+	u.order(s.orders[1])
+	s.orders[1].courier = 2
+	s.setStatus(s.orders[1], model.OrderStatusCompleted, model.ActorAPI)
+	u.order(s.orders[1])
+	s.orders[1].group = 3
+
+	u.group(1)
+	s.groups[1].courier = 5
+	u.group(2)
+	s.groups[2], s.groupSeq = &group{id: 2}, 2
+
+	u.assignment("2023-01-01")
+	s.assignments["2023-01-01"] = assignment{strategy: "optimal"}
+	u.assignment("2023-01-02")
+	s.assignments["2023-01-02"] = assignment{}
+
+	u.rollback()
+	assert.Equal(t, &order{OrderDTO: s.orders[1].OrderDTO}, s.orders[1])
+	assert.Empty(t, s.orders[1].Status)
+	assert.Empty(t, s.history[1])
+	assert.Equal(t, map[int64]*group{1: {id: 1, date: "2023-01-01"}}, s.groups)
+	assert.Equal(t, int64(2), s.groupSeq, "ids of groups are not reused")
+	assert.Equal(t, map[string]assignment{"2023-01-01": {strategy: "greedy"}}, s.assignments)
+}
+
+func TestUndo_Rollback_Created(t *testing.T) {
+	s := New()
+	_, err := s.CreateCouriers(context.Background(), []model.CreateCourierDTO{{CourierType: model.FootCourierTypeString}})
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(context.Background(), []*model.OrderDTO{{Weight: 1, Regions: 1, Cost: 1}}))
+	seq := s.eventSeq
+
+	u := s.begin()
+	_, err = s.CreateCouriers(context.Background(), []model.CreateCourierDTO{{CourierType: model.FootCourierTypeString}})
+	require.NoError(t, err)
+	s.createOrders([]*model.OrderDTO{{Weight: 2, Regions: 1, Cost: 1, SubOrders: []*model.OrderDTO{{Weight: 1, Regions: 1, Cost: 1}}}}, 0)
+	require.NoError(t, s.publish(model.EventOrderCreated, struct{}{}))
+
+	u.rollback()
+	assert.Equal(t, []int64{1}, s.courierIDs)
+	assert.Len(t, s.couriers, 1)
+	assert.Equal(t, []int64{1}, s.orderIDs)
+	assert.Len(t, s.orders, 1)
+	assert.Empty(t, s.subOrders)
+	assert.Len(t, s.history, 1)
+	assert.Len(t, s.outbox, 2)
+	assert.Equal(t, seq+2, s.eventSeq, "ids of events are not reused")
+
+	couriers, err := s.CreateCouriers(context.Background(), []model.CreateCourierDTO{{CourierType: model.FootCourierTypeString}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), couriers[0].CourierID, "ids of couriers are not reused")
+	orders := []*model.OrderDTO{{Weight: 1, Regions: 1, Cost: 1}}
+	require.NoError(t, s.CreateOrders(context.Background(), orders))
+	assert.Equal(t, int64(4), orders[0].OrderID, "ids of orders are not reused")
+}
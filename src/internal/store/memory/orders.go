@@ -0,0 +1,359 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"time"
+)
+
+// dto returns copy of stored order without sub-orders.
+func (o *order) dto() *model.OrderDTO {
+	res := o.OrderDTO
+	res.SubOrders = nil
+	res.DeliveryHours = intervals(o.DeliveryHours)
+	if o.DeliveryTime != nil {
+		t := *o.DeliveryTime
+		res.DeliveryTime = &t
+	}
+	return &res
+}
+
+func (s *Store) GetOrderByID(_ context.Context, id int64) (*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+	}
+	return o.dto(), nil
+}
+
+// GetOrders returns page of orders ordered by id.
+func (s *Store) GetOrders(_ context.Context, limit int, offset int) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from, to, err := page(len(s.orderIDs), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get orders: %w", err)
+	}
+	return s.ordersByIDs(s.orderIDs[from:to]), nil
+}
+
+// GetOrdersAfter returns at most limit orders with id greater than afterID ordered by id.
+func (s *Store) GetOrdersAfter(_ context.Context, afterID int64, limit int) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids, err := after(s.orderIDs, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get orders: %w", err)
+	}
+	return s.ordersByIDs(ids), nil
+}
+
+// GetOrdersByIDs returns orders with provided ids in the same order.
+//
+// If any of orders does not exist then store.ErrDoesNotExists will be returned.
+func (s *Store) GetOrdersByIDs(_ context.Context, ids []int64) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, id := range ids {
+		if _, ok := s.orders[id]; !ok {
+			return nil, fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+		}
+	}
+	return s.ordersByIDs(ids), nil
+}
+
+// GetSubOrders returns orders which order with provided id was split into.
+func (s *Store) GetSubOrders(_ context.Context, id int64) ([]*model.OrderDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.ordersByIDs(s.subOrders[id]), nil
+}
+
+// ordersByIDs returns copies of existing orders with provided ids.
+func (s *Store) ordersByIDs(ids []int64) []*model.OrderDTO {
+	res := make([]*model.OrderDTO, 0, len(ids))
+	for _, id := range ids {
+		res = append(res, s.orders[id].dto())
+	}
+	return res
+}
+
+// checkOrders checks orders with their sub-orders against constraints of postgres storage.
+func checkOrders(orders []*model.OrderDTO) error {
+	for _, o := range orders {
+		if o == nil {
+			return ErrNilReference
+		}
+		if o.Cost <= 0 || o.Regions <= 0 || o.Weight <= 0 {
+			return fmt.Errorf("order with cost %d, region %d and weight %f: %w", o.Cost, o.Regions, o.Weight, store.ErrCheckViolation)
+		}
+		if err := checkOrders(o.SubOrders); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateOrders stores all orders with their sub-orders or none of them and fills ids of created orders.
+func (s *Store) CreateOrders(_ context.Context, orders []*model.OrderDTO) (err error) {
+	if err = checkOrders(orders); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.begin()
+	defer func() {
+		if err != nil {
+			u.rollback()
+		}
+	}()
+
+	s.createOrders(orders, 0)
+	events := make([]any, 0, len(orders))
+	for _, o := range orders {
+		events = append(events, o)
+	}
+	return s.publish(model.EventOrderCreated, events...)
+}
+
+func (s *Store) createOrders(orders []*model.OrderDTO, parent int64) {
+	for _, o := range orders {
+		s.orderSeq++
+		o.OrderID = s.orderSeq
+		o.ParentOrderID = parent
+
+		stored := &order{OrderDTO: *o}
+		stored.SubOrders = nil
+		stored.CompletedTime = datetime.Time{}
+		stored.DeliveryTime = nil
+		stored.UnassignedReason = ""
+		stored.CancelReason = ""
+		stored.DeliveryHours = intervals(o.DeliveryHours)
+		s.setStatus(stored, model.OrderStatusCreated, model.ActorAPI)
+		o.Status = stored.Status
+		s.orders[o.OrderID] = stored
+		s.orderIDs = append(s.orderIDs, o.OrderID)
+		if parent != 0 {
+			s.subOrders[parent] = append(s.subOrders[parent], o.OrderID)
+		}
+		s.createOrders(o.SubOrders, o.OrderID)
+	}
+}
+
+// GetCompletedOrdersPriceByCourier returns sum of costs and count of orders which courier completed in [start, end].
+func (s *Store) GetCompletedOrdersPriceByCourier(_ context.Context, id int64, start time.Time, end time.Time) (sum, count int32, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, o := range s.orders {
+		completed := time.Time(o.CompletedTime)
+		if o.completed() && o.courier == id && !completed.Before(start) && !completed.After(end) {
+			sum += o.Cost
+			count++
+		}
+	}
+	return sum, count, nil
+}
+
+// CompleteOrders marks orders as completed by couriers they are assigned to.
+//
+// Completing of already completed order changes nothing. If any order does not exist or is not assigned to courier
+// then store.ErrDoesNotExists is returned and none of orders is completed. If order can not be completed from its
+// status then store.ErrStatusChanged is returned.
+func (s *Store) CompleteOrders(_ context.Context, info []model.CompleteOrder) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.begin()
+	defer func() {
+		if err != nil {
+			u.rollback()
+		}
+	}()
+
+	for _, c := range info {
+		o, ok := s.orders[c.OrderID]
+		if !ok || o.courier != c.CourierID {
+			return fmt.Errorf("order %d of courier %d: %w", c.OrderID, c.CourierID, store.ErrDoesNotExists)
+		}
+		if o.completed() {
+			continue
+		}
+		if !model.CanChangeOrderStatus(o.Status, model.OrderStatusCompleted) {
+			return fmt.Errorf("order %d is %s: %w", c.OrderID, o.Status, store.ErrStatusChanged)
+		}
+		u.order(o)
+		o.CompletedTime = c.CompleteTime
+		s.setStatus(o, model.OrderStatusCompleted, model.CourierActor(c.CourierID))
+		if err = s.publish(model.EventOrderCompleted, &model.OrderCompletedEvent{
+			CourierID:    c.CourierID,
+			OrderID:      c.OrderID,
+			CompleteTime: c.CompleteTime,
+			Region:       o.Regions,
+		}); err != nil {
+			return err
+		}
+		if err = s.completeParent(u, o.ParentOrderID, c.CourierID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CancelOrders cancels orders with their sub-orders and takes them back from couriers.
+//
+// Cancelling of already cancelled order changes nothing. If any order does not exist then store.ErrDoesNotExists is
+// returned and if any order can not be cancelled from its status then store.ErrStatusChanged is returned, in both
+// cases none of orders is cancelled.
+func (s *Store) CancelOrders(_ context.Context, ids []int64, reason, actor string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.begin()
+	defer func() {
+		if err != nil {
+			u.rollback()
+		}
+	}()
+
+	for _, id := range ids {
+		o, ok := s.orders[id]
+		if !ok {
+			return fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+		}
+		for _, c := range append([]*order{o}, s.subOrdersOf(id)...) {
+			if err = s.cancelOrder(u, c, reason, actor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// subOrdersOf returns stored sub-orders of order with provided id.
+func (s *Store) subOrdersOf(id int64) []*order {
+	res := make([]*order, 0, len(s.subOrders[id]))
+	for _, subID := range s.subOrders[id] {
+		res = append(res, s.orders[subID])
+	}
+	return res
+}
+
+// cancelOrder cancels order, takes it from group of courier and deletes group if it is emptied.
+func (s *Store) cancelOrder(u *undo, o *order, reason, actor string) error {
+	if o.Status == model.OrderStatusCancelled {
+		return nil
+	}
+	if !model.CanChangeOrderStatus(o.Status, model.OrderStatusCancelled) {
+		return fmt.Errorf("order %d is %s: %w", o.OrderID, o.Status, store.ErrStatusChanged)
+	}
+
+	u.order(o)
+	groupID, courier := o.group, o.courier
+	o.courier, o.group, o.DeliveryTime, o.CancelReason = 0, 0, nil, reason
+	s.setStatus(o, model.OrderStatusCancelled, actor)
+	if err := s.publish(model.EventOrderCancelled, &model.OrderCancelledEvent{
+		OrderID:   o.OrderID,
+		CourierID: courier,
+		Region:    o.Regions,
+		Reason:    reason,
+	}); err != nil {
+		return err
+	}
+
+	if groupID == 0 {
+		return nil
+	}
+	for _, other := range s.orders {
+		if other.group == groupID {
+			return nil
+		}
+	}
+	u.group(groupID)
+	delete(s.groups, groupID)
+	return nil
+}
+
+// completeParent completes order with provided id by courier if all of its sub-orders are completed.
+//
+// Completion time of parent is time when the last sub-order was completed. Parent is completed only from status which
+// model.CanCompleteParent allows.
+func (s *Store) completeParent(u *undo, id int64, courier int64) error {
+	parent, ok := s.orders[id]
+	if !ok || !model.CanCompleteParent(parent.Status) {
+		return nil
+	}
+	var last time.Time
+	for _, subID := range s.subOrders[id] {
+		sub := s.orders[subID]
+		if !sub.completed() {
+			return nil
+		}
+		if t := time.Time(sub.CompletedTime); t.After(last) {
+			last = t
+		}
+	}
+	u.order(parent)
+	parent.CompletedTime = datetime.Time(last)
+	s.setStatus(parent, model.OrderStatusCompleted, model.CourierActor(courier))
+	return s.publish(model.EventOrderCompleted, &model.OrderCompletedEvent{
+		CourierID:    courier,
+		OrderID:      id,
+		CompleteTime: parent.CompletedTime,
+		Region:       parent.Regions,
+	})
+}
+
+// ChangeOrderStatus changes status of order from one status to another and records change in history.
+//
+// It only changes status, so it must not be used to complete orders. If order has status other than from or is split
+// into sub-orders then store.ErrStatusChanged is returned. If courier is not zero then order must be assigned to
+// courier, otherwise store.ErrDoesNotExists is returned.
+func (s *Store) ChangeOrderStatus(_ context.Context, id, courier int64, from, to, actor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.orders[id]
+	if !ok {
+		return fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+	}
+	if courier != 0 && o.courier != courier {
+		return fmt.Errorf("order %d of courier %d: %w", id, courier, store.ErrDoesNotExists)
+	}
+	if len(s.subOrders[id]) > 0 {
+		return fmt.Errorf("order %d is split into sub-orders: %w", id, store.ErrStatusChanged)
+	}
+	if o.Status != from {
+		return fmt.Errorf("order %d is not %s: %w", id, from, store.ErrStatusChanged)
+	}
+	if !model.ValidOrderStatus(to) || to == model.OrderStatusCompleted {
+		return fmt.Errorf("status %q: %w", to, store.ErrCheckViolation)
+	}
+	s.setStatus(o, to, actor)
+	return nil
+}
+
+// GetOrderHistory returns changes of order status from the oldest one to the newest one.
+func (s *Store) GetOrderHistory(_ context.Context, id int64) ([]model.OrderStatusChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.orders[id]; !ok {
+		return nil, fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+	}
+	res := make([]model.OrderStatusChange, len(s.history[id]))
+	copy(res, s.history[id])
+	return res, nil
+}
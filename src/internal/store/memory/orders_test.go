@@ -0,0 +1,355 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+	"time"
+)
+
+var testHours = []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+
+func testOrders() []*model.OrderDTO {
+	return []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: testHours, Cost: 10},
+		{
+			Weight:        60,
+			Regions:       1,
+			DeliveryHours: testHours,
+			Cost:          100,
+			SubOrders: []*model.OrderDTO{
+				{Weight: 30, Regions: 1, DeliveryHours: testHours, Cost: 50},
+				{Weight: 30, Regions: 1, DeliveryHours: testHours, Cost: 50},
+			},
+		},
+		{Weight: 2, Regions: 2, DeliveryHours: testHours, Cost: 20},
+	}
+}
+
+func TestStore_CreateOrders(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	orders := testOrders()
+	require.NoError(t, s.CreateOrders(ctx, orders))
+	assert.Equal(t, int64(1), orders[0].OrderID)
+	assert.Equal(t, int64(2), orders[1].OrderID)
+	assert.Equal(t, int64(3), orders[1].SubOrders[0].OrderID)
+	assert.Equal(t, int64(4), orders[1].SubOrders[1].OrderID)
+	assert.Equal(t, int64(5), orders[2].OrderID)
+
+	got, err := s.GetOrderByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Nil(t, got.SubOrders)
+	assert.Equal(t, testHours, got.DeliveryHours)
+
+	subs, err := s.GetSubOrders(ctx, 2)
+	require.NoError(t, err)
+	if assert.Len(t, subs, 2) {
+		for i, sub := range subs {
+			assert.Equal(t, orders[1].SubOrders[i].OrderID, sub.OrderID)
+			assert.Equal(t, int64(2), sub.ParentOrderID)
+		}
+	}
+
+	subs, err = s.GetSubOrders(ctx, 1)
+	require.NoError(t, err)
+	assert.NotNil(t, subs)
+	assert.Empty(t, subs)
+
+	page, err := s.GetOrders(ctx, 2, 3)
+	require.NoError(t, err)
+	if assert.Len(t, page, 2) {
+		assert.Equal(t, int64(4), page[0].OrderID)
+		assert.Equal(t, int64(5), page[1].OrderID)
+	}
+	_, err = s.GetOrders(ctx, -1, 0)
+	assert.ErrorIs(t, err, ErrBadPagination)
+}
+
+func TestStore_CreateOrders_Negative(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	orders := testOrders()
+	orders[1].SubOrders[1].Cost = 0
+	assert.ErrorIs(t, s.CreateOrders(ctx, orders), store.ErrCheckViolation)
+	assert.ErrorIs(t, s.CreateOrders(ctx, []*model.OrderDTO{nil}), ErrNilReference)
+
+	all, err := s.GetOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestStore_GetOrdersByIDs(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+
+	orders, err := s.GetOrdersByIDs(ctx, []int64{5, 1})
+	require.NoError(t, err)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, int64(5), orders[0].OrderID)
+		assert.Equal(t, int64(1), orders[1].OrderID)
+	}
+
+	orders, err = s.GetOrdersByIDs(ctx, []int64{1, 100})
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, orders)
+
+	o, err := s.GetOrderByID(ctx, 100)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, o)
+}
+
+// assignAll assigns all unassigned orders to courier in one group.
+func assignAll(t *testing.T, s *Store, date string, courier int64) *model.OrderAssignResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	orders, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	group := model.GroupOrders{}
+	for _, o := range orders {
+		group.Orders = append(group.Orders, *o)
+	}
+	resp := &model.OrderAssignResponse{
+		Date:     date,
+		Couriers: []model.CourierGroupOrders{{CourierID: courier, Orders: []model.GroupOrders{group}}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, resp))
+	return resp
+}
+
+func TestStore_CompleteOrders(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+	assignAll(t, s, "2023-01-01", 2)
+
+	first := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	// unknown order makes whole batch fail.
+	err = s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(first)},
+		{CourierID: 2, OrderID: 100, CompleteTime: datetime.Time(first)},
+	})
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	// order of another courier makes whole batch fail.
+	err = s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(first)},
+		{CourierID: 1, OrderID: 5, CompleteTime: datetime.Time(first)},
+	})
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	o, err := s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, time.Time(o.CompletedTime).IsZero())
+
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(first)},
+		{CourierID: 2, OrderID: 3, CompleteTime: datetime.Time(first)},
+	}))
+	parent, err := s.GetOrderByID(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, time.Time(parent.CompletedTime).IsZero())
+
+	// completing is idempotent and keeps first completion time.
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(last)},
+		{CourierID: 2, OrderID: 4, CompleteTime: datetime.Time(last)},
+	}))
+	o, err = s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, first.Equal(time.Time(o.CompletedTime)))
+	parent, err = s.GetOrderByID(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, last.Equal(time.Time(parent.CompletedTime)))
+
+	sum, count, err := s.GetCompletedOrdersPriceByCourier(ctx, 2, first, first)
+	require.NoError(t, err)
+	assert.Equal(t, int32(60), sum)
+	assert.Equal(t, int32(2), count)
+
+	sum, count, err = s.GetCompletedOrdersPriceByCourier(ctx, 1, first, last)
+	require.NoError(t, err)
+	assert.Zero(t, sum)
+	assert.Zero(t, count)
+}
+
+// statuses returns statuses from history of order.
+func statuses(t *testing.T, s *Store, id int64) []string {
+	t.Helper()
+	history, err := s.GetOrderHistory(context.Background(), id)
+	require.NoError(t, err)
+	res := make([]string, 0, len(history))
+	for _, change := range history {
+		res = append(res, change.Status+" by "+change.Actor)
+	}
+	return res
+}
+
+func TestStore_OrderStatus(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	orders := testOrders()
+	require.NoError(t, s.CreateOrders(ctx, orders))
+	assert.Equal(t, model.OrderStatusCreated, orders[1].SubOrders[0].Status)
+	assignAll(t, s, "2023-01-01", 2)
+
+	o, err := s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusAssigned, o.Status)
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment"}, statuses(t, s, 1))
+	assert.Equal(t, []string{"CREATED by api"}, statuses(t, s, 2))
+
+	require.NoError(t, s.ChangeOrderStatus(ctx, 1, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI))
+	require.NoError(t, s.ChangeOrderStatus(ctx, 5, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI))
+	require.NoError(t, s.ChangeOrderStatus(ctx, 5, 0, model.OrderStatusInDelivery, model.OrderStatusFailed, model.ActorAPI))
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 1, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI), store.ErrStatusChanged)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 100, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 3, 1, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.CourierActor(1)), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 2, 0, model.OrderStatusCreated, model.OrderStatusCancelled, model.ActorAPI), store.ErrStatusChanged)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 3, 0, model.OrderStatusAssigned, model.OrderStatusCompleted, model.ActorAPI), store.ErrCheckViolation)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, 3, 0, model.OrderStatusAssigned, "LOST", model.ActorAPI), store.ErrCheckViolation)
+
+	// failed order can not be completed and batch is not applied.
+	err = s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(time.Now())},
+		{CourierID: 2, OrderID: 5, CompleteTime: datetime.Time(time.Now())},
+	})
+	assert.ErrorIs(t, err, store.ErrStatusChanged)
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "IN_DELIVERY by api"}, statuses(t, s, 1))
+
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 1, CompleteTime: datetime.Time(time.Now())},
+		{CourierID: 2, OrderID: 3, CompleteTime: datetime.Time(time.Now())},
+		{CourierID: 2, OrderID: 4, CompleteTime: datetime.Time(time.Now())},
+	}))
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "IN_DELIVERY by api", "COMPLETED by courier:2"}, statuses(t, s, 1))
+	assert.Equal(t, []string{"CREATED by api", "COMPLETED by courier:2"}, statuses(t, s, 2))
+
+	_, err = s.GetOrderHistory(ctx, 100)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+}
+
+func TestStore_SaveOrdersAssign_ReleasesOnlyAssigned(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+	assignAll(t, s, "2023-01-01", 2)
+	require.NoError(t, s.ChangeOrderStatus(ctx, 1, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI))
+
+	assigned, err := s.GetAssignedOrders(ctx, "2023-01-01")
+	require.NoError(t, err)
+	assert.Len(t, assigned, 3)
+
+	// replacing assignment with empty one takes back all orders except the one courier delivers.
+	require.NoError(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{Date: "2023-01-01"}))
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	assert.Len(t, unassigned, 3)
+	for _, o := range unassigned {
+		assert.Equal(t, model.OrderStatusCreated, o.Status)
+	}
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "CREATED by assignment"}, statuses(t, s, 3))
+
+	o, err := s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusInDelivery, o.Status)
+	resp, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		assert.Len(t, resp.Couriers[0].Orders[0].Orders, 1)
+	}
+}
+
+func TestStore_CancelOrders(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+	resp := assignAll(t, s, "2023-01-01", 2)
+	group := resp.Couriers[0].Orders[0].GroupOrderID
+
+	// unknown order makes whole batch fail.
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{1, 100}, "withdrew", model.ActorAPI), store.ErrDoesNotExists)
+	o, err := s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusAssigned, o.Status)
+
+	// cancelled parent cancels its sub-orders and they leave group of courier.
+	require.NoError(t, s.CancelOrders(ctx, []int64{1, 2}, "withdrew", model.ActorAPI))
+	for _, id := range []int64{1, 2, 3, 4} {
+		o, err = s.GetOrderByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusCancelled, o.Status)
+		assert.Equal(t, "withdrew", o.CancelReason)
+		assert.Nil(t, o.DeliveryTime)
+	}
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "CANCELLED by api"}, statuses(t, s, 3))
+	assert.Equal(t, []string{"CREATED by api", "CANCELLED by api"}, statuses(t, s, 2))
+
+	got, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, got.Couriers, 1) && assert.Len(t, got.Couriers[0].Orders, 1) {
+		assert.Equal(t, group, got.Couriers[0].Orders[0].GroupOrderID)
+		assert.Len(t, got.Couriers[0].Orders[0].Orders, 1)
+	}
+
+	// cancelling is idempotent and keeps the first reason.
+	require.NoError(t, s.CancelOrders(ctx, []int64{1}, "another reason", model.ActorAPI))
+	o, err = s.GetOrderByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "withdrew", o.CancelReason)
+
+	// the last order of group is cancelled, so group is deleted.
+	require.NoError(t, s.CancelOrders(ctx, []int64{5}, "withdrew", model.ActorAPI))
+	got, err = s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	assert.Empty(t, got.Couriers)
+
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, unassigned)
+}
+
+func TestStore_CancelOrders_Negative(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	_, err := s.CreateCouriers(ctx, testCouriers())
+	require.NoError(t, err)
+	require.NoError(t, s.CreateOrders(ctx, testOrders()))
+	assignAll(t, s, "2023-01-01", 2)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: 2, OrderID: 3, CompleteTime: datetime.Time(time.Now())},
+		{CourierID: 2, OrderID: 5, CompleteTime: datetime.Time(time.Now())},
+	}))
+
+	// completed order and parent with completed sub-order can not be cancelled.
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{1, 5}, "withdrew", model.ActorAPI), store.ErrStatusChanged)
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{2}, "withdrew", model.ActorAPI), store.ErrStatusChanged)
+	for _, id := range []int64{1, 2, 4} {
+		o, err := s.GetOrderByID(ctx, id)
+		require.NoError(t, err)
+		assert.NotEqual(t, model.OrderStatusCancelled, o.Status)
+		assert.Empty(t, o.CancelReason)
+	}
+	assert.Len(t, statuses(t, s, 4), 2)
+
+	sum, count, err := s.GetCompletedOrdersPriceByCourier(ctx, 2, time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int32(70), sum)
+	assert.Equal(t, int32(2), count)
+}
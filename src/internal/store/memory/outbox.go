@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+	"time"
+)
+
+// publish writes events of type with provided payloads into outbox and schedules them for delivery to active webhooks
+// which are subscribed to their type.
+//
+// Events and their deliveries are removed by undo together with change which caused them.
+func (s *Store) publish(typ string, payloads ...any) error {
+	events := make([]*event, 0, len(payloads))
+	for _, p := range payloads {
+		e, err := model.NewEvent(typ, p)
+		if err != nil {
+			return err
+		}
+		events = append(events, &event{Event: e})
+	}
+	now := time.Now()
+	for _, e := range events {
+		s.eventSeq++
+		e.ID = s.eventSeq
+		e.CreatedAt = datetime.Time(now)
+		s.schedule(e.Event, now)
+	}
+	s.outbox = append(s.outbox, events...)
+	return nil
+}
+
+// GetPendingEvents returns at most limit events which are not delivered yet ordered by id.
+func (s *Store) GetPendingEvents(_ context.Context, limit int) ([]model.Event, error) {
+	if limit < 0 {
+		return nil, ErrBadPagination
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]model.Event, 0)
+	for _, e := range s.outbox {
+		if len(res) == limit {
+			break
+		}
+		if !e.delivered {
+			res = append(res, e.Event)
+		}
+	}
+	return res, nil
+}
+
+// MarkEventsDelivered marks events with provided ids as delivered, so they are not returned as pending anymore.
+//
+// Marking of already delivered event changes nothing.
+func (s *Store) MarkEventsDelivered(_ context.Context, ids []int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		if e, ok := s.eventByID(id); ok {
+			e.delivered = true
+		}
+	}
+	return nil
+}
+
+// GetEventsAfter returns at most limit events with id greater than afterID ordered by id whether they are delivered
+// or not.
+func (s *Store) GetEventsAfter(_ context.Context, afterID int64, limit int) ([]model.Event, error) {
+	if limit < 0 {
+		return nil, ErrBadPagination
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	from := sort.Search(len(s.outbox), func(i int) bool {
+		return s.outbox[i].ID > afterID
+	})
+	to := from + limit
+	if to > len(s.outbox) {
+		to = len(s.outbox)
+	}
+	res := make([]model.Event, 0, to-from)
+	for _, e := range s.outbox[from:to] {
+		res = append(res, e.Event)
+	}
+	return res, nil
+}
+
+// GetLastEventID returns id of the last event of outbox or zero if outbox is empty.
+func (s *Store) GetLastEventID(context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.outbox) == 0 {
+		return 0, nil
+	}
+	return s.outbox[len(s.outbox)-1].ID, nil
+}
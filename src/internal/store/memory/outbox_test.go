@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+)
+
+func TestStore_Publish(t *testing.T) {
+	s := New()
+	require.NoError(t, s.publish(model.EventOrderCompleted, &model.CompleteOrder{OrderID: 1}, &model.CompleteOrder{OrderID: 2}))
+	assert.Error(t, s.publish(model.EventOrderCompleted, &model.CompleteOrder{OrderID: 3}, make(chan int)))
+
+	events, err := s.GetPendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	if assert.Len(t, events, 2, "events of failed publish are not stored") {
+		assert.Equal(t, int64(1), events[0].ID)
+		assert.Equal(t, int64(2), events[1].ID)
+		assert.False(t, events[0].CreatedAt.Time().IsZero())
+	}
+}
+
+func TestStore_GetPendingEvents_Negative(t *testing.T) {
+	events, err := New().GetPendingEvents(context.Background(), -1)
+	assert.ErrorIs(t, err, ErrBadPagination)
+	assert.Nil(t, events)
+}
+
+func TestStore_MarkEventsDelivered_Unknown(t *testing.T) {
+	s := New()
+	require.NoError(t, s.publish(model.EventOrderCompleted, &model.CompleteOrder{OrderID: 1}))
+	assert.NoError(t, s.MarkEventsDelivered(context.Background(), []int64{0, 2, 100}))
+
+	events, err := s.GetPendingEvents(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestStore_GetEventsAfter(t *testing.T) {
+	s := New()
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, s.publish(model.EventOrderCompleted, &model.CompleteOrder{OrderID: i}))
+	}
+
+	events, err := s.GetEventsAfter(context.Background(), 1, 10)
+	require.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(2), events[0].ID)
+		assert.Equal(t, int64(3), events[1].ID)
+	}
+	events, err = s.GetEventsAfter(context.Background(), 0, 1)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = s.GetEventsAfter(context.Background(), 0, -1)
+	assert.ErrorIs(t, err, ErrBadPagination)
+	assert.Nil(t, events)
+
+	last, err := s.GetLastEventID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), last)
+}
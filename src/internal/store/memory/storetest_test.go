@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/storetest"
+	"testing"
+)
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) production.Store {
+		return New()
+	})
+}
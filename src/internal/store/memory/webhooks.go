@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"sort"
+	"time"
+)
+
+// dto returns delivery as it is returned by storage.
+func (d *delivery) dto() model.WebhookDelivery {
+	res := model.WebhookDelivery{
+		DeliveryID:     d.id,
+		WebhookID:      d.webhook,
+		EventID:        d.event,
+		EventType:      d.eventType,
+		Status:         d.status,
+		Attempts:       d.attempts,
+		LastStatusCode: d.lastStatusCode,
+		LastError:      d.lastError,
+		CreatedAt:      datetime.Time(d.createdAt),
+	}
+	if d.status == model.DeliveryStatusPending {
+		t := datetime.Time(d.nextAttempt)
+		res.NextAttemptAt = &t
+	}
+	if !d.deliveredAt.IsZero() {
+		t := datetime.Time(d.deliveredAt)
+		res.DeliveredAt = &t
+	}
+	return res
+}
+
+// webhookDTO returns copy of webhook.
+func webhookDTO(w *webhook) model.WebhookDTO {
+	res := w.WebhookDTO
+	res.EventTypes = append([]string(nil), w.EventTypes...)
+	return res
+}
+
+// subscribed returns true if webhook gets events of type.
+func (w *webhook) subscribed(typ string) bool {
+	for _, t := range w.EventTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// schedule creates pending deliveries of event to all active webhooks which are subscribed to its type.
+//
+// Deliveries are removed by undo together with event.
+func (s *Store) schedule(e model.Event, now time.Time) {
+	for _, id := range s.webhookIDs {
+		w := s.webhooks[id]
+		if w.active && w.subscribed(e.Type) {
+			s.addDelivery(id, e.ID, e.Type, now)
+		}
+	}
+}
+
+// addDelivery creates pending delivery of event to webhook.
+func (s *Store) addDelivery(webhookID, eventID int64, eventType string, now time.Time) *delivery {
+	s.deliverySeq++
+	d := &delivery{
+		id:          s.deliverySeq,
+		webhook:     webhookID,
+		event:       eventID,
+		eventType:   eventType,
+		status:      model.DeliveryStatusPending,
+		nextAttempt: now,
+		createdAt:   now,
+	}
+	s.deliveries = append(s.deliveries, d)
+	return d
+}
+
+// deliveryByID returns delivery with id, deliveries are ordered by id.
+func (s *Store) deliveryByID(id int64) (*delivery, bool) {
+	i := sort.Search(len(s.deliveries), func(i int) bool {
+		return s.deliveries[i].id >= id
+	})
+	if i < len(s.deliveries) && s.deliveries[i].id == id {
+		return s.deliveries[i], true
+	}
+	return nil, false
+}
+
+// eventByID returns event of outbox with id, events are ordered by id.
+func (s *Store) eventByID(id int64) (*event, bool) {
+	i := sort.Search(len(s.outbox), func(i int) bool {
+		return s.outbox[i].ID >= id
+	})
+	if i < len(s.outbox) && s.outbox[i].ID == id {
+		return s.outbox[i], true
+	}
+	return nil, false
+}
+
+// activeWebhook returns webhook with id if it is not deleted.
+func (s *Store) activeWebhook(id int64) (*webhook, error) {
+	w, ok := s.webhooks[id]
+	if !ok || !w.active {
+		return nil, fmt.Errorf("webhook %d: %w", id, store.ErrDoesNotExists)
+	}
+	return w, nil
+}
+
+// CreateWebhook stores subscription of webhook and fills its id and creation time.
+func (s *Store) CreateWebhook(_ context.Context, w *model.WebhookDTO) error {
+	if w == nil {
+		return ErrNilReference
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.WebhookID = int64(len(s.webhookIDs)) + 1
+	w.CreatedAt = datetime.Time(time.Now())
+	stored := &webhook{WebhookDTO: *w, active: true}
+	stored.WebhookDTO = webhookDTO(stored)
+	s.webhooks[w.WebhookID] = stored
+	s.webhookIDs = append(s.webhookIDs, w.WebhookID)
+	return nil
+}
+
+// GetWebhooks returns all active webhooks with their secrets ordered by id.
+func (s *Store) GetWebhooks(_ context.Context) ([]model.WebhookDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]model.WebhookDTO, 0, len(s.webhookIDs))
+	for _, id := range s.webhookIDs {
+		if w := s.webhooks[id]; w.active {
+			res = append(res, webhookDTO(w))
+		}
+	}
+	return res, nil
+}
+
+// DeleteWebhook deactivates webhook, so it gets no new events, and fails its pending deliveries.
+//
+// Deliveries of deleted webhook stay in storage. If webhook does not exist or is already deleted then
+// store.ErrDoesNotExists is returned.
+func (s *Store) DeleteWebhook(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w, err := s.activeWebhook(id)
+	if err != nil {
+		return err
+	}
+	w.active = false
+	for _, d := range s.deliveries {
+		if d.webhook == id && d.status == model.DeliveryStatusPending {
+			d.status, d.lastError = model.DeliveryStatusFailed, "webhook deleted"
+		}
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns page of deliveries of active webhook from the newest one.
+//
+// If webhook does not exist or is deleted then store.ErrDoesNotExists is returned.
+func (s *Store) GetWebhookDeliveries(_ context.Context, id int64, limit, offset int) ([]model.WebhookDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, err := s.activeWebhook(id); err != nil {
+		return nil, err
+	}
+	var all []*delivery
+	for i := len(s.deliveries) - 1; i >= 0; i-- {
+		if s.deliveries[i].webhook == id {
+			all = append(all, s.deliveries[i])
+		}
+	}
+	from, to, err := page(len(all), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]model.WebhookDelivery, 0, to-from)
+	for _, d := range all[from:to] {
+		res = append(res, d.dto())
+	}
+	return res, nil
+}
+
+// ReplayWebhookDelivery schedules event of delivery to be delivered to its webhook again and returns new delivery.
+//
+// Original delivery is kept with its attempts. If delivery of active webhook with provided ids does not exist then
+// store.ErrDoesNotExists is returned.
+func (s *Store) ReplayWebhookDelivery(_ context.Context, webhookID, deliveryID int64) (*model.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.activeWebhook(webhookID); err != nil {
+		return nil, err
+	}
+	d, ok := s.deliveryByID(deliveryID)
+	if !ok || d.webhook != webhookID {
+		return nil, fmt.Errorf("delivery %d of webhook %d: %w", deliveryID, webhookID, store.ErrDoesNotExists)
+	}
+	res := s.addDelivery(webhookID, d.event, d.eventType, time.Now()).dto()
+	return &res, nil
+}
+
+// ClaimWebhookDeliveries returns at most limit pending deliveries which are due and postpones them for lease.
+//
+// Claimed deliveries are not returned again until lease expires, so dispatcher which fails to record attempt does not
+// lose delivery and concurrent dispatchers do not post the same delivery at once.
+func (s *Store) ClaimWebhookDeliveries(_ context.Context, limit int, lease time.Duration) ([]model.WebhookTask, error) {
+	if limit < 0 {
+		return nil, ErrBadPagination
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var due []*delivery
+	for _, d := range s.deliveries {
+		if d.status == model.DeliveryStatusPending && !d.nextAttempt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].nextAttempt.Before(due[j].nextAttempt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].id < due[j].id
+	})
+
+	res := make([]model.WebhookTask, 0, len(due))
+	for _, d := range due {
+		d.nextAttempt = now.Add(lease)
+		w := s.webhooks[d.webhook]
+		e, _ := s.eventByID(d.event)
+		res = append(res, model.WebhookTask{
+			Delivery: d.dto(),
+			URL:      w.URL,
+			Secret:   w.Secret,
+			Event:    e.Event,
+		})
+	}
+	return res, nil
+}
+
+// RecordWebhookAttempt records result of attempt to deliver pending delivery.
+//
+// If delivery is not pending anymore, for example because webhook was deleted, then store.ErrDoesNotExists is returned.
+func (s *Store) RecordWebhookAttempt(_ context.Context, id int64, attempt *model.WebhookAttempt) error {
+	if attempt == nil {
+		return ErrNilReference
+	}
+	switch attempt.Status {
+	case model.DeliveryStatusPending, model.DeliveryStatusDelivered, model.DeliveryStatusFailed:
+	default:
+		return fmt.Errorf("delivery status %q: %w", attempt.Status, store.ErrCheckViolation)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveryByID(id)
+	if !ok || d.status != model.DeliveryStatusPending {
+		return fmt.Errorf("pending delivery %d: %w", id, store.ErrDoesNotExists)
+	}
+	now := time.Now()
+	d.attempts++
+	d.status, d.lastStatusCode, d.lastError = attempt.Status, attempt.StatusCode, attempt.Error
+	d.nextAttempt = now.Add(attempt.RetryIn)
+	if attempt.Status == model.DeliveryStatusDelivered {
+		d.deliveredAt = now
+	}
+	return nil
+}
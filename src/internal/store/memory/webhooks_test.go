@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+	"time"
+)
+
+func TestStore_Webhooks_Negative(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	assert.ErrorIs(t, s.CreateWebhook(ctx, nil), ErrNilReference)
+	assert.ErrorIs(t, s.RecordWebhookAttempt(ctx, 1, nil), ErrNilReference)
+	assert.ErrorIs(t, s.RecordWebhookAttempt(ctx, 1, &model.WebhookAttempt{Status: "LOST"}), store.ErrCheckViolation)
+	assert.ErrorIs(t, s.RecordWebhookAttempt(ctx, 1, &model.WebhookAttempt{Status: model.DeliveryStatusFailed}), store.ErrDoesNotExists)
+	_, err := s.ClaimWebhookDeliveries(ctx, -1, time.Minute)
+	assert.ErrorIs(t, err, ErrBadPagination)
+
+	w := &model.WebhookDTO{URL: "http://localhost", EventTypes: []string{model.EventOrderCompleted}}
+	require.NoError(t, s.CreateWebhook(ctx, w))
+	_, err = s.GetWebhookDeliveries(ctx, w.WebhookID, -1, 0)
+	assert.ErrorIs(t, err, ErrBadPagination)
+	_, err = s.GetWebhookDeliveries(ctx, w.WebhookID+1, 1, 0)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	_, err = s.ReplayWebhookDelivery(ctx, w.WebhookID, 1)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+}
+
+func TestStore_CreateWebhook_Copy(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	w := &model.WebhookDTO{URL: "http://localhost", EventTypes: []string{model.EventOrderCompleted}}
+	require.NoError(t, s.CreateWebhook(ctx, w))
+	w.EventTypes[0] = model.EventOrderCreated
+
+	require.NoError(t, s.publish(model.EventOrderCreated, struct{}{}))
+	tasks, err := s.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "stored webhook must not change with request")
+}
@@ -0,0 +1,69 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"time"
+)
+
+// CreateAPIKey stores API key with hash and fills its id and creation time.
+//
+// If key belongs to courier who does not exist then store.ErrForeignKeyViolation is returned.
+func (s *Store) CreateAPIKey(ctx context.Context, key *model.APIKey, hash []byte) (err error) {
+	defer classify(&err)
+
+	if key == nil {
+		return ErrNilReference
+	}
+	var createdAt time.Time
+	if err = s.pool.QueryRow(
+		ctx,
+		`INSERT INTO api_keys(key_hash, role, courier_id) VALUES ($1, $2, $3) RETURNING id, created_at;`,
+		hash,
+		key.Role,
+		key.CourierID,
+	).Scan(&key.KeyID, &createdAt); err != nil {
+		return fmt.Errorf("unable to create api key: %w", err)
+	}
+	key.CreatedAt = datetime.Time(createdAt)
+	return nil
+}
+
+// GetAPIKeyByHash returns API key which is not revoked by hash of key.
+//
+// If there is no such key then store.ErrDoesNotExists is returned.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash []byte) (_ *model.APIKey, err error) {
+	defer classify(&err)
+
+	var (
+		key       model.APIKey
+		createdAt time.Time
+	)
+	if err = s.pool.QueryRow(ctx, `SELECT k.id, k.role, k.courier_id, k.created_at
+FROM api_keys k
+WHERE k.key_hash = $1
+  AND k.revoked_at IS NULL;`, hash).Scan(&key.KeyID, &key.Role, &key.CourierID, &createdAt); err != nil {
+		return nil, notFound(fmt.Errorf("unable to get api key: %w", err))
+	}
+	key.CreatedAt = datetime.Time(createdAt)
+	return &key, nil
+}
+
+// RevokeAPIKey revokes API key, revoked key stays in storage.
+//
+// If key does not exist or is already revoked then store.ErrDoesNotExists is returned.
+func (s *Store) RevokeAPIKey(ctx context.Context, id int64) (err error) {
+	defer classify(&err)
+
+	tag, err := s.pool.Exec(ctx, `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL;`, id)
+	if err != nil {
+		return fmt.Errorf("unable to revoke api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("api key %d: %w", id, store.ErrDoesNotExists)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package pgx
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"testing"
+)
+
+func TestStore_CreateAPIKey_Negative(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.ErrorIs(t, s.CreateAPIKey(context.Background(), nil, []byte("hash")), ErrNilReference)
+	assert.Error(t, s.CreateAPIKey(context.Background(), &model.APIKey{Role: model.RoleAdmin}, []byte("hash")))
+}
+
+func TestStore_GetAPIKeyByHash_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	key, err := s.GetAPIKeyByHash(context.Background(), []byte("hash"))
+	assert.Error(t, err)
+	assert.Nil(t, key)
+}
+
+func TestStore_RevokeAPIKey_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.Error(t, s.RevokeAPIKey(context.Background(), 1))
+}
@@ -0,0 +1,366 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+)
+
+// assignLockPrefix is prefix of key of advisory lock which serializes assignments of one date.
+const assignLockPrefix = "order_assignment:"
+
+// GetUnassignedOrders returns orders which wait for assignment.
+//
+// Orders which were split into sub-orders are never assigned, their sub-orders are returned instead.
+func (s *Store) GetUnassignedOrders(ctx context.Context) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
+
+	res, err = s.queryOrders(ctx, `WHERE x.status = 'CREATED'
+  AND NOT EXISTS(SELECT * FROM orders c WHERE c.parent_id = x.id)
+ORDER BY x.id;`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get orders: %w", err)
+	}
+	return res, nil
+}
+
+// GetAssignedOrders returns orders which were assigned at date and which courier has not taken yet.
+func (s *Store) GetAssignedOrders(ctx context.Context, date string) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
+
+	res, err = s.queryOrders(ctx, `WHERE x.group_id IN (SELECT g.id FROM order_group g WHERE g.date = $1)
+  AND x.status = 'ASSIGNED'
+ORDER BY x.id;`, date)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get orders: %w", err)
+	}
+	return res, nil
+}
+
+// WithAssignLock runs fn while holding advisory lock of assignment at date.
+//
+// Lock is taken on dedicated connection, so assignments of the same date are serialized between all replicas of
+// service. Lock is released when fn returns.
+func (s *Store) WithAssignLock(ctx context.Context, date string, fn func(ctx context.Context) error) (err error) {
+	defer classify(&err)
+
+	if fn == nil {
+		return ErrNilReference
+	}
+
+	var conn *pgxpool.Conn
+	conn, err = s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to acquire conn: %w", err)
+	}
+	defer conn.Release()
+
+	key := assignLockPrefix + date
+	if _, err = conn.Exec(ctx, `SELECT pg_advisory_lock(hashtext($1));`, key); err != nil {
+		return fmt.Errorf("unable to take advisory lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1));`, key); unlockErr != nil {
+			s.log.Error("unable to release advisory lock", zap.String("date", date), zap.Error(unlockErr))
+			// lock is owned by session, so closing connection releases it.
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+
+	return fn(ctx)
+}
+
+// releaseOrdersAssign takes back orders which were assigned at date and which courier has not taken yet and deletes
+// emptied groups.
+//
+// Groups with orders taken by courier and groups with provided ids are kept.
+func (s *Store) releaseOrdersAssign(ctx context.Context, tx pgx.Tx, date string, keep []int64) error {
+	rows, err := tx.Query(ctx, `UPDATE orders
+SET courier       = NULL,
+    group_id      = NULL,
+    delivery_time = NULL,
+    status        = 'CREATED'
+WHERE status = 'ASSIGNED'
+  AND group_id IN (SELECT g.id FROM order_group g WHERE g.date = $1)
+  AND NOT (group_id = ANY ($2::BIGINT[]))
+RETURNING id;`, date, keep)
+	if err != nil {
+		return fmt.Errorf("err while releasing orders: %w", err)
+	}
+	released, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return fmt.Errorf("err while releasing orders: %w", err)
+	}
+	if err = s.recordStatus(ctx, tx, released, model.OrderStatusCreated, model.ActorAssignment); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE
+FROM order_group g
+WHERE g.date = $1
+  AND NOT EXISTS(SELECT * FROM orders o WHERE o.group_id = g.id);`, date); err != nil {
+		return fmt.Errorf("err while deleting groups: %w", err)
+	}
+	return nil
+}
+
+// saveGroup stores group of orders of courier and fills created group id.
+//
+// Group with non-zero id must already exist at date, it is updated and new orders are added to it. If any new order of
+// group is already assigned to another group or does not wait for assignment then store.ErrAlreadyAssigned will be
+// returned.
+func (s *Store) saveGroup(ctx context.Context, tx pgx.Tx, date string, courier int64, group *model.GroupOrders) error {
+	const (
+		groupQuery = `INSERT INTO order_group(date, courier, start_time, end_time)
+VALUES ($1, $2, $3, $4)
+RETURNING id;`
+		updateGroupQuery = `UPDATE order_group
+SET start_time = $4,
+    end_time   = $5
+WHERE id = $1
+  AND date = $2
+  AND courier = $3;`
+		assignQuery = `UPDATE orders
+SET courier           = $1,
+    group_id          = $2,
+    delivery_time     = $3,
+    unassigned_reason = NULL,
+    status            = 'ASSIGNED'
+WHERE id = $4
+  AND group_id IS NULL
+  AND status = 'CREATED'
+RETURNING regions;`
+		keepQuery = `UPDATE orders
+SET delivery_time = $3
+WHERE id = $4
+  AND courier = $1
+  AND group_id = $2;`
+	)
+	var start, end *int32
+	if group.DeliveryWindow != nil {
+		s, e := int32(group.DeliveryWindow.Start()), int32(group.DeliveryWindow.End())
+		start, end = &s, &e
+	}
+
+	if group.GroupOrderID != 0 {
+		tag, err := tx.Exec(ctx, updateGroupQuery, group.GroupOrderID, date, courier, start, end)
+		if err != nil {
+			return fmt.Errorf("err while updating order group: %w", err)
+		}
+		if tag.RowsAffected() != 1 {
+			return fmt.Errorf("group %d: %w", group.GroupOrderID, store.ErrAlreadyAssigned)
+		}
+	} else if err := tx.QueryRow(ctx, groupQuery, date, courier, start, end).Scan(&group.GroupOrderID); err != nil {
+		return fmt.Errorf("err while creating order group: %w", err)
+	}
+
+	assigned := make([]int64, 0, len(group.Orders))
+	events := make([]any, 0, len(group.Orders))
+	for _, order := range group.Orders {
+		var deliveryTime *int32
+		if order.DeliveryTime != nil {
+			t := int32(*order.DeliveryTime)
+			deliveryTime = &t
+		}
+		var region int32
+		err := tx.QueryRow(ctx, assignQuery, courier, group.GroupOrderID, deliveryTime, order.OrderID).Scan(&region)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("err while assigning order: %w", err)
+		}
+		if err == nil {
+			assigned = append(assigned, order.OrderID)
+			events = append(events, &model.OrderAssignedEvent{
+				OrderID:      order.OrderID,
+				CourierID:    courier,
+				GroupOrderID: group.GroupOrderID,
+				Region:       region,
+				Date:         date,
+				DeliveryTime: order.DeliveryTime,
+			})
+			continue
+		}
+		tag, err := tx.Exec(ctx, keepQuery, courier, group.GroupOrderID, deliveryTime, order.OrderID)
+		if err != nil {
+			return fmt.Errorf("err while assigning order: %w", err)
+		}
+		if tag.RowsAffected() != 1 {
+			return fmt.Errorf("order %d: %w", order.OrderID, store.ErrAlreadyAssigned)
+		}
+	}
+	if err := s.recordStatus(ctx, tx, assigned, model.OrderStatusAssigned, model.ActorAssignment); err != nil {
+		return err
+	}
+	return s.publish(ctx, tx, model.EventOrderAssigned, events...)
+}
+
+// SaveOrdersAssign stores all groups of orders from assignment and reasons of unassigned orders in one transaction.
+//
+// If orders were already assigned at date then previous assignment is replaced: orders which courier has not taken yet
+// are taken back from their groups before new groups are stored. Groups of response with non-zero id are kept and extended with
+// new orders. Ids of created groups are written into provided response.
+func (s *Store) SaveOrdersAssign(ctx context.Context, resp *model.OrderAssignResponse) (err error) {
+	defer classify(&err)
+
+	if resp == nil {
+		return ErrNilReference
+	}
+
+	var tx pgx.Tx
+	tx, err = s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("check drivers: unable to begin tx: %w", err)
+	}
+
+	defer s.rollback(ctx, tx)
+
+	if _, err = tx.Exec(
+		ctx,
+		`INSERT INTO order_assignment(date, strategy)
+VALUES ($1, NULLIF($2, ''))
+ON CONFLICT (date) DO UPDATE SET strategy   = excluded.strategy,
+                                 created_at = now();`,
+		resp.Date,
+		resp.Strategy,
+	); err != nil {
+		return fmt.Errorf("err while saving assignment: %w", err)
+	}
+
+	keep := []int64{}
+	for _, courier := range resp.Couriers {
+		for _, group := range courier.Orders {
+			if group.GroupOrderID != 0 {
+				keep = append(keep, group.GroupOrderID)
+			}
+		}
+	}
+	if err = s.releaseOrdersAssign(ctx, tx, resp.Date, keep); err != nil {
+		return err
+	}
+
+	for i := range resp.Couriers {
+		courier := &resp.Couriers[i]
+		for j := range courier.Orders {
+			if err = s.saveGroup(ctx, tx, resp.Date, courier.CourierID, &courier.Orders[j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, order := range resp.Unassigned {
+		if _, err = tx.Exec(
+			ctx,
+			`UPDATE orders SET unassigned_reason = $1 WHERE id = $2;`,
+			order.Reason,
+			order.OrderID,
+		); err != nil {
+			return fmt.Errorf("err while saving unassigned reason: %w", err)
+		}
+	}
+
+	if err = s.publish(ctx, tx, model.EventOrdersAssigned, resp); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// GetOrdersAssign returns groups of orders that were assigned at date with strategy which was used.
+//
+// If courierID is zero then groups of all couriers will be returned. If orders were never assigned at date then
+// store.ErrDoesNotExists will be returned. Couriers without groups are not included into response.
+func (s *Store) GetOrdersAssign(ctx context.Context, date string, courierID int64) (resp *model.OrderAssignResponse, err error) {
+	defer classify(&err)
+
+	const query = `SELECT g.id, g.courier, g.start_time, g.end_time, o.id
+FROM order_group g
+         JOIN orders o ON o.group_id = g.id
+WHERE g.date = $1
+  AND ($2::BIGINT = 0 OR g.courier = $2::BIGINT)
+ORDER BY g.courier, g.id, (COALESCE(o.delivery_time, 0) - COALESCE(g.start_time, 0) + 1440) % 1440, o.id;`
+	var (
+		strategy *string
+		rows     pgx.Rows
+	)
+
+	if err = s.pool.QueryRow(
+		ctx,
+		`SELECT x.strategy FROM order_assignment x WHERE x.date = $1;`,
+		date,
+	).Scan(&strategy); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, store.ErrDoesNotExists
+		}
+		return nil, fmt.Errorf("err while checking assignment: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, query, date, courierID)
+	if err != nil {
+		return nil, fmt.Errorf("err while doing query: %w", err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		group   int64
+		courier int64
+		start   *int32
+		end     *int32
+		order   int64
+	}
+	var (
+		res []row
+		ids []int64
+	)
+	for rows.Next() {
+		var r row
+		if err = rows.Scan(&r.group, &r.courier, &r.start, &r.end, &r.order); err != nil {
+			return nil, fmt.Errorf("error while scanning from rows: %w", err)
+		}
+		res = append(res, r)
+		ids = append(ids, r.order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error from rows.Err() => %w", err)
+	}
+
+	var orders []*model.OrderDTO
+	if orders, err = s.getOrders(ctx, ids); err != nil {
+		return nil, fmt.Errorf("get orders: %w", err)
+	}
+
+	resp = &model.OrderAssignResponse{
+		Date:     date,
+		Couriers: []model.CourierGroupOrders{},
+	}
+	if strategy != nil {
+		resp.Strategy = *strategy
+	}
+	for i, r := range res {
+		if n := len(resp.Couriers); n == 0 || resp.Couriers[n-1].CourierID != r.courier {
+			resp.Couriers = append(resp.Couriers, model.CourierGroupOrders{CourierID: r.courier})
+		}
+		courier := &resp.Couriers[len(resp.Couriers)-1]
+		if n := len(courier.Orders); n == 0 || courier.Orders[n-1].GroupOrderID != r.group {
+			group := model.GroupOrders{GroupOrderID: r.group}
+			if r.start != nil && r.end != nil {
+				group.DeliveryWindow = datetime.TimeIntervalAlias{
+					Start:   *r.start,
+					End:     *r.end,
+					Reverse: *r.end < *r.start,
+				}.TimeInterval()
+			}
+			courier.Orders = append(courier.Orders, group)
+		}
+		group := &courier.Orders[len(courier.Orders)-1]
+		group.Orders = append(group.Orders, *orders[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,349 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStore_SaveOrdersAssign_Positive(t *testing.T) {
+	ctx := context.Background()
+
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	couriers, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{
+		{CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	})
+	require.NoError(t, err)
+
+	orders := []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+	require.NoError(t, s.CreateOrders(ctx, orders))
+
+	got, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, orders, got)
+
+	all, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, couriers, all)
+
+	resp := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: couriers[0].CourierID,
+			Orders:    []model.GroupOrders{{Orders: []model.OrderDTO{*orders[0]}}},
+		}},
+		Unassigned: []model.UnassignedOrder{{
+			OrderID: orders[1].OrderID,
+			Reason:  model.UnassignedReasonCapacityExhausted,
+		}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, resp))
+	assert.NotZero(t, resp.Couriers[0].Orders[0].GroupOrderID)
+
+	got, err = s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, orders[1].OrderID, got[0].OrderID)
+		assert.Equal(t, model.UnassignedReasonCapacityExhausted, got[0].UnassignedReason)
+	}
+
+	order, err := s.GetOrderByID(ctx, orders[0].OrderID)
+	require.NoError(t, err)
+	assert.Empty(t, order.UnassignedReason)
+}
+
+func TestStore_SaveOrdersAssign_Negative(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil response", func(t *testing.T) {
+		s := &Store{}
+		assert.ErrorIs(t, s.SaveOrdersAssign(ctx, nil), ErrNilReference)
+	})
+	t.Run("bad cli", func(t *testing.T) {
+		s, _ := New(client.BadCli(t))
+		assert.Error(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{}))
+	})
+}
+
+func TestStore_GetUnassignedOrders_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	resp, err := s.GetUnassignedOrders(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestStore_GetActiveCouriers_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	resp, err := s.GetActiveCouriers(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestStore_GetOrdersAssign(t *testing.T) {
+	ctx := context.Background()
+
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	resp, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	couriers, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{
+		{CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+		{CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	})
+	require.NoError(t, err)
+
+	orders := []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+	require.NoError(t, s.CreateOrders(ctx, orders))
+
+	first, second := datetime.Minute(625), datetime.Minute(635)
+	orders[0].DeliveryTime, orders[1].DeliveryTime = &second, &first
+	want := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: couriers[0].CourierID,
+			Orders: []model.GroupOrders{{
+				DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 635}.TimeInterval(),
+				Orders:         []model.OrderDTO{*orders[1], *orders[0]},
+			}},
+		}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, want))
+
+	resp, err = s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, want, resp)
+
+	resp, err = s.GetOrdersAssign(ctx, "2023-01-01", couriers[1].CourierID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Empty(t, resp.Couriers)
+	}
+}
+
+func TestStore_SaveOrdersAssign_Replace(t *testing.T) {
+	ctx := context.Background()
+
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	couriers, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{
+		{CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	})
+	require.NoError(t, err)
+
+	orders := []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+	require.NoError(t, s.CreateOrders(ctx, orders))
+
+	group := func(orders ...*model.OrderDTO) *model.OrderAssignResponse {
+		g := model.GroupOrders{}
+		for _, o := range orders {
+			g.Orders = append(g.Orders, *o)
+		}
+		return &model.OrderAssignResponse{
+			Date: "2023-01-01",
+			Couriers: []model.CourierGroupOrders{{
+				CourierID: couriers[0].CourierID,
+				Orders:    []model.GroupOrders{g},
+			}},
+		}
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, group(orders[0])))
+
+	assigned, err := s.GetAssignedOrders(ctx, "2023-01-01")
+	require.NoError(t, err)
+	if assert.Len(t, assigned, 1) {
+		assert.Equal(t, orders[0].OrderID, assigned[0].OrderID)
+	}
+
+	t.Run("already assigned order", func(t *testing.T) {
+		err := s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{
+			Date:     "2023-01-02",
+			Couriers: group(orders[0]).Couriers,
+		})
+		assert.ErrorIs(t, err, store.ErrAlreadyAssigned)
+	})
+
+	require.NoError(t, s.SaveOrdersAssign(ctx, group(orders[1], orders[0])))
+	resp, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		assert.Len(t, resp.Couriers[0].Orders[0].Orders, 2)
+	}
+}
+
+func TestStore_SaveOrdersAssign_Extend(t *testing.T) {
+	ctx := context.Background()
+
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	couriers, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{
+		{CourierType: model.BikeCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	})
+	require.NoError(t, err)
+
+	orders := []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+		{Weight: 1, Regions: 1, DeliveryHours: hours, Cost: 100},
+	}
+	require.NoError(t, s.CreateOrders(ctx, orders))
+
+	first := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: couriers[0].CourierID,
+			Orders: []model.GroupOrders{{
+				DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 612}.TimeInterval(),
+				Orders:         []model.OrderDTO{*orders[0]},
+			}},
+		}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, first))
+	id := first.Couriers[0].Orders[0].GroupOrderID
+
+	extended := &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: couriers[0].CourierID,
+			Orders: []model.GroupOrders{
+				{
+					GroupOrderID:   id,
+					DeliveryWindow: datetime.TimeIntervalAlias{Start: 600, End: 620}.TimeInterval(),
+					Orders:         []model.OrderDTO{*orders[0], *orders[1]},
+				},
+				{
+					DeliveryWindow: datetime.TimeIntervalAlias{Start: 620, End: 632}.TimeInterval(),
+					Orders:         []model.OrderDTO{*orders[2]},
+				},
+			},
+		}},
+	}
+	require.NoError(t, s.SaveOrdersAssign(ctx, extended))
+
+	resp, err := s.GetOrdersAssign(ctx, "2023-01-01", 0)
+	require.NoError(t, err)
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 2) {
+		group := resp.Couriers[0].Orders[0]
+		assert.Equal(t, id, group.GroupOrderID)
+		assert.Equal(t, "10:00-10:20", group.DeliveryWindow.String())
+		assert.Len(t, group.Orders, 2)
+		assert.NotEqual(t, id, resp.Couriers[0].Orders[1].GroupOrderID)
+	}
+
+	t.Run("unknown group", func(t *testing.T) {
+		err := s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{
+			Date: "2023-01-02",
+			Couriers: []model.CourierGroupOrders{{
+				CourierID: couriers[0].CourierID,
+				Orders:    []model.GroupOrders{{GroupOrderID: id, Orders: []model.OrderDTO{*orders[0]}}},
+			}},
+		})
+		assert.ErrorIs(t, err, store.ErrAlreadyAssigned)
+	})
+}
+
+func TestStore_WithAssignLock(t *testing.T) {
+	ctx := context.Background()
+
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	var (
+		mu      sync.Mutex
+		running int
+		maxRun  int
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.WithAssignLock(ctx, "2023-01-01", func(ctx context.Context) error {
+				mu.Lock()
+				running++
+				if running > maxRun {
+					maxRun = running
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, maxRun)
+
+	wantErr := errors.New("some error")
+	assert.ErrorIs(t, s.WithAssignLock(ctx, "2023-01-01", func(context.Context) error {
+		return wantErr
+	}), wantErr)
+}
+
+func TestStore_WithAssignLock_Negative(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil fn", func(t *testing.T) {
+		s := &Store{}
+		assert.ErrorIs(t, s.WithAssignLock(ctx, "2023-01-01", nil), ErrNilReference)
+	})
+	t.Run("bad cli", func(t *testing.T) {
+		s, _ := New(client.BadCli(t))
+		assert.Error(t, s.WithAssignLock(ctx, "2023-01-01", func(context.Context) error {
+			return nil
+		}))
+	})
+}
+
+func TestStore_GetAssignedOrders_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	resp, err := s.GetAssignedOrders(context.Background(), "2023-01-01")
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
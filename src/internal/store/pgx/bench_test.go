@@ -0,0 +1,98 @@
+package pgx
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/assign/dataset"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"testing"
+)
+
+// benchStore returns store filled with medium generated dataset.
+func benchStore(b *testing.B) (*Store, *dataset.Dataset, func()) {
+	b.Helper()
+	ctx := context.Background()
+
+	cli, td := client.NewTest(b)
+	s, err := New(cli)
+	require.NoError(b, err)
+
+	d := dataset.Generate(dataset.Medium, dataset.DefaultSeed)
+	couriers, orders := d.Requests()
+	_, err = s.CreateCouriers(ctx, couriers.Couriers)
+	require.NoError(b, err)
+	dto := make([]*model.OrderDTO, 0, len(orders.Orders))
+	for _, o := range orders.Orders {
+		dto = append(dto, &model.OrderDTO{Weight: o.Weight, Regions: o.Regions, DeliveryHours: o.DeliveryHours, Cost: o.Cost})
+	}
+	require.NoError(b, s.CreateOrders(ctx, dto))
+	return s, d, td
+}
+
+// BenchmarkStore_GetOrders compares reading page of orders with one query against reading every order separately
+// as it was done before.
+func BenchmarkStore_GetOrders(b *testing.B) {
+	ctx := context.Background()
+	s, d, td := benchStore(b)
+	defer td()
+	limit := len(d.Orders)
+
+	b.Run("set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			orders, err := s.GetOrders(ctx, limit, 0)
+			require.NoError(b, err)
+			require.Len(b, orders, limit)
+		}
+	})
+	b.Run("one by one", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for id := int64(1); id <= int64(limit); id++ {
+				_, err := s.GetOrderByID(ctx, id)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+// BenchmarkStore_GetCouriers compares reading page of couriers with one query against reading every courier
+// separately as it was done before.
+func BenchmarkStore_GetCouriers(b *testing.B) {
+	ctx := context.Background()
+	s, d, td := benchStore(b)
+	defer td()
+	limit := len(d.Couriers)
+
+	b.Run("set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			couriers, err := s.GetCouriers(ctx, limit, 0)
+			require.NoError(b, err)
+			require.Len(b, couriers, limit)
+		}
+	})
+	b.Run("one by one", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for id := int64(1); id <= int64(limit); id++ {
+				_, err := s.GetCourierByID(ctx, id)
+				require.NoError(b, err)
+			}
+		}
+	})
+}
+
+func BenchmarkStore_GetOrdersByIDs(b *testing.B) {
+	ctx := context.Background()
+	s, d, td := benchStore(b)
+	defer td()
+
+	ids := make([]int64, 0, len(d.Orders))
+	for _, o := range d.Orders {
+		ids = append(ids, o.OrderID)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		orders, err := s.GetOrdersByIDs(ctx, ids)
+		require.NoError(b, err)
+		require.Len(b, orders, len(ids))
+	}
+}
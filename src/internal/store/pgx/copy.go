@@ -0,0 +1,55 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+)
+
+// reserveIDs takes n next values from serial id sequence of table.
+//
+// Reserved ids allow to insert rows together with their children in bulk using COPY and still return ids in order
+// of input. Ids of rolled back transaction are not reused, same as with plain INSERT.
+func (s *Store) reserveIDs(ctx context.Context, tx pgx.Tx, table string, n int) ([]int64, error) {
+	const query = `SELECT nextval(pg_get_serial_sequence($1, 'id')) FROM generate_series(1, $2);`
+
+	ids := make([]int64, 0, n)
+	if n == 0 {
+		return ids, nil
+	}
+	rows, err := tx.Query(ctx, query, table, n)
+	if err != nil {
+		return nil, fmt.Errorf("reserve %s ids: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("reserve %s ids: scan: %w", table, err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("reserve %s ids: %w", table, err)
+	}
+	if len(ids) != n {
+		return nil, fmt.Errorf("reserve %s ids: got %d of %d", table, len(ids), n)
+	}
+	return ids, nil
+}
+
+// copyRows inserts rows into table with COPY protocol.
+func (s *Store) copyRows(ctx context.Context, tx pgx.Tx, table string, columns []string, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	n, err := tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return fmt.Errorf("copy into %s: %w", table, err)
+	}
+	if n != int64(len(rows)) {
+		return fmt.Errorf("copy into %s: copied %d of %d rows", table, n, len(rows))
+	}
+	return nil
+}
@@ -2,171 +2,169 @@ package pgx
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v5"
-	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
-	"go.uber.org/zap"
 )
 
-// getCourierRegions return slice of regions of courier with provided id.
-func (s *Store) getCourierRegions(ctx context.Context, id int64) (r []int32, err error) {
-	var rows pgx.Rows
-
-	r = make([]int32, 0, 3)
-
-	rows, err = s.pool.Query(ctx, `SELECT x.region FROM courier_region x WHERE x.courier_id = $1;`, id)
-	if err != nil {
-		return nil, fmt.Errorf("err while doing query: %w", err)
-	}
-	defer rows.Close()
-
-	var i int32
-	for rows.Next() {
-
-		if err = rows.Scan(&i); err != nil {
-			return nil, fmt.Errorf("error while scanning from rows: %w", err)
-		}
-
-		r = append(r, i)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error from rows.Err() => %w", err)
+// selectCouriersQuery selects couriers with their regions and working hours aggregated into arrays in one row per
+// courier.
+//
+// Query must be completed with WHERE clause over couriers x.
+const selectCouriersQuery = `SELECT x.id,
+       x.courier_type,
+       x.active,
+       coalesce(r.regions, '{}'),
+       coalesce(h.start_times, '{}'),
+       coalesce(h.end_times, '{}'),
+       coalesce(h.reversed, '{}')
+FROM couriers x
+         LEFT JOIN LATERAL (SELECT array_agg(y.region::INT4 ORDER BY y.id) AS regions
+                            FROM courier_region y
+                            WHERE y.courier_id = x.id) r ON TRUE
+         LEFT JOIN LATERAL (SELECT array_agg(y.start_time ORDER BY y.id) AS start_times,
+                                   array_agg(y.end_time ORDER BY y.id)   AS end_times,
+                                   array_agg(y.reversed ORDER BY y.id)   AS reversed
+                            FROM courier_working_hour y
+                            WHERE y.courier_id = x.id) h ON TRUE
+`
+
+// scanCourier scans courier selected with selectCouriersQuery.
+func scanCourier(row pgx.Row) (*model.CourierDTO, error) {
+	var (
+		c     = new(model.CourierDTO)
+		hours hoursArrays
+	)
+	if err := row.Scan(&c.CourierID, &c.CourierType, &c.Active, &c.Regions, &hours.starts, &hours.ends, &hours.reversed); err != nil {
+		return nil, err
 	}
-	zap.L().Error("error", zap.Error(err))
-
-	return r, nil
+	c.WorkingHours = hours.intervals()
+	return c, nil
 }
 
-func (s *Store) getCourierWorkingHours(ctx context.Context, id int64) (r []*datetime.TimeInterval, err error) {
+// queryCouriers returns couriers selected with selectCouriersQuery completed with provided clause.
+func (s *Store) queryCouriers(ctx context.Context, clause string, args ...any) (res []model.CourierDTO, err error) {
 	var rows pgx.Rows
 
-	r = make([]*datetime.TimeInterval, 0, 8)
-
-	rows, err = s.pool.Query(ctx, `SELECT x.start_time, x.end_time, x.reversed
-FROM courier_working_hour x
-WHERE x.courier_id = $1;`, id)
+	rows, err = s.pool.Query(ctx, selectCouriersQuery+clause, args...)
 	if err != nil {
 		return nil, fmt.Errorf("err while doing query: %w", err)
 	}
 	defer rows.Close()
 
-	var h datetime.TimeIntervalAlias
-
+	res = make([]model.CourierDTO, 0)
 	for rows.Next() {
-		if err = rows.Scan(&h.Start, &h.End, &h.Reverse); err != nil {
+		var c *model.CourierDTO
+		if c, err = scanCourier(rows); err != nil {
 			return nil, fmt.Errorf("error while scanning from rows: %w", err)
 		}
-
-		r = append(r, h.TimeInterval())
+		res = append(res, *c)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error from rows.Err() => %w", err)
 	}
-
-	return r, nil
+	return res, nil
 }
 
 func (s *Store) GetCourierByID(ctx context.Context, id int64) (courier *model.CourierDTO, err error) {
-	courier = &model.CourierDTO{
-		CourierID: id,
-	}
-
-	if err = s.pool.QueryRow(
-		ctx,
-		`SELECT x.courier_type FROM couriers x WHERE x.id=$1;`,
-		id,
-	).Scan(&courier.CourierType); err != nil {
-		return nil, fmt.Errorf("unknown err while scanning: %w", err)
-	}
+	defer classify(&err)
 
-	courier.Regions, err = s.getCourierRegions(ctx, id)
+	courier, err = scanCourier(s.pool.QueryRow(ctx, selectCouriersQuery+`WHERE x.id = $1;`, id))
 	if err != nil {
-		return nil, fmt.Errorf("unknown err while getting regions: %w", err)
+		return nil, notFound(fmt.Errorf("unknown err while scanning: %w", err))
 	}
-	courier.WorkingHours, err = s.getCourierWorkingHours(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("unknown err while getting working hours: %w", err)
-	}
-
 	return courier, nil
 }
 
-func (s *Store) addRegionsToCourier(ctx context.Context, tx pgx.Tx, courier model.CourierDTO) (err error) {
-	for _, region := range courier.Regions {
-		if _, err = tx.Exec(
-			ctx,
-			`INSERT INTO courier_region(region, courier_id) VALUES ($1, $2);`,
-			region,
-			courier.CourierID,
-		); err != nil {
-			return fmt.Errorf("err while adding courier regions")
+func (s *Store) addRegionsToCouriers(ctx context.Context, tx pgx.Tx, couriers []model.CourierDTO) error {
+	rows := make([][]any, 0, len(couriers))
+	for _, courier := range couriers {
+		for _, region := range courier.Regions {
+			rows = append(rows, []any{int64(region), courier.CourierID})
 		}
 	}
+	if err := s.copyRows(ctx, tx, "courier_region", []string{"region", "courier_id"}, rows); err != nil {
+		return fmt.Errorf("err while adding courier regions: %w", err)
+	}
 	return nil
 }
 
-func (s *Store) addWorkingHoursToCourier(ctx context.Context, tx pgx.Tx, dto model.CourierDTO) (err error) {
-	for _, wh := range dto.WorkingHours {
-		if _, err = tx.Exec(
-			ctx,
-			`INSERT INTO courier_working_hour(courier_id, start_time, end_time, reversed) VALUES ($1, $2, $3, $4);`,
-			dto.CourierID,
-			int(wh.Start()),
-			int(wh.End()),
-			wh.Start() > wh.End(),
-		); err != nil {
-			return fmt.Errorf("err while adding courier regions: %w", err)
+func (s *Store) addWorkingHoursToCouriers(ctx context.Context, tx pgx.Tx, couriers []model.CourierDTO) error {
+	rows := make([][]any, 0, len(couriers))
+	for _, courier := range couriers {
+		for _, wh := range courier.WorkingHours {
+			rows = append(rows, []any{courier.CourierID, int32(wh.Start()), int32(wh.End()), wh.Start() > wh.End()})
 		}
 	}
+	if err := s.copyRows(
+		ctx,
+		tx,
+		"courier_working_hour",
+		[]string{"courier_id", "start_time", "end_time", "reversed"},
+		rows,
+	); err != nil {
+		return fmt.Errorf("err while adding courier working hours: %w", err)
+	}
 	return nil
 }
 
-func (s *Store) createCourier(ctx context.Context, tx pgx.Tx, courier model.CreateCourierDTO) (model.CourierDTO, error) {
-	res := model.CourierDTO{
-		CourierType:  courier.CourierType,
-		Regions:      courier.Regions,
-		WorkingHours: courier.WorkingHours,
+// createCouriers stores couriers with their regions and working hours using COPY and returns them with ids in order
+// of input.
+func (s *Store) createCouriers(ctx context.Context, tx pgx.Tx, couriers []model.CreateCourierDTO) ([]model.CourierDTO, error) {
+	ids, err := s.reserveIDs(ctx, tx, "couriers", len(couriers))
+	if err != nil {
+		return nil, err
 	}
-	if err := tx.QueryRow(
-		ctx,
-		`INSERT INTO couriers(courier_type) VALUES ($1) RETURNING id;`,
-		courier.CourierType,
-	).Scan(&res.CourierID); err != nil {
-		return model.CourierDTO{}, err
+
+	res := make([]model.CourierDTO, 0, len(couriers))
+	rows := make([][]any, 0, len(couriers))
+	for i, courier := range couriers {
+		res = append(res, model.CourierDTO{
+			CourierID:    ids[i],
+			CourierType:  courier.CourierType,
+			Regions:      courier.Regions,
+			WorkingHours: courier.WorkingHours,
+			Active:       true,
+		})
+		rows = append(rows, []any{ids[i], courier.CourierType})
 	}
-	if err := s.addWorkingHoursToCourier(ctx, tx, res); err != nil {
-		return model.CourierDTO{}, fmt.Errorf("error while adding WH to courier: %w", err)
+
+	if err = s.copyRows(ctx, tx, "couriers", []string{"id", "courier_type"}, rows); err != nil {
+		return nil, err
 	}
-	if err := s.addRegionsToCourier(ctx, tx, res); err != nil {
-		return model.CourierDTO{}, fmt.Errorf("error while adding regions to courier: %w", err)
+	if err = s.addWorkingHoursToCouriers(ctx, tx, res); err != nil {
+		return nil, fmt.Errorf("error while adding WH to couriers: %w", err)
+	}
+	if err = s.addRegionsToCouriers(ctx, tx, res); err != nil {
+		return nil, fmt.Errorf("error while adding regions to couriers: %w", err)
 	}
-
 	return res, nil
 }
 
+// CreateCouriers stores all couriers in one transaction and returns them with ids in order of input.
 func (s *Store) CreateCouriers(ctx context.Context, couriers []model.CreateCourierDTO) (r []model.CourierDTO, err error) {
+	defer classify(&err)
+
 	var tx pgx.Tx
 
 	tx, err = s.pool.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to start transaction: check drivers: %w", err)
 	}
-	defer func() {
-		s.log.Error("tx rollback", zap.NamedError("tx_error", tx.Rollback(ctx)))
-	}()
-	r = make([]model.CourierDTO, 0, len(couriers))
+	defer s.rollback(ctx, tx)
 
-	var c model.CourierDTO
-	for _, courier := range couriers {
-		c, err = s.createCourier(ctx, tx, courier)
-		if err != nil {
-			return nil, fmt.Errorf("error while creating courier: %w", err)
-		}
-		r = append(r, c)
+	r, err = s.createCouriers(ctx, tx, couriers)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating couriers: %w", err)
+	}
+	events := make([]any, 0, len(r))
+	for i := range r {
+		events = append(events, &r[i])
+	}
+	if err = s.publish(ctx, tx, model.EventCourierCreated, events...); err != nil {
+		return nil, err
 	}
 	if err = tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("error while committing: update drivers: %w", err)
@@ -175,50 +173,108 @@ func (s *Store) CreateCouriers(ctx context.Context, couriers []model.CreateCouri
 	return r, nil
 }
 
-func (s *Store) getCouriers(ctx context.Context, ids []int64) (res []model.CourierDTO, err error) {
-	res = make([]model.CourierDTO, 0, len(ids))
-	var courier *model.CourierDTO
-	for _, id := range ids {
-		courier, err = s.GetCourierByID(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		res = append(res, *courier)
+// GetCouriers returns page of active couriers ordered by id.
+func (s *Store) GetCouriers(ctx context.Context, limit int, offset int) (res []model.CourierDTO, err error) {
+	defer classify(&err)
+
+	res, err = s.queryCouriers(ctx, `WHERE x.id IN (SELECT y.id FROM couriers y WHERE y.active ORDER BY y.id OFFSET $1 ROWS FETCH NEXT $2 ROWS ONLY)
+ORDER BY x.id;`, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get couriers: %w", err)
 	}
 	return res, nil
 }
 
-func (s *Store) GetCouriers(ctx context.Context, limit int, offset int) (res []model.CourierDTO, err error) {
-	const query = `SELECT x.id FROM couriers x ORDER BY x.id OFFSET $1 ROWS FETCH NEXT $2 ROWS ONLY;`
-	var rows pgx.Rows
+// GetCouriersAfter returns at most limit active couriers with id greater than afterID ordered by id.
+func (s *Store) GetCouriersAfter(ctx context.Context, afterID int64, limit int) (res []model.CourierDTO, err error) {
+	defer classify(&err)
 
-	ids := make([]int64, 0, limit)
+	res, err = s.queryCouriers(ctx, `WHERE x.id IN (SELECT y.id FROM couriers y WHERE y.active AND y.id > $1 ORDER BY y.id FETCH NEXT $2 ROWS ONLY)
+ORDER BY x.id;`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get couriers: %w", err)
+	}
+	return res, nil
+}
 
-	rows, err = s.pool.Query(ctx, query, offset, limit)
+// GetActiveCouriers returns all couriers which are not deactivated ordered by id.
+func (s *Store) GetActiveCouriers(ctx context.Context) (res []model.CourierDTO, err error) {
+	defer classify(&err)
+
+	res, err = s.queryCouriers(ctx, `WHERE x.active
+ORDER BY x.id;`)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return []model.CourierDTO{}, nil
-		}
-		return nil, fmt.Errorf("unable to get users: %w", err)
+		return nil, fmt.Errorf("unable to get couriers: %w", err)
 	}
+	return res, nil
+}
 
-	for rows.Next() {
-		var id int64
+// UpdateCourier changes fields of courier which are provided in request in one transaction and returns updated courier.
+//
+// Provided regions and working hours replace stored ones. If courier does not exist then store.ErrDoesNotExists is
+// returned.
+func (s *Store) UpdateCourier(ctx context.Context, id int64, req *model.UpdateCourierRequest) (courier *model.CourierDTO, err error) {
+	defer classify(&err)
 
-		if err = rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("unable to scan id: %w", err)
-		}
+	if req == nil {
+		return nil, ErrNilReference
+	}
 
-		ids = append(ids, id)
+	var tx pgx.Tx
+	tx, err = s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start transaction: %w", err)
 	}
+	defer s.rollback(ctx, tx)
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("err from rows.Err(): %w", err)
+	if err = tx.QueryRow(
+		ctx,
+		`UPDATE couriers SET courier_type = COALESCE($2, courier_type) WHERE id = $1 RETURNING id;`,
+		id,
+		req.CourierType,
+	).Scan(&id); err != nil {
+		return nil, notFound(fmt.Errorf("update courier: %w", err))
+	}
+
+	updated := []model.CourierDTO{{CourierID: id, Regions: req.Regions, WorkingHours: req.WorkingHours}}
+	if req.Regions != nil {
+		if _, err = tx.Exec(ctx, `DELETE FROM courier_region WHERE courier_id = $1;`, id); err != nil {
+			return nil, fmt.Errorf("delete courier regions: %w", err)
+		}
+		if err = s.addRegionsToCouriers(ctx, tx, updated); err != nil {
+			return nil, err
+		}
+	}
+	if req.WorkingHours != nil {
+		if _, err = tx.Exec(ctx, `DELETE FROM courier_working_hour WHERE courier_id = $1;`, id); err != nil {
+			return nil, fmt.Errorf("delete courier working hours: %w", err)
+		}
+		if err = s.addWorkingHoursToCouriers(ctx, tx, updated); err != nil {
+			return nil, err
+		}
+	}
+
+	if courier, err = scanCourier(tx.QueryRow(ctx, selectCouriersQuery+`WHERE x.id = $1;`, id)); err != nil {
+		return nil, fmt.Errorf("get updated courier: %w", err)
 	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return courier, nil
+}
+
+// DeactivateCourier marks courier as deactivated. Deactivation of deactivated courier does nothing.
+//
+// If courier does not exist then store.ErrDoesNotExists is returned.
+func (s *Store) DeactivateCourier(ctx context.Context, id int64) (err error) {
+	defer classify(&err)
 
-	res, err = s.getCouriers(ctx, ids)
+	tag, err := s.pool.Exec(ctx, `UPDATE couriers SET active = FALSE WHERE id = $1;`, id)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("deactivate courier: %w", err)
 	}
-	return res, nil
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("courier %d: %w", id, store.ErrDoesNotExists)
+	}
+	return nil
 }
@@ -13,7 +13,7 @@ import (
 	"testing"
 )
 
-func TestStore_getCourierRegions_PositiveNoData(t *testing.T) {
+func TestStore_GetCourierByID_PositiveNoData(t *testing.T) {
 	ctx := context.Background()
 
 	cli, td := client.NewTest(t)
@@ -22,52 +22,24 @@ func TestStore_getCourierRegions_PositiveNoData(t *testing.T) {
 	s, err := New(cli)
 	require.NoError(t, err)
 
-	var regions []int32
-	regions, err = s.getCourierRegions(ctx, 0)
-
-	assert.NoError(t, err)
-	if assert.NotNil(t, regions) {
-		assert.Empty(t, regions)
-	}
-}
-
-func TestStore_getCourierRegions_Negative(t *testing.T) {
-	ctx := context.Background()
-
-	cli := client.BadCli(t)
-
-	s, err := New(cli)
-	require.NoError(t, err)
-
-	var regions []int32
-	regions, err = s.getCourierRegions(ctx, 0)
-
-	assert.Error(t, err)
-	t.Log(err)
-	if assert.Nil(t, regions) {
-		assert.Empty(t, regions)
-	}
-}
-
-func TestStore_getCourierWorkingHours_PositiveNoData(t *testing.T) {
-	ctx := context.Background()
-
-	cli, td := client.NewTest(t)
-	defer td()
-
-	s, err := New(cli)
+	var couriers []model.CourierDTO
+	couriers, err = s.CreateCouriers(ctx, []model.CreateCourierDTO{{CourierType: model.FootCourierTypeString}})
 	require.NoError(t, err)
 
-	var hours []*datetime.TimeInterval
-	hours, err = s.getCourierWorkingHours(ctx, 0)
-
+	var courier *model.CourierDTO
+	courier, err = s.GetCourierByID(ctx, couriers[0].CourierID)
 	assert.NoError(t, err)
-	if assert.NotNil(t, hours) {
-		assert.Empty(t, hours)
+	if assert.NotNil(t, courier) {
+		if assert.NotNil(t, courier.Regions) {
+			assert.Empty(t, courier.Regions)
+		}
+		if assert.NotNil(t, courier.WorkingHours) {
+			assert.Empty(t, courier.WorkingHours)
+		}
 	}
 }
 
-func TestStore_getCourierWorkingHours_Negative(t *testing.T) {
+func TestStore_queryCouriers_Negative(t *testing.T) {
 	ctx := context.Background()
 
 	cli := client.BadCli(t)
@@ -75,14 +47,12 @@ func TestStore_getCourierWorkingHours_Negative(t *testing.T) {
 	s, err := New(cli)
 	require.NoError(t, err)
 
-	var hours []*datetime.TimeInterval
-	hours, err = s.getCourierWorkingHours(ctx, 0)
+	var couriers []model.CourierDTO
+	couriers, err = s.queryCouriers(ctx, `ORDER BY x.id;`)
 
 	assert.Error(t, err)
 	t.Log(err)
-	if assert.Nil(t, hours) {
-		assert.Empty(t, hours)
-	}
+	assert.Nil(t, couriers)
 }
 
 func TestStore_GetCourierByID_NegativeNotFound(t *testing.T) {
@@ -102,7 +72,7 @@ func TestStore_GetCourierByID_NegativeNotFound(t *testing.T) {
 	}
 }
 
-func TestStore_addRegionsToCourier_Negative_NonExists(t *testing.T) {
+func TestStore_addRegionsToCouriers_Negative_NonExists(t *testing.T) {
 	var (
 		tx pgx.Tx
 	)
@@ -118,11 +88,11 @@ func TestStore_addRegionsToCourier_Negative_NonExists(t *testing.T) {
 	defer assert.NoError(t, tx.Rollback(ctx))
 	require.NoError(t, err)
 
-	err = s.addRegionsToCourier(ctx, tx, model.CourierDTO{CourierID: 1, Regions: []int32{1, 2, 3}})
+	err = s.addRegionsToCouriers(ctx, tx, []model.CourierDTO{{CourierID: 1, Regions: []int32{1, 2, 3}}})
 	assert.Error(t, err)
 }
 
-func TestStore_addWorkingHoursToCourier_Negative_NonExists(t *testing.T) {
+func TestStore_addWorkingHoursToCouriers_Negative_NonExists(t *testing.T) {
 	var (
 		tx pgx.Tx
 	)
@@ -138,9 +108,9 @@ func TestStore_addWorkingHoursToCourier_Negative_NonExists(t *testing.T) {
 	defer assert.NoError(t, tx.Rollback(ctx))
 	require.NoError(t, err)
 
-	err = s.addWorkingHoursToCourier(ctx, tx, model.CourierDTO{CourierID: 1, WorkingHours: []*datetime.TimeInterval{
+	err = s.addWorkingHoursToCouriers(ctx, tx, []model.CourierDTO{{CourierID: 1, WorkingHours: []*datetime.TimeInterval{
 		datetime.TimeIntervalAlias{Start: 123, End: 321}.TimeInterval(),
-	}})
+	}}})
 	assert.Error(t, err)
 }
 
@@ -177,22 +147,16 @@ func TestStore_CreateCouriers_Positive(t *testing.T) {
 	if assert.NotNilf(t, resp, "err = %v, resp=%v", err, resp) {
 		require.NotEmpty(t, resp)
 		courier := resp[0]
-		var (
-			wh      []*datetime.TimeInterval
-			regions []int32
-			c       *model.CourierDTO
-		)
+		var c *model.CourierDTO
 		assert.Equal(t, int64(1), courier.CourierID)
-		wh, err = s.getCourierWorkingHours(ctx, courier.CourierID)
-		assert.NoErrorf(t, err, "unxepectedly got non nil error: %v", err)
-		assert.Equal(t, courier.WorkingHours, wh)
-
-		regions, err = s.getCourierRegions(ctx, courier.CourierID)
-		assert.NoError(t, err)
-		assert.Equal(t, courier.Regions, regions)
 
 		c, err = s.GetCourierByID(ctx, courier.CourierID)
-		assert.Equal(t, courier, *c)
+		assert.NoErrorf(t, err, "unxepectedly got non nil error: %v", err)
+		if assert.NotNil(t, c) {
+			assert.Equal(t, courier.WorkingHours, c.WorkingHours)
+			assert.Equal(t, courier.Regions, c.Regions)
+			assert.Equal(t, courier, *c)
+		}
 	}
 }
 
@@ -243,7 +207,7 @@ func TestStore_CreateCouriers_Negative_BadData(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
-func TestStore_createCourier_Negative(t *testing.T) {
+func TestStore_createCouriers_Negative(t *testing.T) {
 	if testing.Short() {
 		return
 	}
@@ -259,19 +223,19 @@ func TestStore_createCourier_Negative(t *testing.T) {
 	tx, err = cli.P().Begin(ctx)
 	assert.NoError(t, err)
 
-	couriers := model.CreateCourierDTO{
+	couriers := []model.CreateCourierDTO{{
 		CourierType: model.BikeCourierTypeString,
 		Regions:     []int32{1, 3},
 		WorkingHours: []*datetime.TimeInterval{
 			datetime.TimeIntervalAlias{Start: 123, End: 321}.TimeInterval(),
 			datetime.TimeIntervalAlias{Start: 332, End: 400}.TimeInterval(),
 		},
-	}
+	}}
 	i, err := migrator.MigrateDown(cli)
 	t.Log(i)
 	require.NoError(t, err)
-	var resp model.CourierDTO
-	resp, err = s.createCourier(ctx, tx, couriers)
+	var resp []model.CourierDTO
+	resp, err = s.createCouriers(ctx, tx, couriers)
 	assert.Error(t, err)
 	assert.Empty(t, resp)
 }
@@ -345,3 +309,19 @@ func TestStore_GetCouriers_NegativeBadCli(t *testing.T) {
 	assert.Nil(t, resp)
 	assert.Error(t, err)
 }
+
+func TestStore_UpdateCourier_Negative_BadCli(t *testing.T) {
+	cli := client.BadCli(t)
+	s, err := New(cli)
+	require.NoError(t, err)
+	resp, err := s.UpdateCourier(context.Background(), 1, &model.UpdateCourierRequest{Regions: []int32{1}})
+	assert.Nil(t, resp)
+	assert.Error(t, err)
+}
+
+func TestStore_DeactivateCourier_Negative_BadCli(t *testing.T) {
+	cli := client.BadCli(t)
+	s, err := New(cli)
+	require.NoError(t, err)
+	assert.Error(t, s.DeactivateCourier(context.Background(), 1))
+}
@@ -9,157 +9,284 @@ import (
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
 	"go.uber.org/multierr"
-	"go.uber.org/zap"
 	"time"
 )
 
-func (s *Store) getDeliveryHoursOfOrder(ctx context.Context, id int64) (r []*datetime.TimeInterval, err error) {
-	const query = `SELECT x.start_time, x.end_time, x.reversed
-FROM orders_delivery_hours x
-WHERE x.order_id = $1;`
-	var rows pgx.Rows
+// selectOrdersQuery selects orders with their delivery hours aggregated into arrays in one row per order.
+//
+// Query must be completed with WHERE clause over orders x.
+const selectOrdersQuery = `SELECT x.id,
+       x.weight,
+       x.regions,
+       x.cost,
+       x.completed_time,
+       x.delivery_time,
+       coalesce(x.unassigned_reason, ''),
+       coalesce(x.parent_id, 0),
+       x.status,
+       coalesce(x.cancel_reason, ''),
+       coalesce(h.start_times, '{}'),
+       coalesce(h.end_times, '{}'),
+       coalesce(h.reversed, '{}')
+FROM orders x
+         LEFT JOIN LATERAL (SELECT array_agg(y.start_time ORDER BY y.id) AS start_times,
+                                   array_agg(y.end_time ORDER BY y.id)   AS end_times,
+                                   array_agg(y.reversed ORDER BY y.id)   AS reversed
+                            FROM orders_delivery_hours y
+                            WHERE y.order_id = x.id) h ON TRUE
+`
+
+// scanOrder scans order selected with selectOrdersQuery.
+func scanOrder(row pgx.Row) (*model.OrderDTO, error) {
+	var (
+		o             = new(model.OrderDTO)
+		completedTime *time.Time
+		deliveryTime  *int32
+		hours         hoursArrays
+	)
+	if err := row.Scan(
+		&o.OrderID,
+		&o.Weight,
+		&o.Regions,
+		&o.Cost,
+		&completedTime,
+		&deliveryTime,
+		&o.UnassignedReason,
+		&o.ParentOrderID,
+		&o.Status,
+		&o.CancelReason,
+		&hours.starts,
+		&hours.ends,
+		&hours.reversed,
+	); err != nil {
+		return nil, err
+	}
+	if completedTime != nil {
+		o.CompletedTime = datetime.Time(*completedTime)
+	}
+	o.DeliveryTime = minuteFromNullable(deliveryTime)
+	o.DeliveryHours = hours.intervals()
+	return o, nil
+}
 
-	r = make([]*datetime.TimeInterval, 0, 8)
+// hoursArrays is time intervals aggregated into arrays by columns.
+type hoursArrays struct {
+	starts   []int32
+	ends     []int32
+	reversed []bool
+}
 
-	rows, err = s.pool.Query(ctx, query, id)
+// intervals returns time intervals from arrays, it is never nil.
+func (h hoursArrays) intervals() []*datetime.TimeInterval {
+	res := make([]*datetime.TimeInterval, 0, len(h.starts))
+	for i := range h.starts {
+		res = append(res, datetime.TimeIntervalAlias{
+			Start:   h.starts[i],
+			End:     h.ends[i],
+			Reverse: h.reversed[i],
+		}.TimeInterval())
+	}
+	return res
+}
+
+// queryOrders returns orders selected with selectOrdersQuery completed with provided clause.
+func (s *Store) queryOrders(ctx context.Context, clause string, args ...any) (res []*model.OrderDTO, err error) {
+	var rows pgx.Rows
+
+	rows, err = s.pool.Query(ctx, selectOrdersQuery+clause, args...)
 	if err != nil {
 		return nil, fmt.Errorf("err while doing query: %w", err)
 	}
 	defer rows.Close()
 
-	var h datetime.TimeIntervalAlias
-
+	res = make([]*model.OrderDTO, 0)
 	for rows.Next() {
-		if err = rows.Scan(&h.Start, &h.End, &h.Reverse); err != nil {
+		var o *model.OrderDTO
+		if o, err = scanOrder(rows); err != nil {
 			return nil, fmt.Errorf("error while scanning from rows: %w", err)
 		}
-
-		r = append(r, h.TimeInterval())
+		res = append(res, o)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("error from rows.Err() => %w", err)
 	}
-
-	return r, nil
+	return res, nil
 }
 
 func (s *Store) GetOrderByID(ctx context.Context, id int64) (o *model.OrderDTO, err error) {
-	const query = `SELECT x.weight, x.regions, x.cost, coalesce(x.completed_time, '1000-01-01'::timestamp)
-FROM orders x
-WHERE x.id = $1;`
-	o = &model.OrderDTO{
-		OrderID: id,
-	}
-	var t time.Time
-	if err = s.pool.QueryRow(ctx, query, id).Scan(&o.Weight, &o.Regions, &o.Cost, &t); err != nil {
-		return nil, fmt.Errorf("pgxpool: scan: %w", err)
-	}
-	o.CompletedTime = datetime.Time(t)
-	if year, month, day := t.Date(); year == 1000 && month == time.January && day == 1 {
-		o.CompletedTime = datetime.Time{}
-	}
+	defer classify(&err)
 
-	if o.DeliveryHours, err = s.getDeliveryHoursOfOrder(ctx, id); err != nil {
-		return nil, fmt.Errorf("get delivery hours of order: %w", err)
+	o, err = scanOrder(s.pool.QueryRow(ctx, selectOrdersQuery+`WHERE x.id = $1;`, id))
+	if err != nil {
+		return nil, notFound(fmt.Errorf("pgxpool: scan: %w", err))
 	}
-
 	return o, nil
 }
 
+// getOrders returns orders with provided ids in the same order with one query.
+//
+// If any of orders does not exist then error wrapping store.ErrDoesNotExists and pgx.ErrNoRows will be returned.
 func (s *Store) getOrders(ctx context.Context, ids []int64) (res []*model.OrderDTO, err error) {
+	if len(ids) == 0 {
+		return []*model.OrderDTO{}, nil
+	}
+
+	var orders []*model.OrderDTO
+	if orders, err = s.queryOrders(ctx, `WHERE x.id = ANY ($1);`, ids); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int64]*model.OrderDTO, len(orders))
+	for _, o := range orders {
+		byID[o.OrderID] = o
+	}
 	res = make([]*model.OrderDTO, 0, len(ids))
-	var courier *model.OrderDTO
 	for _, id := range ids {
-		courier, err = s.GetOrderByID(ctx, id)
-		if err != nil {
-			return nil, err
+		o, ok := byID[id]
+		if !ok {
+			return nil, notFound(fmt.Errorf("order %d: %w", id, pgx.ErrNoRows))
 		}
-		res = append(res, courier)
+		res = append(res, o)
 	}
 	return res, nil
 }
 
-func (s *Store) GetOrders(ctx context.Context, limit int, offset int) (res []*model.OrderDTO, err error) {
-	const query = `SELECT x.id
-FROM orders x
-ORDER BY x.id
-OFFSET $1 ROWS FETCH NEXT $2 ROWS ONLY;`
-	var rows pgx.Rows
+// GetSubOrders returns orders which order with provided id was split into.
+func (s *Store) GetSubOrders(ctx context.Context, id int64) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
 
-	ids := make([]int64, 0, limit)
-
-	rows, err = s.pool.Query(ctx, query, offset, limit)
+	res, err = s.queryOrders(ctx, `WHERE x.parent_id = $1
+ORDER BY x.id;`, id)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, store.ErrNoContent
-		}
-		return nil, fmt.Errorf("unable to get users: %w", err)
+		return nil, fmt.Errorf("unable to get sub-orders: %w", err)
 	}
+	return res, nil
+}
 
-	for rows.Next() {
-		var id int64
-
-		if err = rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("unable to scan id: %w", err)
-		}
+func (s *Store) GetOrders(ctx context.Context, limit int, offset int) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
 
-		ids = append(ids, id)
+	res, err = s.queryOrders(ctx, `WHERE x.id IN (SELECT y.id FROM orders y ORDER BY y.id OFFSET $1 ROWS FETCH NEXT $2 ROWS ONLY)
+ORDER BY x.id;`, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get orders: %w", err)
 	}
+	return res, nil
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("err from rows.Err(): %w", err)
-	}
+// GetOrdersAfter returns at most limit orders with id greater than afterID ordered by id.
+func (s *Store) GetOrdersAfter(ctx context.Context, afterID int64, limit int) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
 
-	res, err = s.getOrders(ctx, ids)
+	res, err = s.queryOrders(ctx, `WHERE x.id IN (SELECT y.id FROM orders y WHERE y.id > $1 ORDER BY y.id FETCH NEXT $2 ROWS ONLY)
+ORDER BY x.id;`, afterID, limit)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to get orders: %w", err)
 	}
 	return res, nil
 }
 
-func (s *Store) addDeliveryHoursToOrder(ctx context.Context, tx pgx.Tx, dto *model.OrderDTO) (err error) {
-	for _, wh := range dto.DeliveryHours {
-		if _, err = tx.Exec(
-			ctx,
-			`INSERT INTO orders_delivery_hours(order_id, start_time, end_time, reversed) VALUES ($1, $2, $3, $4);`,
-			dto.OrderID,
-			int(wh.Start()),
-			int(wh.End()),
-			wh.Start() > wh.End(),
-		); err != nil {
-			return fmt.Errorf("err while adding working hours to courier: %w", err)
+func (s *Store) addDeliveryHoursToOrders(ctx context.Context, tx pgx.Tx, orders []*model.OrderDTO) error {
+	rows := make([][]any, 0, len(orders))
+	for _, order := range orders {
+		for _, wh := range order.DeliveryHours {
+			rows = append(rows, []any{order.OrderID, int32(wh.Start()), int32(wh.End()), wh.Start() > wh.End()})
 		}
 	}
+	if err := s.copyRows(
+		ctx,
+		tx,
+		"orders_delivery_hours",
+		[]string{"order_id", "start_time", "end_time", "reversed"},
+		rows,
+	); err != nil {
+		return fmt.Errorf("err while adding delivery hours to orders: %w", err)
+	}
 	return nil
 }
 
-func (s *Store) createOrder(ctx context.Context, tx pgx.Tx, order *model.OrderDTO) (err error) {
-	const query = `INSERT INTO orders(weight, regions, cost, completed)
-VALUES ($1, $2, $3, FALSE)
-RETURNING id;`
+// flattenOrders returns orders with their sub-orders, every parent going before its sub-orders.
+func flattenOrders(orders []*model.OrderDTO) []*model.OrderDTO {
+	res := make([]*model.OrderDTO, 0, len(orders))
+	for _, order := range orders {
+		res = append(res, order)
+		res = append(res, flattenOrders(order.SubOrders)...)
+	}
+	return res
+}
 
-	if err = tx.QueryRow(ctx, query, order.Weight, order.Regions, order.Cost).Scan(&order.OrderID); err != nil {
-		return fmt.Errorf("err while creating order: %w", err)
+// createOrders stores orders with their sub-orders and delivery hours using COPY and fills ids of created orders.
+func (s *Store) createOrders(ctx context.Context, tx pgx.Tx, orders []*model.OrderDTO) error {
+	all := flattenOrders(orders)
+	ids, err := s.reserveIDs(ctx, tx, "orders", len(all))
+	if err != nil {
+		return err
 	}
-	return s.addDeliveryHoursToOrder(ctx, tx, order)
+	for i, order := range all {
+		order.OrderID = ids[i]
+		for _, sub := range order.SubOrders {
+			sub.ParentOrderID = order.OrderID
+		}
+	}
+
+	rows := make([][]any, 0, len(all))
+	for _, order := range all {
+		var parent *int64
+		if order.ParentOrderID != 0 {
+			parent = &order.ParentOrderID
+		}
+		order.Status = model.OrderStatusCreated
+		rows = append(rows, []any{order.OrderID, order.Weight, order.Regions, order.Cost, false, parent, order.Status})
+	}
+	if err = s.copyRows(
+		ctx,
+		tx,
+		"orders",
+		[]string{"id", "weight", "regions", "cost", "completed", "parent_id", "status"},
+		rows,
+	); err != nil {
+		return fmt.Errorf("err while creating orders: %w", err)
+	}
+	if err = s.recordStatus(ctx, tx, ids, model.OrderStatusCreated, model.ActorAPI); err != nil {
+		return err
+	}
+	return s.addDeliveryHoursToOrders(ctx, tx, all)
 }
 
+// recordStatus records that orders with provided ids got status into history of order statuses.
+func (s *Store) recordStatus(ctx context.Context, tx pgx.Tx, ids []int64, status, actor string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO order_status_history(order_id, status, actor)
+SELECT unnest($1::BIGINT[]), $2::VARCHAR, $3::TEXT;`, ids, status, actor); err != nil {
+		return fmt.Errorf("err while recording order status: %w", err)
+	}
+	return nil
+}
+
+// CreateOrders stores all orders with their sub-orders in one transaction and fills ids of created orders.
 func (s *Store) CreateOrders(ctx context.Context, orders []*model.OrderDTO) (err error) {
+	defer classify(&err)
+
 	var tx pgx.Tx
 	tx, err = s.pool.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("check drivers: unable to begin tx: %w", err)
 	}
 
-	defer func() {
-		s.log.Error("tx rollback", zap.NamedError("tx_error", tx.Rollback(ctx)))
-	}()
+	defer s.rollback(ctx, tx)
 
+	if multierr.AppendInto(&err, s.createOrders(ctx, tx, orders)) {
+		return err
+	}
+	events := make([]any, 0, len(orders))
 	for _, order := range orders {
-		if multierr.AppendInto(&err, s.createOrder(ctx, tx, order)) {
-			return err
-		}
+		events = append(events, order)
+	}
+	if multierr.AppendInto(&err, s.publish(ctx, tx, model.EventOrderCreated, events...)) {
+		return err
 	}
 
 	if multierr.AppendInto(&err, tx.Commit(ctx)) {
@@ -169,6 +296,8 @@ func (s *Store) CreateOrders(ctx context.Context, orders []*model.OrderDTO) (err
 }
 
 func (s *Store) GetCompletedOrdersPriceByCourier(ctx context.Context, id int64, start time.Time, end time.Time) (sum, count int32, err error) {
+	defer classify(&err)
+
 	const query = `SELECT COALESCE(SUM(x.cost), 0), COALESCE(COUNT(x.cost), 0)
 FROM orders x
 WHERE x.completed
@@ -182,37 +311,91 @@ WHERE x.completed
 	return
 }
 
+// completeOrder completes order which is assigned to courier or is delivered by courier.
+//
+// Completing of already completed order changes nothing.
 func (s *Store) completeOrder(ctx context.Context, tx pgx.Tx, order *model.CompleteOrder) error {
-	var orderAssigned, orderCompleted bool
-	const query = `SELECT EXISTS(
-    SELECT * FROM orders WHERE courier = $1 AND id = $2
-), EXISTS(
-    SELECT * FROM orders x WHERE x.id = $2 AND completed = false
-);`
-	if err := tx.QueryRow(ctx, query, order.CourierID, order.OrderID).Scan(&orderAssigned, &orderCompleted); err != nil {
+	var (
+		status        string
+		courier       *int64
+		completedTime *time.Time
+		region        int32
+	)
+	if err := tx.QueryRow(
+		ctx,
+		`SELECT x.status, x.courier, x.completed_time, x.regions FROM orders x WHERE x.id = $1 FOR UPDATE;`,
+		order.OrderID,
+	).Scan(&status, &courier, &completedTime, &region); err != nil {
+		return notFound(fmt.Errorf("order %d: %w", order.OrderID, err))
+	}
+	if courier == nil || *courier != order.CourierID {
+		return fmt.Errorf("order %d of courier %d: %w", order.OrderID, order.CourierID, store.ErrDoesNotExists)
+	}
+	if status == model.OrderStatusCompleted && completedTime != nil {
+		order.CompleteTime = datetime.Time(*completedTime)
+		return nil
+	}
+	if !model.CanChangeOrderStatus(status, model.OrderStatusCompleted) {
+		return fmt.Errorf("order %d is %s: %w", order.OrderID, status, store.ErrStatusChanged)
+	}
+
+	const updateQuery = `UPDATE orders SET completed_time = $1, completed = TRUE, status = 'COMPLETED' WHERE id = $2;`
+	if _, err := tx.Exec(ctx, updateQuery, order.CompleteTime, order.OrderID); err != nil {
 		return err
 	}
-	if !orderAssigned {
-		return store.ErrDoesNotExists
+	if err := s.recordStatus(ctx, tx, []int64{order.OrderID}, model.OrderStatusCompleted, model.CourierActor(order.CourierID)); err != nil {
+		return err
+	}
+	if err := s.publish(ctx, tx, model.EventOrderCompleted, &model.OrderCompletedEvent{
+		CourierID:    order.CourierID,
+		OrderID:      order.OrderID,
+		CompleteTime: order.CompleteTime,
+		Region:       region,
+	}); err != nil {
+		return err
+	}
+	return s.completeParent(ctx, tx, order.OrderID, order.CourierID)
+}
+
+// completeParent completes parent of sub-order with provided id by courier if all of its sub-orders are completed.
+//
+// Completion time of parent is time when the last sub-order was completed. Parent is never assigned and its status can
+// not be changed directly, so it is completed right from CREATED status, see model.CanCompleteParent.
+func (s *Store) completeParent(ctx context.Context, tx pgx.Tx, id int64, courier int64) error {
+	const query = `UPDATE orders p
+SET completed      = TRUE,
+    status         = 'COMPLETED',
+    completed_time = (SELECT max(c.completed_time) FROM orders c WHERE c.parent_id = p.id)
+WHERE p.id = (SELECT x.parent_id FROM orders x WHERE x.id = $1)
+  AND NOT p.completed
+  AND p.status = 'CREATED'
+  AND NOT EXISTS(SELECT * FROM orders c WHERE c.parent_id = p.id AND NOT c.completed)
+RETURNING p.id, p.completed_time, p.regions;`
+	var completedTime time.Time
+	parent := &model.OrderCompletedEvent{CourierID: courier}
+	if err := tx.QueryRow(ctx, query, id).Scan(&parent.OrderID, &completedTime, &parent.Region); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("err while completing parent order: %w", err)
 	}
-	if orderCompleted {
-		return tx.QueryRow(ctx, `select completed_time from orders where id = $1;`, order.OrderID).Scan(&order.CompleteTime)
+	parent.CompleteTime = datetime.Time(completedTime)
+	if err := s.recordStatus(ctx, tx, []int64{parent.OrderID}, model.OrderStatusCompleted, model.CourierActor(courier)); err != nil {
+		return err
 	}
-	const updateQuery = `update orders set completed_time = $1, completed = TRUE where id = $2;`
-	_, err := tx.Exec(ctx, updateQuery, order.CompleteTime, order.OrderID)
-	return err
+	return s.publish(ctx, tx, model.EventOrderCompleted, parent)
 }
 
 func (s *Store) CompleteOrders(ctx context.Context, info []model.CompleteOrder) (err error) {
+	defer classify(&err)
+
 	var tx pgx.Tx
 	tx, err = s.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		s.log.Error("rollback", zap.NamedError("tx_error", tx.Rollback(ctx)))
-	}()
+	defer s.rollback(ctx, tx)
 
 	for _, o := range info {
 		if err = s.completeOrder(ctx, tx, &o); err != nil {
@@ -222,30 +405,219 @@ func (s *Store) CompleteOrders(ctx context.Context, info []model.CompleteOrder)
 	return tx.Commit(ctx)
 }
 
-func (s *Store) GetOrdersByIDs(ctx context.Context, ids []int64) (res []*model.OrderDTO, err error) {
-	const query = `SELECT x.weight, x.regions, x.cost, coalesce(x.completed_time, '1000-01-01'::timestamp), x.completed
+// CancelOrders cancels orders with their sub-orders in one transaction and takes them back from couriers.
+//
+// Cancelling of already cancelled order changes nothing. If any order can not be cancelled from its status then
+// store.ErrStatusChanged is returned and none of orders is cancelled.
+func (s *Store) CancelOrders(ctx context.Context, ids []int64, reason, actor string) (err error) {
+	defer classify(&err)
+
+	var tx pgx.Tx
+	tx, err = s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin tx: %w", err)
+	}
+
+	defer s.rollback(ctx, tx)
+
+	for _, id := range ids {
+		if err = s.cancelOrder(ctx, tx, id, reason, actor); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// cancelOrder cancels order with its sub-orders, takes them from groups of couriers and deletes emptied groups.
+func (s *Store) cancelOrder(ctx context.Context, tx pgx.Tx, id int64, reason, actor string) error {
+	rows, err := tx.Query(ctx, `SELECT x.id, x.status, x.group_id, x.courier, x.regions
 FROM orders x
-WHERE x.id = $1;`
-	res = make([]*model.OrderDTO, 0, len(ids))
+WHERE x.id = $1
+   OR x.parent_id = $1
+ORDER BY x.id
+    FOR UPDATE;`, id)
+	if err != nil {
+		return fmt.Errorf("err while getting order: %w", err)
+	}
+	type row struct {
+		id      int64
+		status  string
+		group   *int64
+		courier *int64
+		region  int32
+	}
+	orders, err := pgx.CollectRows(rows, func(r pgx.CollectableRow) (res row, err error) {
+		err = r.Scan(&res.id, &res.status, &res.group, &res.courier, &res.region)
+		return res, err
+	})
+	if err != nil {
+		return fmt.Errorf("err while getting order: %w", err)
+	}
+
 	var (
-		t     time.Time
-		ok    bool
-		order *model.OrderDTO
+		found  bool
+		cancel []int64
+		groups []int64
+		events []any
 	)
-	for _, id := range ids {
-		order = new(model.OrderDTO)
-		if err = s.pool.QueryRow(ctx, query, id).Scan(&order.Weight, &order.Regions, &order.Cost, &t, &ok); err != nil {
-			return nil, err
+	for _, o := range orders {
+		found = found || o.id == id
+		if o.status == model.OrderStatusCancelled {
+			continue
 		}
-		if ok {
-			order.CompletedTime = datetime.Time(t)
+		if !model.CanChangeOrderStatus(o.status, model.OrderStatusCancelled) {
+			return fmt.Errorf("order %d is %s: %w", o.id, o.status, store.ErrStatusChanged)
 		}
-		order.OrderID = id
+		cancel = append(cancel, o.id)
+		e := &model.OrderCancelledEvent{OrderID: o.id, Region: o.region, Reason: reason}
+		if o.courier != nil {
+			e.CourierID = *o.courier
+		}
+		events = append(events, e)
+		if o.group != nil {
+			groups = append(groups, *o.group)
+		}
+	}
+	if !found {
+		return fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
+	}
+	if len(cancel) == 0 {
+		return nil
+	}
 
-		if order.DeliveryHours, err = s.getDeliveryHoursOfOrder(ctx, id); err != nil {
-			return nil, err
+	if _, err = tx.Exec(ctx, `UPDATE orders
+SET status        = 'CANCELLED',
+    cancel_reason = $2,
+    courier       = NULL,
+    group_id      = NULL,
+    delivery_time = NULL
+WHERE id = ANY ($1::BIGINT[]);`, cancel, reason); err != nil {
+		return fmt.Errorf("err while cancelling orders: %w", err)
+	}
+	if _, err = tx.Exec(ctx, `DELETE
+FROM order_group g
+WHERE g.id = ANY ($1::BIGINT[])
+  AND NOT EXISTS(SELECT * FROM orders o WHERE o.group_id = g.id);`, groups); err != nil {
+		return fmt.Errorf("err while deleting groups: %w", err)
+	}
+	if err = s.recordStatus(ctx, tx, cancel, model.OrderStatusCancelled, actor); err != nil {
+		return err
+	}
+	return s.publish(ctx, tx, model.EventOrderCancelled, events...)
+}
+
+// GetOrdersByIDs returns orders with provided ids in the same order.
+func (s *Store) GetOrdersByIDs(ctx context.Context, ids []int64) (res []*model.OrderDTO, err error) {
+	defer classify(&err)
+
+	return s.getOrders(ctx, ids)
+}
+
+// minuteFromNullable converts nullable column with minutes into time.
+func minuteFromNullable(m *int32) *datetime.Minute {
+	if m == nil {
+		return nil
+	}
+	res := datetime.Minute(*m)
+	return &res
+}
+
+// ChangeOrderStatus changes status of order from one status to another and records change in history.
+//
+// It only changes status, so it must not be used to complete orders. If order has status other than from or is split
+// into sub-orders then store.ErrStatusChanged is returned. If courier is not zero then order must be assigned to
+// courier, otherwise store.ErrDoesNotExists is returned.
+func (s *Store) ChangeOrderStatus(ctx context.Context, id, courier int64, from, to, actor string) (err error) {
+	defer classify(&err)
+
+	var tx pgx.Tx
+	tx, err = s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin tx: %w", err)
+	}
+
+	defer s.rollback(ctx, tx)
+
+	var (
+		status   string
+		assignee *int64
+		split    bool
+	)
+	if err = tx.QueryRow(
+		ctx,
+		`SELECT x.status, x.courier, EXISTS(SELECT * FROM orders c WHERE c.parent_id = x.id)
+FROM orders x
+WHERE x.id = $1
+    FOR UPDATE;`,
+		id,
+	).Scan(&status, &assignee, &split); err != nil {
+		return notFound(fmt.Errorf("order %d: %w", id, err))
+	}
+	if courier != 0 && (assignee == nil || *assignee != courier) {
+		return fmt.Errorf("order %d of courier %d: %w", id, courier, store.ErrDoesNotExists)
+	}
+	if split {
+		return fmt.Errorf("order %d is split into sub-orders: %w", id, store.ErrStatusChanged)
+	}
+	if status != from {
+		return fmt.Errorf("order %d is not %s: %w", id, from, store.ErrStatusChanged)
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE orders SET status = $2 WHERE id = $1;`, id, to); err != nil {
+		return fmt.Errorf("err while changing order status: %w", err)
+	}
+	if err = s.recordStatus(ctx, tx, []int64{id}, to, actor); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// GetOrderHistory returns changes of order status from the oldest one to the newest one.
+func (s *Store) GetOrderHistory(ctx context.Context, id int64) (res []model.OrderStatusChange, err error) {
+	defer classify(&err)
+
+	var rows pgx.Rows
+	rows, err = s.pool.Query(ctx, `SELECT h.status, h.actor, h.changed_at
+FROM order_status_history h
+WHERE h.order_id = $1
+ORDER BY h.id;`, id)
+	if err != nil {
+		return nil, fmt.Errorf("err while doing query: %w", err)
+	}
+	defer rows.Close()
+
+	res = make([]model.OrderStatusChange, 0)
+	for rows.Next() {
+		var (
+			change    model.OrderStatusChange
+			changedAt time.Time
+		)
+		if err = rows.Scan(&change.Status, &change.Actor, &changedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning from rows: %w", err)
+		}
+		change.ChangedAt = datetime.Time(changedAt)
+		res = append(res, change)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error from rows.Err() => %w", err)
+	}
+
+	if len(res) == 0 {
+		var exists bool
+		if err = s.pool.QueryRow(ctx, `SELECT EXISTS(SELECT * FROM orders x WHERE x.id = $1);`, id).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("err while checking order: %w", err)
+		}
+		if !exists {
+			return nil, fmt.Errorf("order %d: %w", id, store.ErrDoesNotExists)
 		}
-		res = append(res, order)
 	}
 	return res, nil
 }
@@ -228,3 +228,168 @@ func TestStore_CompleteOrders_Positive(t *testing.T) {
 	err := s.CompleteOrders(ctx, []model.CompleteOrder{{rand.Int63(), rand.Int63(), datetime.Time{}}})
 	assert.NoError(t, err)
 }
+
+func TestStore_CreateOrders_SubOrders(t *testing.T) {
+	ctx := context.Background()
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	hours := []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 1200}.TimeInterval()}
+	couriers, err := s.CreateCouriers(ctx, []model.CreateCourierDTO{
+		{CourierType: model.AutoCourierTypeString, Regions: []int32{1}, WorkingHours: hours},
+	})
+	require.NoError(t, err)
+
+	parent := &model.OrderDTO{
+		Weight:        60,
+		Regions:       1,
+		DeliveryHours: hours,
+		Cost:          100,
+		SubOrders: []*model.OrderDTO{
+			{Weight: 30, Regions: 1, DeliveryHours: hours, Cost: 50},
+			{Weight: 30, Regions: 1, DeliveryHours: hours, Cost: 50},
+		},
+	}
+	require.NoError(t, s.CreateOrders(ctx, []*model.OrderDTO{parent}))
+
+	subs, err := s.GetSubOrders(ctx, parent.OrderID)
+	require.NoError(t, err)
+	if assert.Len(t, subs, 2) {
+		for i, sub := range subs {
+			assert.Equal(t, parent.SubOrders[i].OrderID, sub.OrderID)
+			assert.Equal(t, parent.OrderID, sub.ParentOrderID)
+		}
+	}
+
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	var ids []int64
+	for _, o := range unassigned {
+		ids = append(ids, o.OrderID)
+	}
+	assert.Equal(t, []int64{parent.SubOrders[0].OrderID, parent.SubOrders[1].OrderID}, ids)
+
+	require.NoError(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{
+		Date: "2023-01-01",
+		Couriers: []model.CourierGroupOrders{{
+			CourierID: couriers[0].CourierID,
+			Orders: []model.GroupOrders{{
+				Orders: []model.OrderDTO{*parent.SubOrders[0], *parent.SubOrders[1]},
+			}},
+		}},
+	}))
+
+	first := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: couriers[0].CourierID, OrderID: parent.SubOrders[0].OrderID, CompleteTime: datetime.Time(first)},
+	}))
+	got, err := s.GetOrderByID(ctx, parent.OrderID)
+	require.NoError(t, err)
+	assert.True(t, time.Time(got.CompletedTime).IsZero())
+
+	last := first.Add(time.Hour)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: couriers[0].CourierID, OrderID: parent.SubOrders[1].OrderID, CompleteTime: datetime.Time(last)},
+	}))
+	got, err = s.GetOrderByID(ctx, parent.OrderID)
+	require.NoError(t, err)
+	assert.True(t, last.Equal(time.Time(got.CompletedTime)))
+}
+
+func TestStore_GetSubOrders_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	res, err := s.GetSubOrders(context.Background(), 1)
+	assert.Nil(t, res)
+	assert.Error(t, err)
+}
+
+func TestFlattenOrders(t *testing.T) {
+	sub := []*model.OrderDTO{{Weight: 30}, {Weight: 30}}
+	orders := []*model.OrderDTO{{Weight: 1}, {Weight: 60, SubOrders: sub}, {Weight: 2}}
+	assert.Equal(t, []*model.OrderDTO{orders[0], orders[1], sub[0], sub[1], orders[2]}, flattenOrders(orders))
+	assert.Empty(t, flattenOrders(nil))
+}
+
+func TestStore_CreateOrders_Bulk(t *testing.T) {
+	ctx := context.Background()
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	orders := make([]*model.OrderDTO, 0, 100)
+	for i := 0; i < cap(orders); i++ {
+		orders = append(orders, &model.OrderDTO{
+			Weight:        float64(i%10 + 1),
+			Regions:       int32(i%5 + 1),
+			DeliveryHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: int32(i), End: int32(i + 60)}.TimeInterval()},
+			Cost:          int32(i + 1),
+		})
+	}
+	require.NoError(t, s.CreateOrders(ctx, orders))
+
+	ids := make([]int64, 0, len(orders))
+	for i, o := range orders {
+		if i > 0 {
+			assert.Greater(t, o.OrderID, orders[i-1].OrderID)
+		}
+		ids = append(ids, o.OrderID)
+	}
+	got, err := s.GetOrdersByIDs(ctx, ids)
+	require.NoError(t, err)
+	for i, o := range got {
+		assert.Equal(t, orders[i].Cost, o.Cost)
+		assert.Equal(t, orders[i].DeliveryHours, o.DeliveryHours)
+	}
+}
+
+func TestStore_CreateOrders_Negative_Atomic(t *testing.T) {
+	ctx := context.Background()
+	cli, td := client.NewTest(t)
+	defer td()
+
+	s, err := New(cli)
+	require.NoError(t, err)
+
+	err = s.CreateOrders(ctx, []*model.OrderDTO{
+		{Weight: 1, Regions: 1, Cost: 1},
+		{Weight: 1, Regions: 1, Cost: 0},
+	})
+	assert.Error(t, err)
+
+	got, err := s.GetOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestStore_CreateOrders_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.Error(t, s.CreateOrders(context.Background(), []*model.OrderDTO{{Weight: 1, Regions: 1, Cost: 1}}))
+}
+
+func TestStore_ChangeOrderStatus_Negative_BadCli(t *testing.T) {
+	cli := client.BadCli(t)
+	s, err := New(cli)
+	require.NoError(t, err)
+	assert.Error(t, s.ChangeOrderStatus(context.Background(), 1, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI))
+}
+
+func TestStore_GetOrderHistory_Negative_BadCli(t *testing.T) {
+	cli := client.BadCli(t)
+	s, err := New(cli)
+	require.NoError(t, err)
+	resp, err := s.GetOrderHistory(context.Background(), 1)
+	assert.Nil(t, resp)
+	assert.Error(t, err)
+}
+
+func TestStore_CancelOrders_Negative_BadCli(t *testing.T) {
+	cli := client.BadCli(t)
+	s, err := New(cli)
+	require.NoError(t, err)
+	assert.Error(t, s.CancelOrders(context.Background(), []int64{1}, "withdrew", model.ActorAPI))
+}
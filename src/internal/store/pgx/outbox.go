@@ -0,0 +1,131 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"time"
+)
+
+// publish writes events of type with provided payloads into outbox in transaction of change which caused them.
+//
+// Every event is also scheduled for delivery to active webhooks which are subscribed to its type.
+func (s *Store) publish(ctx context.Context, tx pgx.Tx, typ string, payloads ...any) error {
+	ids, err := s.reserveIDs(ctx, tx, "outbox", len(payloads))
+	if err != nil {
+		return err
+	}
+	rows := make([][]any, 0, len(payloads))
+	for i, p := range payloads {
+		e, err := model.NewEvent(typ, p)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []any{ids[i], e.Type, []byte(e.Payload)})
+	}
+	if err = s.copyRows(ctx, tx, "outbox", []string{"id", "type", "payload"}, rows); err != nil {
+		return fmt.Errorf("err while publishing events: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err = tx.Exec(ctx, `INSERT INTO webhook_deliveries(webhook_id, event_id)
+SELECT w.id, e.id
+FROM outbox e
+         JOIN webhooks w ON e.type = ANY (w.event_types)
+WHERE e.id = ANY ($1::BIGINT[])
+  AND w.active
+ORDER BY e.id, w.id;`, ids); err != nil {
+		return fmt.Errorf("err while scheduling webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// scanEvent scans event selected as id, type, payload and creation time.
+func scanEvent(row pgx.CollectableRow) (model.Event, error) {
+	var (
+		e         model.Event
+		payload   []byte
+		createdAt time.Time
+	)
+	if err := row.Scan(&e.ID, &e.Type, &payload, &createdAt); err != nil {
+		return e, err
+	}
+	e.Payload = payload
+	e.CreatedAt = datetime.Time(createdAt)
+	return e, nil
+}
+
+// GetPendingEvents returns at most limit events which are not delivered yet ordered by id.
+func (s *Store) GetPendingEvents(ctx context.Context, limit int) (res []model.Event, err error) {
+	defer classify(&err)
+
+	const query = `SELECT x.id, x.type, x.payload, x.created_at
+FROM outbox x
+WHERE x.delivered_at IS NULL
+ORDER BY x.id
+FETCH NEXT $1 ROWS ONLY;`
+
+	rows, err := s.pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get pending events: %w", err)
+	}
+	res, err = pgx.CollectRows(rows, scanEvent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan events: %w", err)
+	}
+	return res, nil
+}
+
+// GetEventsAfter returns at most limit events with id greater than afterID ordered by id whether they are delivered
+// or not.
+func (s *Store) GetEventsAfter(ctx context.Context, afterID int64, limit int) (res []model.Event, err error) {
+	defer classify(&err)
+
+	const query = `SELECT x.id, x.type, x.payload, x.created_at
+FROM outbox x
+WHERE x.id > $1
+ORDER BY x.id
+FETCH NEXT $2 ROWS ONLY;`
+
+	rows, err := s.pool.Query(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get events: %w", err)
+	}
+	res, err = pgx.CollectRows(rows, scanEvent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan events: %w", err)
+	}
+	return res, nil
+}
+
+// GetLastEventID returns id of the last event of outbox or zero if outbox is empty.
+func (s *Store) GetLastEventID(ctx context.Context) (id int64, err error) {
+	defer classify(&err)
+
+	if err = s.pool.QueryRow(ctx, `SELECT coalesce(max(x.id), 0) FROM outbox x;`).Scan(&id); err != nil {
+		return 0, fmt.Errorf("unable to get last event id: %w", err)
+	}
+	return id, nil
+}
+
+// MarkEventsDelivered marks events with provided ids as delivered, so they are not returned as pending anymore.
+//
+// Marking of already delivered event changes nothing.
+func (s *Store) MarkEventsDelivered(ctx context.Context, ids []int64) (err error) {
+	defer classify(&err)
+
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err = s.pool.Exec(
+		ctx,
+		`UPDATE outbox SET delivered_at = now() WHERE id = ANY($1) AND delivered_at IS NULL;`,
+		ids,
+	); err != nil {
+		return fmt.Errorf("unable to mark events delivered: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package pgx
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"testing"
+)
+
+func TestStore_GetPendingEvents_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	events, err := s.GetPendingEvents(context.Background(), 10)
+	assert.Error(t, err)
+	assert.Nil(t, events)
+}
+
+func TestStore_MarkEventsDelivered_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.Error(t, s.MarkEventsDelivered(context.Background(), []int64{1}))
+	assert.NoError(t, s.MarkEventsDelivered(context.Background(), nil))
+}
+
+func TestStore_GetEventsAfter_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	events, err := s.GetEventsAfter(context.Background(), 0, 10)
+	assert.Error(t, err)
+	assert.Nil(t, events)
+}
+
+func TestStore_GetLastEventID_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	id, err := s.GetLastEventID(context.Background())
+	assert.Error(t, err)
+	assert.Zero(t, id)
+}
@@ -1,11 +1,18 @@
 package pgx
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"github.com/jackc/pgerrcode"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx"
 	"go.uber.org/zap"
+	"net"
 )
 
 var (
@@ -29,3 +36,62 @@ func New(cli pgx.Client) (*Store, error) {
 		pool: cli.P(),
 	}, nil
 }
+
+// notFound marks error wrapping pgx.ErrNoRows as store.ErrDoesNotExists keeping original error in chain.
+func notFound(err error) error {
+	if errors.Is(err, pgxv5.ErrNoRows) {
+		return fmt.Errorf("%w: %w", store.ErrDoesNotExists, err)
+	}
+	return err
+}
+
+// rollback rolls back tx and logs failure of rollback.
+//
+// It is deferred right after tx begins, so it is called after commit too: pgx.ErrTxClosed is returned then and is not
+// logged.
+func (s *Store) rollback(ctx context.Context, tx pgxv5.Tx) {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgxv5.ErrTxClosed) {
+		s.log.Error("tx rollback", zap.NamedError("tx_error", err))
+	}
+}
+
+// classify replaces error which is caused by postgres with typed store error.
+//
+// It must be deferred by exported methods, so callers can tell failures of database apart from bad requests.
+func classify(err *error) {
+	if *err == nil {
+		return
+	}
+	var e *store.Error
+	if errors.As(*err, &e) {
+		return
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(*err, &pgErr) {
+		*err = &store.Error{Kind: kind(pgErr.Code), Constraint: pgErr.ConstraintName, Err: *err}
+		return
+	}
+	var netErr net.Error
+	if errors.As(*err, &netErr) || pgconn.Timeout(*err) || pgconn.SafeToRetry(*err) {
+		*err = &store.Error{Kind: store.ErrUnavailable, Err: *err}
+	}
+}
+
+// kind returns kind of store error by SQLSTATE code of postgres error.
+func kind(code string) error {
+	switch {
+	case code == pgerrcode.UniqueViolation:
+		return store.ErrUniqueViolation
+	case code == pgerrcode.ForeignKeyViolation:
+		return store.ErrForeignKeyViolation
+	case code == pgerrcode.CheckViolation, code == pgerrcode.NotNullViolation, pgerrcode.IsDataException(code):
+		return store.ErrCheckViolation
+	case code == pgerrcode.SerializationFailure, code == pgerrcode.DeadlockDetected:
+		return store.ErrSerialization
+	case pgerrcode.IsConnectionException(code),
+		pgerrcode.IsInsufficientResources(code),
+		pgerrcode.IsOperatorIntervention(code):
+		return store.ErrUnavailable
+	}
+	return store.ErrInternal
+}
@@ -1,8 +1,18 @@
 package pgx
 
 import (
+	"errors"
+	"fmt"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/storetest"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"net"
 	"testing"
 )
 
@@ -24,3 +34,82 @@ func TestNew_Negative(t *testing.T) {
 	}
 	assert.Nil(t, s)
 }
+
+func TestNotFound(t *testing.T) {
+	err := notFound(fmt.Errorf("scan: %w", pgx.ErrNoRows))
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	other := errors.New("other")
+	assert.Equal(t, other, notFound(other))
+	assert.NoError(t, notFound(nil))
+}
+
+func TestKind(t *testing.T) {
+	tt := []struct {
+		code string
+		want error
+	}{
+		{pgerrcode.UniqueViolation, store.ErrUniqueViolation},
+		{pgerrcode.ForeignKeyViolation, store.ErrForeignKeyViolation},
+		{pgerrcode.CheckViolation, store.ErrCheckViolation},
+		{pgerrcode.NotNullViolation, store.ErrCheckViolation},
+		{pgerrcode.InvalidRowCountInLimitClause, store.ErrCheckViolation},
+		{pgerrcode.SerializationFailure, store.ErrSerialization},
+		{pgerrcode.DeadlockDetected, store.ErrSerialization},
+		{pgerrcode.ConnectionFailure, store.ErrUnavailable},
+		{pgerrcode.TooManyConnections, store.ErrUnavailable},
+		{pgerrcode.AdminShutdown, store.ErrUnavailable},
+		{pgerrcode.UndefinedTable, store.ErrInternal},
+	}
+	for _, tc := range tt {
+		t.Run(tc.code, func(t *testing.T) {
+			assert.Equal(t, tc.want, kind(tc.code))
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		var err error
+		classify(&err)
+		assert.NoError(t, err)
+	})
+	t.Run("postgres error", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: "couriers_pkey"}
+		err := fmt.Errorf("insert: %w", pgErr)
+		classify(&err)
+		assert.ErrorIs(t, err, store.ErrUniqueViolation)
+		assert.ErrorIs(t, err, pgErr)
+		assert.Equal(t, "couriers_pkey", store.Constraint(err))
+	})
+	t.Run("network error", func(t *testing.T) {
+		var err error = &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		classify(&err)
+		assert.ErrorIs(t, err, store.ErrUnavailable)
+	})
+	t.Run("classified", func(t *testing.T) {
+		want := &store.Error{Kind: store.ErrSerialization, Err: &pgconn.PgError{Code: pgerrcode.UniqueViolation}}
+		var err error = fmt.Errorf("fn: %w", want)
+		classify(&err)
+		assert.NotErrorIs(t, err, store.ErrUniqueViolation)
+		assert.ErrorIs(t, err, store.ErrSerialization)
+	})
+	t.Run("other", func(t *testing.T) {
+		for _, want := range []error{store.ErrDoesNotExists, store.ErrAlreadyAssigned, errors.New("other")} {
+			err := want
+			classify(&err)
+			assert.Equal(t, want, err)
+		}
+	})
+}
+
+func TestStore_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) production.Store {
+		cli, td := client.NewTest(t)
+		t.Cleanup(td)
+		s, err := New(cli)
+		require.NoError(t, err)
+		return s
+	})
+}
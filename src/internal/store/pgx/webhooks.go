@@ -0,0 +1,275 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"time"
+)
+
+// deliveryColumns are columns of delivery which scanDelivery expects, table of deliveries must be aliased as d.
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, e.type, d.status, d.attempts, d.last_status_code, d.last_error,
+       d.next_attempt_at, d.delivered_at, d.created_at`
+
+// scanDelivery scans delivery selected with deliveryColumns followed by dest.
+func scanDelivery(row pgx.Row, dest ...any) (*model.WebhookDelivery, error) {
+	var (
+		d           model.WebhookDelivery
+		statusCode  *int32
+		lastError   *string
+		nextAttempt time.Time
+		deliveredAt *time.Time
+		createdAt   time.Time
+	)
+	if err := row.Scan(append([]any{
+		&d.DeliveryID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.EventType,
+		&d.Status,
+		&d.Attempts,
+		&statusCode,
+		&lastError,
+		&nextAttempt,
+		&deliveredAt,
+		&createdAt,
+	}, dest...)...); err != nil {
+		return nil, err
+	}
+	if statusCode != nil {
+		d.LastStatusCode = *statusCode
+	}
+	if lastError != nil {
+		d.LastError = *lastError
+	}
+	if d.Status == model.DeliveryStatusPending {
+		t := datetime.Time(nextAttempt)
+		d.NextAttemptAt = &t
+	}
+	if deliveredAt != nil {
+		t := datetime.Time(*deliveredAt)
+		d.DeliveredAt = &t
+	}
+	d.CreatedAt = datetime.Time(createdAt)
+	return &d, nil
+}
+
+// CreateWebhook stores subscription of webhook and fills its id and creation time.
+func (s *Store) CreateWebhook(ctx context.Context, w *model.WebhookDTO) (err error) {
+	defer classify(&err)
+
+	if w == nil {
+		return ErrNilReference
+	}
+	var createdAt time.Time
+	if err = s.pool.QueryRow(
+		ctx,
+		`INSERT INTO webhooks(url, event_types, secret) VALUES ($1, $2, $3) RETURNING id, created_at;`,
+		w.URL,
+		w.EventTypes,
+		w.Secret,
+	).Scan(&w.WebhookID, &createdAt); err != nil {
+		return fmt.Errorf("unable to create webhook: %w", err)
+	}
+	w.CreatedAt = datetime.Time(createdAt)
+	return nil
+}
+
+// GetWebhooks returns all active webhooks with their secrets ordered by id.
+func (s *Store) GetWebhooks(ctx context.Context) (res []model.WebhookDTO, err error) {
+	defer classify(&err)
+
+	rows, err := s.pool.Query(ctx, `SELECT w.id, w.url, w.event_types, w.secret, w.created_at
+FROM webhooks w
+WHERE w.active
+ORDER BY w.id;`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get webhooks: %w", err)
+	}
+	res, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (w model.WebhookDTO, err error) {
+		var createdAt time.Time
+		err = row.Scan(&w.WebhookID, &w.URL, &w.EventTypes, &w.Secret, &createdAt)
+		w.CreatedAt = datetime.Time(createdAt)
+		return w, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan webhooks: %w", err)
+	}
+	return res, nil
+}
+
+// DeleteWebhook deactivates webhook, so it gets no new events, and fails its pending deliveries.
+//
+// Deliveries of deleted webhook stay in storage. If webhook does not exist or is already deleted then
+// store.ErrDoesNotExists is returned.
+func (s *Store) DeleteWebhook(ctx context.Context, id int64) (err error) {
+	defer classify(&err)
+
+	var tx pgx.Tx
+	tx, err = s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to begin tx: %w", err)
+	}
+
+	defer s.rollback(ctx, tx)
+
+	tag, err := tx.Exec(ctx, `UPDATE webhooks SET active = FALSE WHERE id = $1 AND active;`, id)
+	if err != nil {
+		return fmt.Errorf("unable to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("webhook %d: %w", id, store.ErrDoesNotExists)
+	}
+	if _, err = tx.Exec(ctx, `UPDATE webhook_deliveries
+SET status     = 'FAILED',
+    last_error = 'webhook deleted'
+WHERE webhook_id = $1
+  AND status = 'PENDING';`, id); err != nil {
+		return fmt.Errorf("unable to fail pending deliveries: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveries returns page of deliveries of active webhook from the newest one.
+//
+// If webhook does not exist or is deleted then store.ErrDoesNotExists is returned.
+func (s *Store) GetWebhookDeliveries(ctx context.Context, id int64, limit, offset int) (res []model.WebhookDelivery, err error) {
+	defer classify(&err)
+
+	var active bool
+	if err = s.pool.QueryRow(ctx, `SELECT w.active FROM webhooks w WHERE w.id = $1;`, id).Scan(&active); err != nil {
+		return nil, notFound(fmt.Errorf("webhook %d: %w", id, err))
+	}
+	if !active {
+		return nil, fmt.Errorf("webhook %d: %w", id, store.ErrDoesNotExists)
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT `+deliveryColumns+`
+FROM webhook_deliveries d
+         JOIN outbox e ON e.id = d.event_id
+WHERE d.webhook_id = $1
+ORDER BY d.id DESC
+OFFSET $2 ROWS FETCH NEXT $3 ROWS ONLY;`, id, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get deliveries: %w", err)
+	}
+	res, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.WebhookDelivery, error) {
+		d, err := scanDelivery(row)
+		if err != nil {
+			return model.WebhookDelivery{}, err
+		}
+		return *d, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan deliveries: %w", err)
+	}
+	return res, nil
+}
+
+// ReplayWebhookDelivery schedules event of delivery to be delivered to its webhook again and returns new delivery.
+//
+// Original delivery is kept with its attempts. If delivery of active webhook with provided ids does not exist then
+// store.ErrDoesNotExists is returned.
+func (s *Store) ReplayWebhookDelivery(ctx context.Context, webhookID, deliveryID int64) (d *model.WebhookDelivery, err error) {
+	defer classify(&err)
+
+	d, err = scanDelivery(s.pool.QueryRow(ctx, `WITH d AS (
+    INSERT INTO webhook_deliveries (webhook_id, event_id)
+        SELECT x.webhook_id, x.event_id
+        FROM webhook_deliveries x
+                 JOIN webhooks w ON w.id = x.webhook_id
+        WHERE x.id = $2
+          AND x.webhook_id = $1
+          AND w.active
+        RETURNING *)
+SELECT `+deliveryColumns+`
+FROM d
+         JOIN outbox e ON e.id = d.event_id;`, webhookID, deliveryID))
+	if err != nil {
+		return nil, notFound(fmt.Errorf("delivery %d of webhook %d: %w", deliveryID, webhookID, err))
+	}
+	return d, nil
+}
+
+// ClaimWebhookDeliveries returns at most limit pending deliveries which are due and postpones them for lease.
+//
+// Claimed deliveries are not returned again until lease expires, so dispatcher which fails to record attempt does not
+// lose delivery and concurrent dispatchers do not post the same delivery at once.
+func (s *Store) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) (res []model.WebhookTask, err error) {
+	defer classify(&err)
+
+	rows, err := s.pool.Query(ctx, `WITH d AS (
+    UPDATE webhook_deliveries x
+        SET next_attempt_at = now() + make_interval(secs => $2::FLOAT8)
+        WHERE x.id IN (SELECT y.id
+                       FROM webhook_deliveries y
+                       WHERE y.status = 'PENDING'
+                         AND y.next_attempt_at <= now()
+                       ORDER BY y.next_attempt_at, y.id
+                       FETCH NEXT $1 ROWS ONLY FOR UPDATE SKIP LOCKED)
+        RETURNING *)
+SELECT `+deliveryColumns+`, w.url, w.secret, e.payload, e.created_at
+FROM d
+         JOIN webhooks w ON w.id = d.webhook_id
+         JOIN outbox e ON e.id = d.event_id
+ORDER BY d.id;`, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("unable to claim deliveries: %w", err)
+	}
+	res, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (task model.WebhookTask, err error) {
+		var (
+			payload   []byte
+			createdAt time.Time
+		)
+		d, err := scanDelivery(row, &task.URL, &task.Secret, &payload, &createdAt)
+		if err != nil {
+			return task, err
+		}
+		task.Delivery = *d
+		task.Event = model.Event{
+			ID:        d.EventID,
+			Type:      d.EventType,
+			Payload:   payload,
+			CreatedAt: datetime.Time(createdAt),
+		}
+		return task, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to scan deliveries: %w", err)
+	}
+	return res, nil
+}
+
+// RecordWebhookAttempt records result of attempt to deliver pending delivery.
+//
+// If delivery is not pending anymore, for example because webhook was deleted, then store.ErrDoesNotExists is returned.
+func (s *Store) RecordWebhookAttempt(ctx context.Context, id int64, attempt *model.WebhookAttempt) (err error) {
+	defer classify(&err)
+
+	if attempt == nil {
+		return ErrNilReference
+	}
+	tag, err := s.pool.Exec(ctx, `UPDATE webhook_deliveries
+SET attempts         = attempts + 1,
+    status           = $2,
+    last_status_code = NULLIF($3::INT4, 0),
+    last_error       = NULLIF($4::TEXT, ''),
+    next_attempt_at  = now() + make_interval(secs => $5::FLOAT8),
+    delivered_at     = CASE WHEN $2 = 'DELIVERED' THEN now() END
+WHERE id = $1
+  AND status = 'PENDING';`, id, attempt.Status, attempt.StatusCode, attempt.Error, attempt.RetryIn.Seconds())
+	if err != nil {
+		return fmt.Errorf("unable to record attempt: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("pending delivery %d: %w", id, store.ErrDoesNotExists)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package pgx
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
+	"testing"
+	"time"
+)
+
+func TestStore_CreateWebhook_Negative(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.ErrorIs(t, s.CreateWebhook(context.Background(), nil), ErrNilReference)
+	assert.Error(t, s.CreateWebhook(context.Background(), &model.WebhookDTO{URL: "http://localhost"}))
+}
+
+func TestStore_GetWebhooks_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	webhooks, err := s.GetWebhooks(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, webhooks)
+}
+
+func TestStore_DeleteWebhook_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.Error(t, s.DeleteWebhook(context.Background(), 1))
+}
+
+func TestStore_GetWebhookDeliveries_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	deliveries, err := s.GetWebhookDeliveries(context.Background(), 1, 10, 0)
+	assert.Error(t, err)
+	assert.Nil(t, deliveries)
+}
+
+func TestStore_ReplayWebhookDelivery_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	d, err := s.ReplayWebhookDelivery(context.Background(), 1, 1)
+	assert.Error(t, err)
+	assert.Nil(t, d)
+}
+
+func TestStore_ClaimWebhookDeliveries_Negative_BadCli(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	tasks, err := s.ClaimWebhookDeliveries(context.Background(), 10, time.Minute)
+	assert.Error(t, err)
+	assert.Nil(t, tasks)
+}
+
+func TestStore_RecordWebhookAttempt_Negative(t *testing.T) {
+	s, _ := New(client.BadCli(t))
+	assert.ErrorIs(t, s.RecordWebhookAttempt(context.Background(), 1, nil), ErrNilReference)
+	assert.Error(t, s.RecordWebhookAttempt(context.Background(), 1, &model.WebhookAttempt{Status: model.DeliveryStatusDelivered}))
+}
@@ -0,0 +1,944 @@
+// Package storetest contains conformance test suite which every implementation of production.Store must pass.
+//
+// Implementations call Run from their own tests:
+//
+//	func TestStore_Conformance(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) production.Store {
+//			return memory.New()
+//		})
+//	}
+package storetest
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/outbox"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/service/production"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/stream"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/webhook"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"testing"
+	"time"
+)
+
+// Factory returns new empty storage for one test.
+//
+// Factory may skip test if storage is not available and must register its cleanup with t.Cleanup.
+type Factory func(t *testing.T) production.Store
+
+// date is date of assignments made by suite.
+const date = "2023-01-01"
+
+// Run runs all conformance tests against storages created by factory, every test gets its own storage.
+func Run(t *testing.T, factory Factory) {
+	tt := []struct {
+		name string
+		test func(t *testing.T, s production.Store)
+	}{
+		{"CreateCouriers", testCreateCouriers},
+		{"CreateCouriers_Atomic", testCreateCouriersAtomic},
+		{"CreateOrders", testCreateOrders},
+		{"CreateOrders_Atomic", testCreateOrdersAtomic},
+		{"Pagination", testPagination},
+		{"KeysetPagination", testKeysetPagination},
+		{"UpdateCourier", testUpdateCourier},
+		{"DeactivateCourier", testDeactivateCourier},
+		{"NotFound", testNotFound},
+		{"CompleteOrders", testCompleteOrders},
+		{"CompleteOrders_Negative", testCompleteOrdersNegative},
+		{"Earnings", testEarnings},
+		{"OrderStatus", testOrderStatus},
+		{"CancelOrders", testCancelOrders},
+		{"Outbox", testOutbox},
+		{"Webhooks", testWebhooks},
+		{"EventStream", testEventStream},
+		{"APIKeys", testAPIKeys},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.test(t, factory(t))
+		})
+	}
+}
+
+func hours(start, end int32) []*datetime.TimeInterval {
+	return []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: start, End: end}.TimeInterval()}
+}
+
+func couriers() []model.CreateCourierDTO {
+	return []model.CreateCourierDTO{
+		{CourierType: model.FootCourierTypeString, Regions: []int32{1}, WorkingHours: hours(600, 720)},
+		{CourierType: model.AutoCourierTypeString, Regions: []int32{1, 2, 3}, WorkingHours: hours(540, 1200)},
+		{CourierType: model.BikeCourierTypeString, Regions: []int32{2}, WorkingHours: hours(1320, 120)},
+	}
+}
+
+func orders() []*model.OrderDTO {
+	return []*model.OrderDTO{
+		{Weight: 1, Regions: 1, DeliveryHours: hours(600, 1200), Cost: 10},
+		{
+			Weight:        60,
+			Regions:       2,
+			DeliveryHours: hours(600, 1200),
+			Cost:          100,
+			SubOrders: []*model.OrderDTO{
+				{Weight: 30, Regions: 2, DeliveryHours: hours(600, 1200), Cost: 50},
+				{Weight: 30, Regions: 2, DeliveryHours: hours(600, 1200), Cost: 50},
+			},
+		},
+		{Weight: 2.5, Regions: 3, DeliveryHours: hours(1320, 120), Cost: 20},
+	}
+}
+
+// seed creates couriers and orders returned by couriers and orders.
+func seed(t *testing.T, s production.Store) ([]model.CourierDTO, []*model.OrderDTO) {
+	t.Helper()
+	ctx := context.Background()
+
+	c, err := s.CreateCouriers(ctx, couriers())
+	require.NoError(t, err)
+	o := orders()
+	require.NoError(t, s.CreateOrders(ctx, o))
+	return c, o
+}
+
+// assign assigns orders with provided ids to courier in one group.
+func assign(t *testing.T, s production.Store, courier int64, ids ...int64) {
+	t.Helper()
+
+	group := model.GroupOrders{}
+	for _, id := range ids {
+		group.Orders = append(group.Orders, model.OrderDTO{OrderID: id})
+	}
+	require.NoError(t, s.SaveOrdersAssign(context.Background(), &model.OrderAssignResponse{
+		Date:     date,
+		Couriers: []model.CourierGroupOrders{{CourierID: courier, Orders: []model.GroupOrders{group}}},
+	}))
+}
+
+func testCreateCouriers(t *testing.T, s production.Store) {
+	ctx := context.Background()
+
+	created, err := s.CreateCouriers(ctx, couriers())
+	require.NoError(t, err)
+	require.Len(t, created, len(couriers()))
+	for i, c := range created {
+		if i > 0 {
+			assert.Greater(t, c.CourierID, created[i-1].CourierID, "ids must follow order of input")
+		}
+		assert.Equal(t, couriers()[i].CourierType, c.CourierType)
+
+		got, err := s.GetCourierByID(ctx, c.CourierID)
+		require.NoError(t, err)
+		assert.Equal(t, c, *got)
+	}
+
+	all, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, created, all)
+}
+
+func testCreateCouriersAtomic(t *testing.T, s production.Store) {
+	ctx := context.Background()
+
+	batch := append(couriers(), model.CreateCourierDTO{CourierType: "unknown", Regions: []int32{1}})
+	created, err := s.CreateCouriers(ctx, batch)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	assert.Nil(t, created)
+
+	all, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all, "failed batch must not create any courier")
+}
+
+func testCreateOrders(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	_, created := seed(t, s)
+
+	parent := created[1]
+	ids := []int64{created[2].OrderID, created[0].OrderID, parent.OrderID}
+	got, err := s.GetOrdersByIDs(ctx, ids)
+	require.NoError(t, err)
+	require.Len(t, got, len(ids))
+	for i, o := range got {
+		assert.Equal(t, ids[i], o.OrderID, "orders must follow order of ids")
+	}
+	want := created[2]
+	assert.Equal(t, want.Weight, got[0].Weight)
+	assert.Equal(t, want.Regions, got[0].Regions)
+	assert.Equal(t, want.Cost, got[0].Cost)
+	assert.Equal(t, want.DeliveryHours, got[0].DeliveryHours)
+	assert.True(t, time.Time(got[0].CompletedTime).IsZero())
+
+	subs, err := s.GetSubOrders(ctx, parent.OrderID)
+	require.NoError(t, err)
+	if assert.Len(t, subs, 2) {
+		for i, sub := range subs {
+			assert.Equal(t, parent.SubOrders[i].OrderID, sub.OrderID)
+			assert.Equal(t, parent.OrderID, sub.ParentOrderID)
+		}
+	}
+	subs, err = s.GetSubOrders(ctx, created[0].OrderID)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	var unassignedIDs []int64
+	for _, o := range unassigned {
+		unassignedIDs = append(unassignedIDs, o.OrderID)
+	}
+	assert.Equal(t, []int64{
+		created[0].OrderID,
+		parent.SubOrders[0].OrderID,
+		parent.SubOrders[1].OrderID,
+		created[2].OrderID,
+	}, unassignedIDs, "split order must be replaced with its sub-orders")
+}
+
+func testCreateOrdersAtomic(t *testing.T, s production.Store) {
+	ctx := context.Background()
+
+	batch := orders()
+	batch[1].SubOrders[1].Cost = 0
+	assert.ErrorIs(t, s.CreateOrders(ctx, batch), store.ErrCheckViolation)
+
+	all, err := s.GetOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, all, "failed batch must not create any order")
+}
+
+func testPagination(t *testing.T, s production.Store) {
+	ctx := context.Background()
+
+	c, err := s.GetCouriers(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, c)
+	assert.Empty(t, c)
+	o, err := s.GetOrders(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, o)
+	assert.Empty(t, o)
+
+	createdCouriers, createdOrders := seed(t, s)
+	// sub-orders are listed among orders right after their parent.
+	var orderIDs []int64
+	for _, o := range createdOrders {
+		orderIDs = append(orderIDs, o.OrderID)
+		for _, sub := range o.SubOrders {
+			orderIDs = append(orderIDs, sub.OrderID)
+		}
+	}
+
+	tt := []struct {
+		name          string
+		limit, offset int
+	}{
+		{"first", 1, 0},
+		{"middle", 2, 1},
+		{"tail", 10, 2},
+		{"zero limit", 0, 0},
+		{"offset out of range", 10, 100},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := s.GetCouriers(ctx, tc.limit, tc.offset)
+			require.NoError(t, err)
+			assert.NotNil(t, c)
+			from, to := window(len(createdCouriers), tc.limit, tc.offset)
+			assert.Equal(t, createdCouriers[from:to], c)
+
+			o, err := s.GetOrders(ctx, tc.limit, tc.offset)
+			require.NoError(t, err)
+			assert.NotNil(t, o)
+			ids := []int64{}
+			for _, order := range o {
+				ids = append(ids, order.OrderID)
+			}
+			from, to = window(len(orderIDs), tc.limit, tc.offset)
+			assert.Equal(t, orderIDs[from:to], ids)
+		})
+	}
+
+	_, err = s.GetCouriers(ctx, -1, 0)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	_, err = s.GetCouriers(ctx, 1, -1)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	_, err = s.GetOrders(ctx, -1, 0)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	_, err = s.GetOrders(ctx, 1, -1)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+}
+
+func testKeysetPagination(t *testing.T, s production.Store) {
+	ctx := context.Background()
+
+	createdCouriers, createdOrders := seed(t, s)
+	var courierIDs, orderIDs []int64
+	for _, c := range createdCouriers {
+		courierIDs = append(courierIDs, c.CourierID)
+	}
+	for _, o := range createdOrders {
+		orderIDs = append(orderIDs, o.OrderID)
+		for _, sub := range o.SubOrders {
+			orderIDs = append(orderIDs, sub.OrderID)
+		}
+	}
+
+	var got []int64
+	for after := int64(0); ; {
+		c, err := s.GetCouriersAfter(ctx, after, 2)
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		if len(c) == 0 {
+			break
+		}
+		for _, courier := range c {
+			got = append(got, courier.CourierID)
+		}
+		after = c[len(c)-1].CourierID
+	}
+	assert.Equal(t, courierIDs, got)
+
+	got = nil
+	for after := int64(0); ; {
+		o, err := s.GetOrdersAfter(ctx, after, 2)
+		require.NoError(t, err)
+		require.NotNil(t, o)
+		if len(o) == 0 {
+			break
+		}
+		for _, order := range o {
+			got = append(got, order.OrderID)
+		}
+		after = o[len(o)-1].OrderID
+	}
+	assert.Equal(t, orderIDs, got)
+
+	c, err := s.GetCouriersAfter(ctx, courierIDs[0], 1)
+	require.NoError(t, err)
+	assert.Equal(t, createdCouriers[1:2], c)
+
+	_, err = s.GetCouriersAfter(ctx, 0, -1)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	_, err = s.GetOrdersAfter(ctx, 0, -1)
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+}
+
+func testUpdateCourier(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	created, _ := seed(t, s)
+
+	bike := model.BikeCourierTypeString
+	updated, err := s.UpdateCourier(ctx, created[0].CourierID, &model.UpdateCourierRequest{
+		CourierType:  &bike,
+		Regions:      []int32{4, 5},
+		WorkingHours: hours(480, 600),
+	})
+	require.NoError(t, err)
+	want := created[0]
+	want.CourierType, want.Regions, want.WorkingHours = bike, []int32{4, 5}, hours(480, 600)
+	assert.Equal(t, &want, updated)
+
+	got, err := s.GetCourierByID(ctx, created[0].CourierID)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got)
+
+	// fields which are not provided are kept.
+	updated, err = s.UpdateCourier(ctx, created[1].CourierID, &model.UpdateCourierRequest{WorkingHours: hours(60, 120)})
+	require.NoError(t, err)
+	want = created[1]
+	want.WorkingHours = hours(60, 120)
+	assert.Equal(t, &want, updated)
+
+	unknown := "unknown"
+	_, err = s.UpdateCourier(ctx, created[1].CourierID, &model.UpdateCourierRequest{CourierType: &unknown})
+	assert.ErrorIs(t, err, store.ErrCheckViolation)
+	got, err = s.GetCourierByID(ctx, created[1].CourierID)
+	require.NoError(t, err)
+	assert.Equal(t, &want, got, "failed update must not change courier")
+
+	_, err = s.UpdateCourier(ctx, created[len(created)-1].CourierID+100, &model.UpdateCourierRequest{Regions: []int32{1}})
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+}
+
+func testDeactivateCourier(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	created, _ := seed(t, s)
+
+	require.NoError(t, s.DeactivateCourier(ctx, created[1].CourierID))
+	require.NoError(t, s.DeactivateCourier(ctx, created[1].CourierID), "deactivation must be idempotent")
+	assert.ErrorIs(t, s.DeactivateCourier(ctx, created[len(created)-1].CourierID+100), store.ErrDoesNotExists)
+
+	got, err := s.GetCourierByID(ctx, created[1].CourierID)
+	require.NoError(t, err)
+	assert.False(t, got.Active)
+
+	active := []model.CourierDTO{created[0], created[2]}
+	all, err := s.GetActiveCouriers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, active, all)
+
+	page, err := s.GetCouriers(ctx, 10, 1)
+	require.NoError(t, err)
+	assert.Equal(t, active[1:], page)
+
+	page, err = s.GetCouriersAfter(ctx, created[0].CourierID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, active[1:], page)
+}
+
+// window returns bounds of page of n records.
+func window(n, limit, offset int) (from, to int) {
+	from, to = offset, offset+limit
+	if from > n {
+		from = n
+	}
+	if to > n {
+		to = n
+	}
+	return from, to
+}
+
+func testNotFound(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	_, created := seed(t, s)
+	missing := created[len(created)-1].OrderID + 100
+
+	c, err := s.GetCourierByID(ctx, missing)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, c)
+
+	o, err := s.GetOrderByID(ctx, missing)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, o)
+
+	orders, err := s.GetOrdersByIDs(ctx, []int64{created[0].OrderID, missing})
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, orders)
+
+	resp, err := s.GetOrdersAssign(ctx, date, 0)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.Nil(t, resp)
+}
+
+func testCompleteOrders(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, o := seed(t, s)
+	courier := c[1].CourierID
+	parent := o[1]
+	assign(t, s, courier, o[0].OrderID, parent.SubOrders[0].OrderID, parent.SubOrders[1].OrderID)
+
+	first := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	last := first.Add(time.Hour)
+
+	require.NoError(t, s.CompleteOrders(ctx, nil))
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[0].OrderID, CompleteTime: datetime.Time(first)},
+		{CourierID: courier, OrderID: parent.SubOrders[0].OrderID, CompleteTime: datetime.Time(first)},
+	}))
+	got, err := s.GetOrderByID(ctx, parent.OrderID)
+	require.NoError(t, err)
+	assert.True(t, time.Time(got.CompletedTime).IsZero(), "parent is completed only with all sub-orders")
+
+	// repeated completion succeeds and keeps the first completion time.
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[0].OrderID, CompleteTime: datetime.Time(last)},
+		{CourierID: courier, OrderID: parent.SubOrders[1].OrderID, CompleteTime: datetime.Time(last)},
+		{CourierID: courier, OrderID: parent.SubOrders[1].OrderID, CompleteTime: datetime.Time(last)},
+	}))
+	got, err = s.GetOrderByID(ctx, o[0].OrderID)
+	require.NoError(t, err)
+	assert.True(t, first.Equal(time.Time(got.CompletedTime)), "got %v", time.Time(got.CompletedTime))
+
+	got, err = s.GetOrderByID(ctx, parent.OrderID)
+	require.NoError(t, err)
+	assert.True(t, last.Equal(time.Time(got.CompletedTime)), "parent must be completed with the last sub-order")
+
+	assigned, err := s.GetAssignedOrders(ctx, date)
+	require.NoError(t, err)
+	assert.Empty(t, assigned)
+}
+
+func testCompleteOrdersNegative(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, o := seed(t, s)
+	assign(t, s, c[1].CourierID, o[0].OrderID)
+	at := datetime.Time(time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	tt := []struct {
+		name  string
+		batch []model.CompleteOrder
+	}{
+		{"unknown order", []model.CompleteOrder{{CourierID: c[1].CourierID, OrderID: o[2].OrderID + 100, CompleteTime: at}}},
+		{"not assigned order", []model.CompleteOrder{{CourierID: c[1].CourierID, OrderID: o[2].OrderID, CompleteTime: at}}},
+		{"another courier", []model.CompleteOrder{{CourierID: c[0].CourierID, OrderID: o[0].OrderID, CompleteTime: at}}},
+		{"one bad order in batch", []model.CompleteOrder{
+			{CourierID: c[1].CourierID, OrderID: o[0].OrderID, CompleteTime: at},
+			{CourierID: c[1].CourierID, OrderID: o[2].OrderID, CompleteTime: at},
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, s.CompleteOrders(ctx, tc.batch), store.ErrDoesNotExists)
+
+			got, err := s.GetOrderByID(ctx, o[0].OrderID)
+			require.NoError(t, err)
+			assert.True(t, time.Time(got.CompletedTime).IsZero(), "failed batch must not complete any order")
+		})
+	}
+}
+
+func testEarnings(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, o := seed(t, s)
+	courier := c[1].CourierID
+	parent := o[1]
+	assign(t, s, courier, o[0].OrderID, parent.SubOrders[0].OrderID, parent.SubOrders[1].OrderID, o[2].OrderID)
+
+	day := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[0].OrderID, CompleteTime: datetime.Time(day.Add(10 * time.Hour))},
+		{CourierID: courier, OrderID: parent.SubOrders[0].OrderID, CompleteTime: datetime.Time(day.Add(12 * time.Hour))},
+		{CourierID: courier, OrderID: parent.SubOrders[1].OrderID, CompleteTime: datetime.Time(day.Add(26 * time.Hour))},
+	}))
+
+	tt := []struct {
+		name       string
+		courier    int64
+		start, end time.Time
+		sum, count int32
+	}{
+		{"whole day", courier, day, day.Add(24*time.Hour - time.Nanosecond), 60, 2},
+		{"bounds are inclusive", courier, day.Add(10 * time.Hour), day.Add(12 * time.Hour), 60, 2},
+		{"part of day", courier, day.Add(11 * time.Hour), day.Add(13 * time.Hour), 50, 1},
+		{"two days", courier, day, day.Add(48 * time.Hour), 110, 3},
+		{"no orders in span", courier, day.Add(-24 * time.Hour), day.Add(-time.Hour), 0, 0},
+		{"another courier", c[0].CourierID, day, day.Add(48 * time.Hour), 0, 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sum, count, err := s.GetCompletedOrdersPriceByCourier(ctx, tc.courier, tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.sum, sum)
+			assert.Equal(t, tc.count, count)
+		})
+	}
+}
+
+// history returns statuses with actors from history of order.
+func history(t *testing.T, s production.Store, id int64) []string {
+	t.Helper()
+
+	changes, err := s.GetOrderHistory(context.Background(), id)
+	require.NoError(t, err)
+	res := make([]string, 0, len(changes))
+	for _, change := range changes {
+		assert.False(t, time.Time(change.ChangedAt).IsZero())
+		res = append(res, change.Status+" by "+change.Actor)
+	}
+	return res
+}
+
+func testOrderStatus(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, o := seed(t, s)
+	courier := c[1].CourierID
+	for _, order := range o {
+		assert.Equal(t, model.OrderStatusCreated, order.Status)
+	}
+	assign(t, s, courier, o[0].OrderID, o[2].OrderID)
+
+	got, err := s.GetOrderByID(ctx, o[0].OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusAssigned, got.Status)
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment"}, history(t, s, o[0].OrderID))
+
+	require.NoError(t, s.ChangeOrderStatus(ctx, o[0].OrderID, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI))
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, o[0].OrderID, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI), store.ErrStatusChanged)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, o[2].OrderID+100, 0, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.ActorAPI), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, o[2].OrderID, c[0].CourierID, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.CourierActor(c[0].CourierID)), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.ChangeOrderStatus(ctx, o[1].OrderID, 0, model.OrderStatusCreated, model.OrderStatusCancelled, model.ActorAPI), store.ErrStatusChanged)
+
+	// replacing assignment takes back only orders which courier has not taken yet.
+	require.NoError(t, s.SaveOrdersAssign(ctx, &model.OrderAssignResponse{Date: date}))
+	got, err = s.GetOrderByID(ctx, o[2].OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCreated, got.Status)
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "CREATED by assignment"}, history(t, s, o[2].OrderID))
+	assigned, err := s.GetAssignedOrders(ctx, date)
+	require.NoError(t, err)
+	assert.Empty(t, assigned)
+
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[0].OrderID, CompleteTime: datetime.Time(time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))},
+	}))
+	assert.Equal(
+		t,
+		[]string{"CREATED by api", "ASSIGNED by assignment", "IN_DELIVERY by api", "COMPLETED by " + model.CourierActor(courier)},
+		history(t, s, o[0].OrderID),
+	)
+
+	assign(t, s, courier, o[2].OrderID)
+	require.NoError(t, s.ChangeOrderStatus(ctx, o[2].OrderID, courier, model.OrderStatusAssigned, model.OrderStatusInDelivery, model.CourierActor(courier)))
+	require.NoError(t, s.ChangeOrderStatus(ctx, o[2].OrderID, 0, model.OrderStatusInDelivery, model.OrderStatusFailed, model.ActorAPI))
+	assert.ErrorIs(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[2].OrderID, CompleteTime: datetime.Time(time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))},
+	}), store.ErrStatusChanged)
+	assert.Equal(
+		t,
+		[]string{
+			"CREATED by api", "ASSIGNED by assignment", "CREATED by assignment", "ASSIGNED by assignment",
+			"IN_DELIVERY by " + model.CourierActor(courier), "FAILED by api",
+		},
+		history(t, s, o[2].OrderID),
+	)
+
+	_, err = s.GetOrderHistory(ctx, o[2].OrderID+100)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+}
+
+func testCancelOrders(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, o := seed(t, s)
+	courier := c[1].CourierID
+	parent := o[1]
+	assign(t, s, courier, o[0].OrderID, parent.SubOrders[0].OrderID, o[2].OrderID)
+	at := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, s.CompleteOrders(ctx, []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[2].OrderID, CompleteTime: datetime.Time(at)},
+	}))
+
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{o[0].OrderID, o[2].OrderID + 100}, "withdrew", model.ActorAPI), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{o[0].OrderID, o[2].OrderID}, "withdrew", model.ActorAPI), store.ErrStatusChanged)
+	got, err := s.GetOrderByID(ctx, o[0].OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusAssigned, got.Status, "failed batch must not cancel any order")
+
+	require.NoError(t, s.CancelOrders(ctx, []int64{o[0].OrderID, parent.OrderID}, "withdrew", model.ActorAPI))
+	require.NoError(t, s.CancelOrders(ctx, []int64{o[0].OrderID}, "another reason", model.ActorAPI))
+	for _, id := range []int64{o[0].OrderID, parent.OrderID, parent.SubOrders[0].OrderID, parent.SubOrders[1].OrderID} {
+		got, err = s.GetOrderByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, model.OrderStatusCancelled, got.Status)
+		assert.Equal(t, "withdrew", got.CancelReason, "cancelling keeps the first reason")
+	}
+	assert.Equal(t, []string{"CREATED by api", "ASSIGNED by assignment", "CANCELLED by api"}, history(t, s, o[0].OrderID))
+
+	// cancelled orders leave group, only completed order stays in it.
+	resp, err := s.GetOrdersAssign(ctx, date, 0)
+	require.NoError(t, err)
+	if assert.Len(t, resp.Couriers, 1) && assert.Len(t, resp.Couriers[0].Orders, 1) {
+		orders := resp.Couriers[0].Orders[0].Orders
+		if assert.Len(t, orders, 1) {
+			assert.Equal(t, o[2].OrderID, orders[0].OrderID)
+		}
+	}
+	unassigned, err := s.GetUnassignedOrders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, unassigned)
+	sum, count, err := s.GetCompletedOrdersPriceByCourier(ctx, courier, at, at)
+	require.NoError(t, err)
+	assert.Equal(t, o[2].Cost, sum)
+	assert.Equal(t, int32(1), count)
+}
+
+// pending returns at most limit pending events of outbox with their types.
+func pending(t *testing.T, s outbox.Store, limit int) ([]model.Event, []string) {
+	t.Helper()
+
+	events, err := s.GetPendingEvents(context.Background(), limit)
+	require.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return events, types
+}
+
+func testOutbox(t *testing.T, s production.Store) {
+	ob, ok := s.(outbox.Store)
+	if !ok {
+		t.Skip("storage has no outbox")
+	}
+	ctx := context.Background()
+
+	_, err := s.CreateCouriers(ctx, append(couriers(), model.CreateCourierDTO{CourierType: "unknown", Regions: []int32{1}}))
+	require.Error(t, err)
+	events, _ := pending(t, ob, 100)
+	assert.Empty(t, events, "failed batch must not publish events")
+
+	c, o := seed(t, s)
+	courier := c[1].CourierID
+	parent := o[1]
+	assign(t, s, courier, o[0].OrderID, parent.SubOrders[0].OrderID, parent.SubOrders[1].OrderID)
+
+	at := datetime.Time(time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC))
+	complete := []model.CompleteOrder{
+		{CourierID: courier, OrderID: o[0].OrderID, CompleteTime: at},
+		{CourierID: courier, OrderID: parent.SubOrders[0].OrderID, CompleteTime: at},
+		{CourierID: courier, OrderID: parent.SubOrders[1].OrderID, CompleteTime: at},
+	}
+	assert.ErrorIs(t, s.CompleteOrders(ctx, append(complete, model.CompleteOrder{
+		CourierID: courier, OrderID: o[2].OrderID, CompleteTime: at,
+	})), store.ErrDoesNotExists)
+	require.NoError(t, s.CompleteOrders(ctx, complete))
+	require.NoError(t, s.CompleteOrders(ctx, complete))
+
+	events, types := pending(t, ob, 100)
+	assert.Equal(t, []string{
+		model.EventCourierCreated, model.EventCourierCreated, model.EventCourierCreated,
+		model.EventOrderCreated, model.EventOrderCreated, model.EventOrderCreated,
+		model.EventOrderAssigned, model.EventOrderAssigned, model.EventOrderAssigned,
+		model.EventOrdersAssigned,
+		model.EventOrderCompleted, model.EventOrderCompleted, model.EventOrderCompleted, model.EventOrderCompleted,
+	}, types, "failed and repeated completions must not publish events")
+	require.Len(t, events, 14)
+	for i := 1; i < len(events); i++ {
+		assert.Less(t, events[i-1].ID, events[i].ID)
+	}
+
+	var created model.OrderDTO
+	require.NoError(t, json.Unmarshal(events[4].Payload, &created))
+	assert.Equal(t, parent.OrderID, created.OrderID)
+	assert.Len(t, created.SubOrders, 2)
+
+	var assigned model.OrderAssignedEvent
+	require.NoError(t, json.Unmarshal(events[7].Payload, &assigned))
+	assert.Equal(t, model.OrderAssignedEvent{
+		OrderID:      parent.SubOrders[0].OrderID,
+		CourierID:    courier,
+		GroupOrderID: assigned.GroupOrderID,
+		Region:       parent.SubOrders[0].Regions,
+		Date:         date,
+	}, assigned)
+
+	var completed model.OrderCompletedEvent
+	require.NoError(t, json.Unmarshal(events[13].Payload, &completed))
+	assert.Equal(t, parent.OrderID, completed.OrderID, "parent is completed with the last sub-order")
+	assert.Equal(t, courier, completed.CourierID)
+	assert.Equal(t, parent.Regions, completed.Region)
+
+	require.NoError(t, ob.MarkEventsDelivered(ctx, []int64{events[0].ID, events[1].ID, events[2].ID}))
+	require.NoError(t, ob.MarkEventsDelivered(ctx, []int64{events[0].ID}))
+	_, types = pending(t, ob, 2)
+	assert.Equal(t, []string{model.EventOrderCreated, model.EventOrderCreated}, types)
+	left, _ := pending(t, ob, 100)
+	assert.Equal(t, events[3:], left)
+}
+
+func testEventStream(t *testing.T, s production.Store) {
+	es, ok := s.(stream.Store)
+	if !ok {
+		t.Skip("storage has no event stream")
+	}
+	ctx := context.Background()
+
+	last, err := es.GetLastEventID(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, last)
+	events, err := es.GetEventsAfter(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	seed(t, s)
+	all, err := es.GetEventsAfter(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, all, 6)
+	last, err = es.GetLastEventID(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, all[5].ID, last)
+
+	events, err = es.GetEventsAfter(ctx, all[1].ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, all[2:4], events)
+	events, err = es.GetEventsAfter(ctx, last, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	if ob, ok := s.(outbox.Store); ok {
+		require.NoError(t, ob.MarkEventsDelivered(ctx, []int64{all[0].ID, all[1].ID}))
+		events, err = es.GetEventsAfter(ctx, 0, 100)
+		require.NoError(t, err)
+		assert.Equal(t, all, events, "delivered events must be streamed too")
+	}
+}
+
+// claim claims due deliveries and returns them with ids of their events.
+func claim(t *testing.T, s webhook.Store) ([]model.WebhookTask, []string) {
+	t.Helper()
+
+	tasks, err := s.ClaimWebhookDeliveries(context.Background(), 100, time.Hour)
+	require.NoError(t, err)
+	types := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		types = append(types, task.Event.Type)
+	}
+	return tasks, types
+}
+
+func testWebhooks(t *testing.T, s production.Store) {
+	wh, ok := s.(webhook.Store)
+	if !ok {
+		t.Skip("storage has no webhook deliveries")
+	}
+	ctx := context.Background()
+
+	w1 := &model.WebhookDTO{
+		URL:        "http://127.0.0.1:8081/orders",
+		EventTypes: []string{model.EventOrderCreated, model.EventOrderCancelled},
+		Secret:     "0123456789abcdef",
+	}
+	w2 := &model.WebhookDTO{
+		URL:        "http://127.0.0.1:8082/assigned",
+		EventTypes: []string{model.EventOrderAssigned},
+		Secret:     "fedcba9876543210",
+	}
+	require.NoError(t, s.CreateWebhook(ctx, w1))
+	require.NoError(t, s.CreateWebhook(ctx, w2))
+	assert.NotZero(t, w1.WebhookID)
+	assert.Less(t, w1.WebhookID, w2.WebhookID)
+	webhooks, err := s.GetWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.WebhookDTO{*w1, *w2}, webhooks)
+
+	_, o := seed(t, s)
+	assert.ErrorIs(t, s.CancelOrders(ctx, []int64{o[2].OrderID, o[2].OrderID + 100}, "withdrew", model.ActorAPI), store.ErrDoesNotExists)
+	require.NoError(t, s.CancelOrders(ctx, []int64{o[2].OrderID}, "withdrew", model.ActorAPI))
+	assign(t, s, 2, o[0].OrderID)
+
+	tasks, types := claim(t, wh)
+	assert.Equal(t, []string{
+		model.EventOrderCreated, model.EventOrderCreated, model.EventOrderCreated,
+		model.EventOrderCancelled,
+		model.EventOrderAssigned,
+	}, types, "only subscribed events of committed changes are delivered")
+	require.Len(t, tasks, 5)
+	assert.Equal(t, w1.URL, tasks[0].URL)
+	assert.Equal(t, w1.Secret, tasks[0].Secret)
+	assert.Equal(t, w2.URL, tasks[4].URL)
+	assert.Equal(t, w2.Secret, tasks[4].Secret)
+	for _, task := range tasks {
+		assert.Equal(t, model.DeliveryStatusPending, task.Delivery.Status)
+		assert.Equal(t, task.Event.ID, task.Delivery.EventID)
+		assert.Equal(t, task.Event.Type, task.Delivery.EventType)
+		assert.NotEmpty(t, task.Event.Payload)
+	}
+	var cancelled model.OrderCancelledEvent
+	require.NoError(t, json.Unmarshal(tasks[3].Event.Payload, &cancelled))
+	assert.Equal(t, model.OrderCancelledEvent{OrderID: o[2].OrderID, Region: o[2].Regions, Reason: "withdrew"}, cancelled)
+	_, types = claim(t, wh)
+	assert.Empty(t, types, "claimed deliveries are leased")
+
+	delivered, retried := tasks[0].Delivery.DeliveryID, tasks[1].Delivery.DeliveryID
+	require.NoError(t, wh.RecordWebhookAttempt(ctx, delivered, &model.WebhookAttempt{
+		StatusCode: 200,
+		Status:     model.DeliveryStatusDelivered,
+	}))
+	require.NoError(t, wh.RecordWebhookAttempt(ctx, retried, &model.WebhookAttempt{
+		StatusCode: 500,
+		Error:      "webhook rejected event",
+		Status:     model.DeliveryStatusPending,
+	}))
+	assert.ErrorIs(t, wh.RecordWebhookAttempt(ctx, delivered, &model.WebhookAttempt{
+		Status: model.DeliveryStatusFailed,
+	}), store.ErrDoesNotExists, "delivered delivery is not pending")
+	again, _ := claim(t, wh)
+	if assert.Len(t, again, 1, "retried delivery is due again") {
+		assert.Equal(t, retried, again[0].Delivery.DeliveryID)
+		assert.Equal(t, int32(1), again[0].Delivery.Attempts)
+	}
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, w1.WebhookID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 4)
+	assert.Equal(t, tasks[3].Delivery.DeliveryID, deliveries[0].DeliveryID, "the newest delivery goes first")
+	last := deliveries[3]
+	assert.Equal(t, delivered, last.DeliveryID)
+	assert.Equal(t, model.DeliveryStatusDelivered, last.Status)
+	assert.Equal(t, int32(1), last.Attempts)
+	assert.Equal(t, int32(200), last.LastStatusCode)
+	assert.NotNil(t, last.DeliveredAt)
+	assert.Nil(t, last.NextAttemptAt)
+	assert.Equal(t, "webhook rejected event", deliveries[2].LastError)
+	assert.NotNil(t, deliveries[2].NextAttemptAt)
+	page, err := s.GetWebhookDeliveries(ctx, w1.WebhookID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, deliveries[1:2], page)
+
+	_, err = s.ReplayWebhookDelivery(ctx, w2.WebhookID, delivered)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists, "delivery belongs to another webhook")
+	replay, err := s.ReplayWebhookDelivery(ctx, w1.WebhookID, delivered)
+	require.NoError(t, err)
+	assert.Greater(t, replay.DeliveryID, tasks[4].Delivery.DeliveryID)
+	assert.Equal(t, tasks[0].Event.ID, replay.EventID)
+	assert.Equal(t, model.DeliveryStatusPending, replay.Status)
+	assert.Zero(t, replay.Attempts)
+	replayed, _ := claim(t, wh)
+	if assert.Len(t, replayed, 1) {
+		assert.Equal(t, replay.DeliveryID, replayed[0].Delivery.DeliveryID)
+		assert.Equal(t, tasks[0].Event, replayed[0].Event)
+	}
+
+	require.NoError(t, s.DeleteWebhook(ctx, w1.WebhookID))
+	assert.ErrorIs(t, s.DeleteWebhook(ctx, w1.WebhookID), store.ErrDoesNotExists)
+	_, err = s.GetWebhookDeliveries(ctx, w1.WebhookID, 10, 0)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	_, err = s.ReplayWebhookDelivery(ctx, w1.WebhookID, delivered)
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+	assert.ErrorIs(t, wh.RecordWebhookAttempt(ctx, replay.DeliveryID, &model.WebhookAttempt{
+		Status: model.DeliveryStatusDelivered,
+	}), store.ErrDoesNotExists, "pending deliveries of deleted webhook are failed")
+	webhooks, err = s.GetWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []model.WebhookDTO{*w2}, webhooks)
+
+	require.NoError(t, s.CreateOrders(ctx, orders()[:1]))
+	_, types = claim(t, wh)
+	assert.Empty(t, types, "deleted webhook gets no new events")
+}
+
+func testAPIKeys(t *testing.T, s production.Store) {
+	ctx := context.Background()
+	c, _ := seed(t, s)
+
+	unknown := int64(100)
+	err := s.CreateAPIKey(ctx, &model.APIKey{Role: model.RoleCourier, CourierID: &unknown}, []byte("unknown"))
+	assert.ErrorIs(t, err, store.ErrForeignKeyViolation)
+	assert.ErrorIs(t, s.CreateAPIKey(ctx, &model.APIKey{Role: "root"}, []byte("root")), store.ErrCheckViolation)
+
+	admin := &model.APIKey{Role: model.RoleAdmin}
+	require.NoError(t, s.CreateAPIKey(ctx, admin, []byte("admin")))
+	assert.NotZero(t, admin.KeyID)
+	assert.False(t, admin.CreatedAt.Time().IsZero())
+	assert.ErrorIs(t, s.CreateAPIKey(ctx, &model.APIKey{Role: model.RoleDispatcher}, []byte("admin")), store.ErrUniqueViolation)
+
+	courier := &model.APIKey{Role: model.RoleCourier, CourierID: &c[0].CourierID}
+	require.NoError(t, s.CreateAPIKey(ctx, courier, []byte("courier")))
+	assert.NotEqual(t, admin.KeyID, courier.KeyID)
+
+	got, err := s.GetAPIKeyByHash(ctx, []byte("courier"))
+	require.NoError(t, err)
+	assert.Equal(t, courier.KeyID, got.KeyID)
+	assert.Equal(t, model.RoleCourier, got.Role)
+	if assert.NotNil(t, got.CourierID) {
+		assert.Equal(t, c[0].CourierID, *got.CourierID)
+	}
+	got, err = s.GetAPIKeyByHash(ctx, []byte("admin"))
+	require.NoError(t, err)
+	assert.Nil(t, got.CourierID)
+	_, err = s.GetAPIKeyByHash(ctx, []byte("unknown"))
+	assert.ErrorIs(t, err, store.ErrDoesNotExists)
+
+	require.NoError(t, s.RevokeAPIKey(ctx, courier.KeyID))
+	_, err = s.GetAPIKeyByHash(ctx, []byte("courier"))
+	assert.ErrorIs(t, err, store.ErrDoesNotExists, "revoked key must not authenticate")
+	assert.ErrorIs(t, s.RevokeAPIKey(ctx, courier.KeyID), store.ErrDoesNotExists)
+	assert.ErrorIs(t, s.RevokeAPIKey(ctx, unknown), store.ErrDoesNotExists)
+	_, err = s.GetAPIKeyByHash(ctx, []byte("admin"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,347 @@
+// Package stream fans out events about changes of orders to clients which are subscribed with API.
+//
+// Hub polls outbox and broadcasts new events to subscribers in process, so one query to storage serves any number of
+// clients. Subscriber which does not keep up with events is dropped, it is expected to subscribe again with id of the
+// last event which it got and to receive missed events from storage.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNilReference = errors.New("unexpectedly got nil reference in event stream")
+	// ErrNotRunning is returned when client subscribes to hub which is not started or is already stopped.
+	ErrNotRunning = errors.New("event stream is not running")
+	// ErrBadRequest is returned when subscription request is not valid.
+	ErrBadRequest = errors.New("bad subscription request")
+)
+
+type (
+	// Store is storage of domain events.
+	Store interface {
+		// GetEventsAfter returns at most limit events with id greater than afterID ordered by id.
+		GetEventsAfter(ctx context.Context, afterID int64, limit int) ([]model.Event, error)
+		// GetLastEventID returns id of the last event or zero if there are no events.
+		GetLastEventID(ctx context.Context) (int64, error)
+	}
+	// Config configures hub.
+	Config interface {
+		// PollInterval is delay between checks of new events.
+		PollInterval() time.Duration
+		// BatchSize is maximum number of events which are read from storage at once.
+		BatchSize() int
+		// GapTimeout is how long hub waits for event with missing id before it skips it.
+		GapTimeout() time.Duration
+		// BufferSize is number of events which subscriber may not read yet before it is dropped.
+		BufferSize() int
+	}
+)
+
+// scoped is event about order together with courier and region of order.
+type scoped struct {
+	model.Event
+	courier int64
+	region  int32
+}
+
+// scope is part of payloads of order events which selects subscribers.
+type scope struct {
+	CourierID int64 `json:"courier_id"`
+	Region    int32 `json:"region"`
+	// Regions is region of OrderDTO which is payload of order.created.
+	Regions int32 `json:"regions"`
+}
+
+// newScoped returns event with courier and region decoded from its payload.
+func newScoped(e model.Event) (scoped, error) {
+	var sc scope
+	if err := json.Unmarshal(e.Payload, &sc); err != nil {
+		return scoped{}, fmt.Errorf("json: unmarshal %s payload: %w", e.Type, err)
+	}
+	res := scoped{Event: e, courier: sc.CourierID, region: sc.Region}
+	if e.Type == model.EventOrderCreated {
+		res.region = sc.Regions
+	}
+	return res, nil
+}
+
+// subscriber is client which gets events selected by request.
+type subscriber struct {
+	req  model.SubscribeEventsRequest
+	live chan scoped
+}
+
+// Hub broadcasts new order events to subscribers.
+type Hub struct {
+	store Store
+	cfg   Config
+	log   *zap.Logger
+
+	mu      sync.Mutex
+	running bool
+	// last is id of the last event which was broadcast.
+	last int64
+	// gapSince is time when hub first met missing id after last, it is zero if there is no gap.
+	gapSince time.Time
+	subs     map[*subscriber]struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewHub returns hub of events from store.
+func NewHub(logger *zap.Logger, cfg Config, store Store) (*Hub, error) {
+	if logger == nil || cfg == nil || store == nil {
+		return nil, ErrNilReference
+	}
+	return &Hub{
+		store: store,
+		cfg:   cfg,
+		log:   logger,
+		subs:  make(map[*subscriber]struct{}),
+	}, nil
+}
+
+// Subscribe returns channel of order events selected by request which is closed when ctx is done.
+//
+// If request has id of the last event which client got then events after it are read from storage and sent before new
+// ones. Channel is also closed if subscriber does not read events fast enough or hub is stopped, client should
+// subscribe again with id of the last event which it got.
+func (h *Hub) Subscribe(ctx context.Context, req *model.SubscribeEventsRequest) (<-chan model.Event, error) {
+	if !req.Valid() {
+		return nil, ErrBadRequest
+	}
+	sub := &subscriber{req: *req, live: make(chan scoped, h.cfg.BufferSize())}
+
+	h.mu.Lock()
+	if !h.running {
+		h.mu.Unlock()
+		return nil, ErrNotRunning
+	}
+	h.subs[sub] = struct{}{}
+	bound := h.last
+	h.mu.Unlock()
+
+	out := make(chan model.Event)
+	go h.serve(ctx, sub, bound, out)
+	return out, nil
+}
+
+// serve sends missed events up to bound and then live events of subscriber to out until ctx is done.
+func (h *Hub) serve(ctx context.Context, sub *subscriber, bound int64, out chan<- model.Event) {
+	defer close(out)
+	defer h.unsubscribe(sub)
+
+	send := func(e model.Event) bool {
+		select {
+		case out <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if sub.req.LastEventID != nil {
+		if err := h.backfill(ctx, sub, *sub.req.LastEventID, bound, send); err != nil {
+			if ctx.Err() == nil {
+				h.log.Error("unable to send missed events", zap.Error(err))
+			}
+			return
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-sub.live:
+			if !ok || !send(e.Event) {
+				return
+			}
+		}
+	}
+}
+
+// backfill sends events of subscriber with id greater than after and not greater than bound from storage.
+func (h *Hub) backfill(ctx context.Context, sub *subscriber, after, bound int64, send func(model.Event) bool) error {
+	for after < bound {
+		events, err := h.store.GetEventsAfter(ctx, after, h.cfg.BatchSize())
+		if err != nil {
+			return fmt.Errorf("get events after %d: %w", after, err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		for _, e := range events {
+			if e.ID > bound {
+				return nil
+			}
+			after = e.ID
+			if !model.IsOrderEvent(e.Type) {
+				continue
+			}
+			s, err := newScoped(e)
+			if err != nil {
+				h.log.Error("unable to decode event", zap.Int64("event_id", e.ID), zap.Error(err))
+				continue
+			}
+			if sub.req.Match(s.courier, s.region) && !send(e) {
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// unsubscribe removes subscriber from hub if it was not dropped yet.
+func (h *Hub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.live)
+	}
+}
+
+// broadcast sends event to every subscriber which selected it without blocking.
+//
+// Subscriber whose buffer is full is dropped. It must be called with locked mutex.
+func (h *Hub) broadcast(e scoped) {
+	for sub := range h.subs {
+		if !sub.req.Match(e.courier, e.region) {
+			continue
+		}
+		select {
+		case sub.live <- e:
+		default:
+			delete(h.subs, sub)
+			close(sub.live)
+			h.log.Warn("dropped lagging events subscriber", zap.Int64("event_id", e.ID))
+		}
+	}
+}
+
+// Poll broadcasts one batch of events which were stored after the last broadcast one.
+//
+// Transactions may commit events out of order of their ids, so hub waits for missing id for gap timeout before
+// skipping it. It returns number of events which hub moved past.
+func (h *Hub) Poll(ctx context.Context) (int, error) {
+	h.mu.Lock()
+	after := h.last
+	h.mu.Unlock()
+
+	events, err := h.store.GetEventsAfter(ctx, after, h.cfg.BatchSize())
+	if err != nil {
+		return 0, fmt.Errorf("get events after %d: %w", after, err)
+	}
+	batch := make([]scoped, 0, len(events))
+	for _, e := range events {
+		if !model.IsOrderEvent(e.Type) {
+			batch = append(batch, scoped{Event: e})
+			continue
+		}
+		s, err := newScoped(e)
+		if err != nil {
+			h.log.Error("unable to decode event", zap.Int64("event_id", e.ID), zap.Error(err))
+			s = scoped{Event: e}
+		}
+		batch = append(batch, s)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	now := time.Now()
+	n := 0
+	for _, e := range batch {
+		if e.ID <= h.last {
+			continue
+		}
+		if e.ID != h.last+1 {
+			if h.gapSince.IsZero() {
+				h.gapSince = now
+			}
+			if now.Sub(h.gapSince) < h.cfg.GapTimeout() {
+				break
+			}
+			h.log.Warn("skipped missing events", zap.Int64("from_id", h.last+1), zap.Int64("to_id", e.ID-1))
+		}
+		h.gapSince = time.Time{}
+		h.last = e.ID
+		n++
+		if model.IsOrderEvent(e.Type) {
+			h.broadcast(e)
+		}
+	}
+	return n, nil
+}
+
+// run polls events until ctx is done.
+//
+// Full batch means that more events may be stored, so the next batch is read without delay.
+func (h *Hub) run(ctx context.Context) {
+	defer close(h.done)
+
+	for {
+		n, err := h.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			h.log.Error("unable to poll events", zap.Error(err))
+		}
+		if err == nil && n > 0 && n == h.cfg.BatchSize() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.cfg.PollInterval()):
+		}
+	}
+}
+
+// Start starts broadcast of events which are stored after start.
+func (h *Hub) Start(ctx context.Context) error {
+	last, err := h.store.GetLastEventID(ctx)
+	if err != nil {
+		return fmt.Errorf("get last event id: %w", err)
+	}
+	h.mu.Lock()
+	h.last, h.running = last, true
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.done = make(chan struct{})
+	go h.run(ctx)
+	h.log.Info("starting event stream", zap.Int64("last_event_id", last))
+	return nil
+}
+
+// Stop stops broadcast of events and closes channels of all subscribers.
+func (h *Hub) Stop(ctx context.Context) error {
+	if h.cancel == nil {
+		return nil
+	}
+	h.log.Info("stopping event stream")
+	h.cancel()
+
+	h.mu.Lock()
+	h.running = false
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.live)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-h.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,323 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/memory"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type config struct {
+	gap    time.Duration
+	buffer int
+}
+
+func (*config) PollInterval() time.Duration { return time.Millisecond }
+
+func (*config) BatchSize() int { return 2 }
+
+func (c *config) GapTimeout() time.Duration { return c.gap }
+
+func (c *config) BufferSize() int { return c.buffer }
+
+// fakeStore is storage of events which were committed in arbitrary order.
+type fakeStore struct {
+	events []model.Event
+}
+
+func (s *fakeStore) GetEventsAfter(_ context.Context, afterID int64, limit int) ([]model.Event, error) {
+	var res []model.Event
+	for _, e := range s.events {
+		if e.ID > afterID && len(res) < limit {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
+func (s *fakeStore) GetLastEventID(context.Context) (int64, error) {
+	if len(s.events) == 0 {
+		return 0, nil
+	}
+	return s.events[len(s.events)-1].ID, nil
+}
+
+type failingStore struct{}
+
+var errStore = errors.New("store failure")
+
+func (failingStore) GetEventsAfter(context.Context, int64, int) ([]model.Event, error) {
+	return nil, errStore
+}
+
+func (failingStore) GetLastEventID(context.Context) (int64, error) { return 0, errStore }
+
+func event(t *testing.T, id int64, typ string, payload any) model.Event {
+	t.Helper()
+	e, err := model.NewEvent(typ, payload)
+	require.NoError(t, err)
+	e.ID = id
+	return e
+}
+
+func createOrder(t *testing.T, s *memory.Store, region int32) {
+	t.Helper()
+	require.NoError(t, s.CreateOrders(context.Background(), []*model.OrderDTO{{
+		Weight:        1,
+		Regions:       region,
+		Cost:          1,
+		DeliveryHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 720}.TimeInterval()},
+	}}))
+}
+
+func startHub(t *testing.T, cfg *config, s Store) *Hub {
+	t.Helper()
+	h, err := NewHub(zap.L(), cfg, s)
+	require.NoError(t, err)
+	require.NoError(t, h.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, h.Stop(context.Background()))
+	})
+	return h
+}
+
+func receive(t *testing.T, events <-chan model.Event) model.Event {
+	t.Helper()
+	select {
+	case e, ok := <-events:
+		require.True(t, ok, "events channel is closed")
+		return e
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event in time")
+	}
+	return model.Event{}
+}
+
+func assertClosed(t *testing.T, events <-chan model.Event) {
+	t.Helper()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "events channel must be closed")
+	case <-time.After(time.Second):
+		assert.Fail(t, "events channel is not closed in time")
+	}
+}
+
+func TestNewHub_Negative(t *testing.T) {
+	for name, args := range map[string]struct {
+		log   *zap.Logger
+		cfg   Config
+		store Store
+	}{
+		"nil logger": {nil, &config{}, memory.New()},
+		"nil config": {zap.L(), nil, memory.New()},
+		"nil store":  {zap.L(), &config{}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, err := NewHub(args.log, args.cfg, args.store)
+			assert.Nil(t, h)
+			assert.ErrorIs(t, err, ErrNilReference)
+		})
+	}
+}
+
+func TestNewScoped(t *testing.T) {
+	for name, tc := range map[string]struct {
+		event   model.Event
+		courier int64
+		region  int32
+	}{
+		"created": {
+			event:  event(t, 1, model.EventOrderCreated, &model.OrderDTO{OrderID: 1, Regions: 3}),
+			region: 3,
+		},
+		"assigned": {
+			event:   event(t, 2, model.EventOrderAssigned, &model.OrderAssignedEvent{OrderID: 1, CourierID: 2, Region: 3}),
+			courier: 2,
+			region:  3,
+		},
+		"completed": {
+			event:   event(t, 3, model.EventOrderCompleted, &model.OrderCompletedEvent{OrderID: 1, CourierID: 2, Region: 3}),
+			courier: 2,
+			region:  3,
+		},
+		"cancelled": {
+			event:  event(t, 4, model.EventOrderCancelled, &model.OrderCancelledEvent{OrderID: 1, Region: 3}),
+			region: 3,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, err := newScoped(tc.event)
+			require.NoError(t, err)
+			assert.Equal(t, tc.event, s.Event)
+			assert.Equal(t, tc.courier, s.courier)
+			assert.Equal(t, tc.region, s.region)
+		})
+	}
+	t.Run("bad payload", func(t *testing.T) {
+		_, err := newScoped(model.Event{Type: model.EventOrderCreated, Payload: json.RawMessage(`[]`)})
+		assert.Error(t, err)
+	})
+}
+
+func TestHub_Start_Negative(t *testing.T) {
+	h, err := NewHub(zap.L(), &config{}, failingStore{})
+	require.NoError(t, err)
+	assert.ErrorIs(t, h.Start(context.Background()), errStore)
+
+	_, err = h.Subscribe(context.Background(), &model.SubscribeEventsRequest{})
+	assert.ErrorIs(t, err, ErrNotRunning)
+	assert.NoError(t, h.Stop(context.Background()))
+}
+
+func TestHub_Subscribe_BadRequest(t *testing.T) {
+	h := startHub(t, &config{buffer: 1}, memory.New())
+	_, err := h.Subscribe(context.Background(), nil)
+	assert.ErrorIs(t, err, ErrBadRequest)
+	_, err = h.Subscribe(context.Background(), &model.SubscribeEventsRequest{Region: -1})
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestHub_FanOut(t *testing.T) {
+	s := memory.New()
+	createOrder(t, s, 1)
+	h := startHub(t, &config{buffer: 4}, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	all, err := h.Subscribe(ctx, &model.SubscribeEventsRequest{})
+	require.NoError(t, err)
+	region, err := h.Subscribe(ctx, &model.SubscribeEventsRequest{Region: 2})
+	require.NoError(t, err)
+	courier, err := h.Subscribe(ctx, &model.SubscribeEventsRequest{CourierID: 1})
+	require.NoError(t, err)
+
+	createOrder(t, s, 1)
+	createOrder(t, s, 2)
+
+	assert.Equal(t, int64(2), receive(t, all).ID, "events stored before start must not be sent")
+	assert.Equal(t, int64(3), receive(t, all).ID)
+	e := receive(t, region)
+	assert.Equal(t, int64(3), e.ID)
+	assert.Equal(t, model.EventOrderCreated, e.Type)
+
+	select {
+	case e := <-courier:
+		assert.Fail(t, "created order has no courier", "got event %d", e.ID)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	assertClosed(t, all)
+	assertClosed(t, region)
+	assertClosed(t, courier)
+}
+
+func TestHub_Subscribe_Resume(t *testing.T) {
+	s := memory.New()
+	for i := 0; i < 5; i++ {
+		createOrder(t, s, int32(i%2+1))
+	}
+	h := startHub(t, &config{buffer: 4}, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	last := int64(1)
+	events, err := h.Subscribe(ctx, &model.SubscribeEventsRequest{Region: 1, LastEventID: &last})
+	require.NoError(t, err)
+	createOrder(t, s, 1)
+
+	for _, id := range []int64{3, 5, 6} {
+		assert.Equal(t, id, receive(t, events).ID)
+	}
+}
+
+func TestHub_LaggingSubscriber(t *testing.T) {
+	s := memory.New()
+	h := startHub(t, &config{buffer: 1}, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	slow, err := h.Subscribe(ctx, &model.SubscribeEventsRequest{})
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		createOrder(t, s, 1)
+	}
+	require.Eventually(t, func() bool {
+		n, err := h.Poll(ctx)
+		return err == nil && n == 0
+	}, time.Second, time.Millisecond)
+
+	var got []int64
+	for e := range slow {
+		got = append(got, e.ID)
+	}
+	assert.NotEmpty(t, got)
+	assert.Less(t, len(got), 4, "lagging subscriber must be dropped")
+}
+
+func TestHub_Poll_Gap(t *testing.T) {
+	ctx := context.Background()
+	created := func(id int64) model.Event {
+		return event(t, id, model.EventOrderCreated, &model.OrderDTO{OrderID: id, Regions: 1})
+	}
+	s := &fakeStore{}
+	h, err := NewHub(zap.L(), &config{gap: time.Hour, buffer: 4}, s)
+	require.NoError(t, err)
+	h.running = true
+	sub := &subscriber{live: make(chan scoped, 4)}
+	h.subs[sub] = struct{}{}
+
+	s.events = []model.Event{created(1), created(3)}
+	n, err := h.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "event after missing id must wait for it")
+	assert.Equal(t, int64(1), (<-sub.live).ID)
+
+	s.events = []model.Event{created(1), created(2), created(3)}
+	n, err = h.Poll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, int64(2), (<-sub.live).ID)
+	assert.Equal(t, int64(3), (<-sub.live).ID)
+
+	t.Run("timeout", func(t *testing.T) {
+		h.cfg = &config{buffer: 4}
+		s.events = append(s.events, created(5))
+		n, err := h.Poll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n, "missing id must be skipped after timeout")
+		assert.Equal(t, int64(5), (<-sub.live).ID)
+	})
+}
+
+func TestHub_Poll_Negative(t *testing.T) {
+	h, err := NewHub(zap.L(), &config{}, failingStore{})
+	require.NoError(t, err)
+	n, err := h.Poll(context.Background())
+	assert.Zero(t, n)
+	assert.ErrorIs(t, err, errStore)
+}
+
+func TestHub_Stop(t *testing.T) {
+	h, err := NewHub(zap.L(), &config{buffer: 1}, memory.New())
+	require.NoError(t, err)
+	assert.NoError(t, h.Stop(context.Background()), "hub which is not started must stop")
+	require.NoError(t, h.Start(context.Background()))
+
+	events, err := h.Subscribe(context.Background(), &model.SubscribeEventsRequest{})
+	require.NoError(t, err)
+	require.NoError(t, h.Stop(context.Background()))
+	assertClosed(t, events)
+
+	_, err = h.Subscribe(context.Background(), &model.SubscribeEventsRequest{})
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
@@ -0,0 +1,257 @@
+// Package webhook delivers order events to callback URLs which partners subscribed with API.
+//
+// Storage schedules delivery of event to every subscribed webhook in transaction of change which caused event.
+// Dispatcher claims due deliveries, posts events signed with secret of webhook and records every attempt. Failed
+// attempts are retried with exponential backoff until maximum number of attempts is made. Delivery is at least once,
+// so consumers must drop events with ids which they already got.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Headers of request with event.
+const (
+	// HeaderSignature is "sha256=" followed by hex encoded signature, see Sign.
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderTimestamp is unix time of attempt in seconds which is signed together with body.
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	// HeaderEvent is type of event.
+	HeaderEvent = "X-Webhook-Event"
+	// HeaderDelivery is id of delivery, it is changed only by replay.
+	HeaderDelivery = "X-Webhook-Delivery"
+)
+
+// signaturePrefix is name of algorithm of signature in HeaderSignature.
+const signaturePrefix = "sha256="
+
+var (
+	ErrNilReference = errors.New("unexpectedly got nil reference in webhook dispatcher")
+	// ErrRejected is returned when webhook responded with status other than 2xx.
+	ErrRejected = errors.New("webhook rejected event")
+)
+
+type (
+	// Store is storage of webhook deliveries.
+	Store interface {
+		// ClaimWebhookDeliveries returns at most limit pending deliveries which are due and postpones them for lease.
+		ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookTask, error)
+		// RecordWebhookAttempt records result of attempt to deliver pending delivery.
+		RecordWebhookAttempt(ctx context.Context, id int64, attempt *model.WebhookAttempt) error
+	}
+	// Config configures dispatcher.
+	Config interface {
+		// PollInterval is delay between checks of due deliveries.
+		PollInterval() time.Duration
+		// BatchSize is maximum number of deliveries which are posted at once.
+		BatchSize() int
+		// MaxAttempts is number of attempts after which delivery is failed.
+		MaxAttempts() int
+		// Backoff is delay after the first failed attempt, it is doubled after every next one.
+		Backoff() time.Duration
+		// MaxBackoff limits delay between attempts.
+		MaxBackoff() time.Duration
+		// Timeout is timeout of one attempt.
+		Timeout() time.Duration
+	}
+)
+
+// Sign returns hex encoded HMAC-SHA256 of "<timestamp>.<body>" with secret.
+//
+// Timestamp is signed to let consumers reject requests which were replayed later.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if signature from HeaderSignature matches body and timestamp.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	want := signaturePrefix + Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(want), []byte(signature))
+}
+
+// Dispatcher posts due webhook deliveries in background.
+type Dispatcher struct {
+	store  Store
+	cfg    Config
+	log    *zap.Logger
+	client *http.Client
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher returns dispatcher of deliveries from store.
+func NewDispatcher(logger *zap.Logger, cfg Config, store Store) (*Dispatcher, error) {
+	if logger == nil || cfg == nil || store == nil {
+		return nil, ErrNilReference
+	}
+	return &Dispatcher{
+		store:  store,
+		cfg:    cfg,
+		log:    logger,
+		client: &http.Client{Timeout: cfg.Timeout()},
+	}, nil
+}
+
+// backoff returns delay after attempt with provided number which failed.
+func (d *Dispatcher) backoff(attempt int32) time.Duration {
+	delay, limit := d.cfg.Backoff(), d.cfg.MaxBackoff()
+	for i := int32(1); i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+	if delay > limit {
+		return limit
+	}
+	return delay
+}
+
+// Deliver posts one batch of due deliveries and records their attempts.
+//
+// Deliveries are claimed for twice timeout of attempt, so they are not posted again while attempt is in progress. It
+// returns number of claimed deliveries.
+func (d *Dispatcher) Deliver(ctx context.Context) (int, error) {
+	tasks, err := d.store.ClaimWebhookDeliveries(ctx, d.cfg.BatchSize(), 2*d.cfg.Timeout())
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for i := range tasks {
+		wg.Add(1)
+		go func(task *model.WebhookTask) {
+			defer wg.Done()
+			attempt := d.attempt(ctx, task)
+			if err := d.store.RecordWebhookAttempt(ctx, task.Delivery.DeliveryID, attempt); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("record attempt of delivery %d: %w", task.Delivery.DeliveryID, err))
+				mu.Unlock()
+			}
+		}(&tasks[i])
+	}
+	wg.Wait()
+	return len(tasks), errors.Join(errs...)
+}
+
+// attempt posts event of task and returns result of attempt.
+func (d *Dispatcher) attempt(ctx context.Context, task *model.WebhookTask) *model.WebhookAttempt {
+	code, err := d.post(ctx, task)
+	if err == nil {
+		return &model.WebhookAttempt{StatusCode: code, Status: model.DeliveryStatusDelivered}
+	}
+
+	attempts := task.Delivery.Attempts + 1
+	res := &model.WebhookAttempt{StatusCode: code, Error: err.Error(), Status: model.DeliveryStatusPending}
+	if int(attempts) >= d.cfg.MaxAttempts() {
+		res.Status = model.DeliveryStatusFailed
+	} else {
+		res.RetryIn = d.backoff(attempts)
+	}
+	d.log.Warn(
+		"webhook delivery attempt failed",
+		zap.Int64("delivery_id", task.Delivery.DeliveryID),
+		zap.Int64("webhook_id", task.Delivery.WebhookID),
+		zap.Int32("attempts", attempts),
+		zap.String("status", res.Status),
+		zap.Error(err),
+	)
+	return res
+}
+
+// post makes one attempt to post event of task and returns status code of response.
+func (d *Dispatcher) post(ctx context.Context, task *model.WebhookTask) (int32, error) {
+	body, err := json.Marshal(&task.Event)
+	if err != nil {
+		return 0, fmt.Errorf("json: marshal event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("new request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, task.Event.Type)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(task.Delivery.DeliveryID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, signaturePrefix+Sign(task.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("post event: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return int32(resp.StatusCode), fmt.Errorf("%w: status %d", ErrRejected, resp.StatusCode)
+	}
+	return int32(resp.StatusCode), nil
+}
+
+// run posts deliveries until ctx is done.
+//
+// Full batch means that more deliveries may be due, so the next batch is claimed without delay.
+func (d *Dispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	for {
+		n, err := d.Deliver(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.log.Error("unable to deliver webhooks", zap.Error(err))
+		}
+		if err == nil && n > 0 && n == d.cfg.BatchSize() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.cfg.PollInterval()):
+		}
+	}
+}
+
+// Start starts delivery of webhooks in background.
+func (d *Dispatcher) Start(context.Context) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(ctx)
+	d.log.Info("starting webhook dispatcher")
+	return nil
+}
+
+// Stop stops delivery of webhooks and waits until batch which is being posted is done.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.log.Info("stopping webhook dispatcher")
+	d.cancel()
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vlad-marlo/yandex-academy-enrollment/internal/store/memory"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/model"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const secret = "0123456789abcdef"
+
+type config struct {
+	attempts int
+}
+
+func (*config) PollInterval() time.Duration { return time.Millisecond }
+
+func (*config) BatchSize() int { return 10 }
+
+func (c *config) MaxAttempts() int { return c.attempts }
+
+func (*config) Backoff() time.Duration { return time.Millisecond }
+
+func (*config) MaxBackoff() time.Duration { return 4 * time.Millisecond }
+
+func (*config) Timeout() time.Duration { return time.Second }
+
+// receiver is test HTTP server of partner which checks signatures of events.
+type receiver struct {
+	t      *testing.T
+	mu     sync.Mutex
+	status int
+	events []model.Event
+	ids    []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(r.t, err)
+	assert.True(r.t, Verify(secret, timestamp, body, req.Header.Get(HeaderSignature)))
+
+	var e model.Event
+	require.NoError(r.t, json.Unmarshal(body, &e))
+	assert.Equal(r.t, e.Type, req.Header.Get(HeaderEvent))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+	r.ids = append(r.ids, req.Header.Get(HeaderDelivery))
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) received() ([]model.Event, []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.Event(nil), r.events...), append([]string(nil), r.ids...)
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// setup returns store with webhook of test server which is subscribed to created orders.
+func setup(t *testing.T, status int) (*memory.Store, *receiver, int64) {
+	t.Helper()
+	r := &receiver{t: t, status: status}
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+
+	s := memory.New()
+	w := &model.WebhookDTO{URL: srv.URL, EventTypes: []string{model.EventOrderCreated}, Secret: secret}
+	require.NoError(t, s.CreateWebhook(context.Background(), w))
+	return s, r, w.WebhookID
+}
+
+func createOrder(t *testing.T, s *memory.Store) {
+	t.Helper()
+	require.NoError(t, s.CreateOrders(context.Background(), []*model.OrderDTO{{
+		Weight:        1,
+		Regions:       1,
+		Cost:          1,
+		DeliveryHours: []*datetime.TimeInterval{datetime.TimeIntervalAlias{Start: 600, End: 720}.TimeInterval()},
+	}}))
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	sig := Sign(secret, 1, body)
+	assert.Len(t, sig, 64)
+	assert.Equal(t, sig, Sign(secret, 1, body))
+	assert.NotEqual(t, sig, Sign(secret, 2, body))
+	assert.NotEqual(t, sig, Sign("another secret!!", 1, body))
+	assert.True(t, Verify(secret, 1, body, "sha256="+sig))
+	assert.False(t, Verify(secret, 1, body, sig))
+	assert.False(t, Verify(secret, 1, []byte(`{"id":2}`), "sha256="+sig))
+}
+
+func TestNewDispatcher_Negative(t *testing.T) {
+	for name, args := range map[string]struct {
+		log   *zap.Logger
+		cfg   Config
+		store Store
+	}{
+		"nil logger": {nil, &config{}, memory.New()},
+		"nil config": {zap.L(), nil, memory.New()},
+		"nil store":  {zap.L(), &config{}, nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			d, err := NewDispatcher(args.log, args.cfg, args.store)
+			assert.Nil(t, d)
+			assert.ErrorIs(t, err, ErrNilReference)
+		})
+	}
+}
+
+func TestDispatcher_backoff(t *testing.T) {
+	d := &Dispatcher{cfg: &config{}}
+	assert.Equal(t, time.Millisecond, d.backoff(1))
+	assert.Equal(t, 2*time.Millisecond, d.backoff(2))
+	assert.Equal(t, 4*time.Millisecond, d.backoff(3))
+	assert.Equal(t, 4*time.Millisecond, d.backoff(10))
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	ctx := context.Background()
+	s, r, id := setup(t, http.StatusNoContent)
+	d, err := NewDispatcher(zap.L(), &config{attempts: 3}, s)
+	require.NoError(t, err)
+
+	n, err := d.Deliver(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	createOrder(t, s)
+	n, err = d.Deliver(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	events, ids := r.received()
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventOrderCreated, events[0].Type)
+	assert.Equal(t, []string{"1"}, ids)
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, id, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, int32(1), deliveries[0].Attempts)
+	assert.Equal(t, int32(http.StatusNoContent), deliveries[0].LastStatusCode)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+
+	n, err = d.Deliver(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "delivered event must not be posted again")
+}
+
+func TestDispatcher_Deliver_Retry(t *testing.T) {
+	ctx := context.Background()
+	s, r, id := setup(t, http.StatusInternalServerError)
+	d, err := NewDispatcher(zap.L(), &config{attempts: 2}, s)
+	require.NoError(t, err)
+	createOrder(t, s)
+
+	n, err := d.Deliver(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, id, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.DeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, int32(1), deliveries[0].Attempts)
+	assert.Equal(t, int32(http.StatusInternalServerError), deliveries[0].LastStatusCode)
+	assert.NotEmpty(t, deliveries[0].LastError)
+
+	require.Eventually(t, func() bool {
+		n, err = d.Deliver(ctx)
+		return err == nil && n == 1
+	}, time.Second, time.Millisecond)
+	deliveries, err = s.GetWebhookDeliveries(ctx, id, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, model.DeliveryStatusFailed, deliveries[0].Status, "delivery must fail after max attempts")
+	assert.Equal(t, int32(2), deliveries[0].Attempts)
+
+	t.Run("replay", func(t *testing.T) {
+		r.setStatus(http.StatusOK)
+		replay, err := s.ReplayWebhookDelivery(ctx, id, deliveries[0].DeliveryID)
+		require.NoError(t, err)
+
+		n, err := d.Deliver(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		events, ids := r.received()
+		require.Len(t, events, 3)
+		assert.Equal(t, events[0].ID, events[2].ID, "replay must post the same event")
+		assert.Equal(t, strconv.FormatInt(replay.DeliveryID, 10), ids[2])
+
+		deliveries, err = s.GetWebhookDeliveries(ctx, id, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, model.DeliveryStatusDelivered, deliveries[0].Status)
+		assert.Equal(t, model.DeliveryStatusFailed, deliveries[1].Status)
+	})
+}
+
+func TestDispatcher_StartStop(t *testing.T) {
+	s, r, _ := setup(t, http.StatusOK)
+	d, err := NewDispatcher(zap.L(), &config{attempts: 1}, s)
+	require.NoError(t, err)
+	require.NoError(t, d.Start(context.Background()))
+	createOrder(t, s)
+
+	assert.Eventually(t, func() bool {
+		events, _ := r.received()
+		return len(events) == 1
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, d.Stop(context.Background()))
+}
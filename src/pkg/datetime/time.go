@@ -1,6 +1,7 @@
 package datetime
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -90,6 +91,38 @@ func (t Minute) Minute() int {
 	return int(t) % 60
 }
 
+// Add returns time after provided count of minutes.
+//
+// Result is always wrapped into one day, so negative count of minutes moves time backwards.
 func (t Minute) Add(minutes int) Minute {
-	return Minute((int(t) + minutes) % (24 * 60))
+	return Minute(((int(t)+minutes)%int(minutesInDay) + int(minutesInDay)) % int(minutesInDay))
+}
+
+// Sub returns count of minutes from other to t.
+//
+// If t is before other then t is considered to be at the next day, so result is never negative.
+func (t Minute) Sub(other Minute) int {
+	return int(Minute(0).Add(int(t) - int(other)))
+}
+
+// MarshalJSON represents time in HH:MM format.
+func (t Minute) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON parses time from JSON string in HH:MM format.
+func (t *Minute) UnmarshalJSON(data []byte) error {
+	if t == nil {
+		return ErrNilReference
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed, err := ParseTime(raw)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
 }
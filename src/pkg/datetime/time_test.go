@@ -1,7 +1,9 @@
 package datetime
 
 import (
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -116,6 +118,8 @@ func TestTime_Add(t *testing.T) {
 		{"default", Minute(12*60 + 22), Minute(13*60 + 40), 78},
 		{"minutes overflows", Minute(12*60 + 22), Minute(13*60 + 00), 38},
 		{"hours overflows", Minute(23*60 + 59), Minute(2*60 + 59), 180},
+		{"negative", Minute(12*60 + 22), Minute(11*60 + 22), -60},
+		{"negative overflows", Minute(0), Minute(23*60 + 50), -10},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -124,6 +128,39 @@ func TestTime_Add(t *testing.T) {
 	}
 }
 
+func TestTime_Sub(t *testing.T) {
+	tt := []struct {
+		name  string
+		t     Minute
+		other Minute
+		want  int
+	}{
+		{"same", TestTime1, TestTime1, 0},
+		{"default", TestTime2, TestTime1, 2},
+		{"next day", TestTime1, TestTime2, 24*60 - 2},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.t.Sub(tc.other))
+			assert.Equal(t, tc.t, tc.other.Add(tc.want))
+		})
+	}
+}
+
+func TestTime_JSON(t *testing.T) {
+	data, err := json.Marshal(TestTime1)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"11:59"`, string(data))
+
+	var got Minute
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, TestTime1, got)
+
+	assert.Error(t, json.Unmarshal([]byte(`"24:00"`), &got))
+	assert.Error(t, json.Unmarshal([]byte(`12`), &got))
+	assert.ErrorIs(t, (*Minute)(nil).UnmarshalJSON(data), ErrNilReference)
+}
+
 func TestTime_String(t *testing.T) {
 	tt := []struct {
 		name   string
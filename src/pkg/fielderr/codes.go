@@ -13,6 +13,7 @@ const (
 	CodeForbidden
 	CodeNoContent
 	CodeOK
+	CodeUnavailable
 )
 
 var httpCodes = map[Code]int{
@@ -24,4 +25,5 @@ var httpCodes = map[Code]int{
 	CodeConflict:     http.StatusConflict,
 	CodeNoContent:    http.StatusNoContent,
 	CodeOK:           http.StatusOK,
+	CodeUnavailable:  http.StatusServiceUnavailable,
 }
@@ -0,0 +1,30 @@
+package model
+
+// Roles of API keys.
+const (
+	// RoleAdmin can do everything including issue and revocation of API keys.
+	RoleAdmin = "admin"
+	// RoleDispatcher manages couriers, orders and their assignment.
+	RoleDispatcher = "dispatcher"
+	// RoleCourier can only complete orders and change status of orders of courier who owns key.
+	RoleCourier = "courier"
+)
+
+// ValidRole returns true if API key can have role.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleDispatcher, RoleCourier:
+		return true
+	}
+	return false
+}
+
+// CanComplete returns true if key allows to complete order on behalf of courier with provided id.
+//
+// Courier keys complete only orders of their couriers, keys of other roles complete orders of any courier.
+func (k *APIKey) CanComplete(courierID int64) bool {
+	if k.Role != RoleCourier {
+		return true
+	}
+	return k.CourierID != nil && *k.CourierID == courierID
+}
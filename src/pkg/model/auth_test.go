@@ -0,0 +1,23 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidRole(t *testing.T) {
+	for _, role := range []string{RoleAdmin, RoleDispatcher, RoleCourier} {
+		assert.True(t, ValidRole(role), role)
+	}
+	assert.False(t, ValidRole(""))
+	assert.False(t, ValidRole("Admin"))
+}
+
+func TestAPIKey_CanComplete(t *testing.T) {
+	courier := int64(1)
+	assert.True(t, (&APIKey{Role: RoleAdmin}).CanComplete(2))
+	assert.True(t, (&APIKey{Role: RoleDispatcher}).CanComplete(2))
+	assert.True(t, (&APIKey{Role: RoleCourier, CourierID: &courier}).CanComplete(1))
+	assert.False(t, (&APIKey{Role: RoleCourier, CourierID: &courier}).CanComplete(2))
+	assert.False(t, (&APIKey{Role: RoleCourier}).CanComplete(1))
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix versions format of cursor, so it can be changed without breaking cursors which clients hold.
+const cursorPrefix = "id:"
+
+var ErrBadCursor = errors.New("bad cursor")
+
+// EncodeCursor returns opaque cursor of page which starts right after record with provided id.
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// DecodeCursor returns id of record after which page of cursor starts.
+func DecodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrBadCursor
+	}
+	s, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrBadCursor
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return 0, ErrBadCursor
+	}
+	return id, nil
+}
+
+// NextCursor returns cursor of page after the page of n records with the last record id, if page of provided limit
+// is full. Otherwise, there are no more records and empty cursor is returned.
+func NextCursor(n, limit int, last int64) string {
+	if limit <= 0 || n < limit {
+		return ""
+	}
+	return EncodeCursor(last)
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	for _, id := range []int64{0, 1, 12, 1 << 62} {
+		cursor := EncodeCursor(id)
+		assert.NotContains(t, cursor, "=")
+		got, err := DecodeCursor(cursor)
+		assert.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+}
+
+func TestDecodeCursor_Negative(t *testing.T) {
+	for _, cursor := range []string{
+		"",
+		"!!!",
+		"12",
+		base64.RawURLEncoding.EncodeToString([]byte("offset:12")),
+		base64.RawURLEncoding.EncodeToString([]byte("id:abc")),
+		base64.RawURLEncoding.EncodeToString([]byte("id:-1")),
+	} {
+		id, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrBadCursor, cursor)
+		assert.Zero(t, id)
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	assert.Equal(t, EncodeCursor(5), NextCursor(2, 2, 5))
+	assert.Empty(t, NextCursor(1, 2, 5))
+	assert.Empty(t, NextCursor(0, 0, 0))
+}
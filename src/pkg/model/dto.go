@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"math"
 )
 
 type (
@@ -15,6 +16,20 @@ type (
 		DeliveryHours []*datetime.TimeInterval `json:"delivery_hours" swaggertype:"array,string" validate:"required"`
 		Cost          int32                    `json:"cost" validate:"required"`
 		CompletedTime datetime.Time            `json:"completed_time,omitempty" swaggertype:"string"`
+		// DeliveryTime is estimated time of delivery of assigned order in HH:MM format.
+		DeliveryTime *datetime.Minute `json:"delivery_time,omitempty" swaggertype:"string" example:"12:25"`
+		// UnassignedReason explains why order was left unassigned by the last assignment.
+		UnassignedReason string `json:"unassigned_reason,omitempty" enums:"overweight,no_courier_in_region,no_hours_overlap,capacity_exhausted"`
+		// ParentOrderID is id of order which was split into sub-orders, one of which is this order.
+		ParentOrderID int64 `json:"parent_order_id,omitempty"`
+		// SubOrders are parts of order which is heavier than any courier can carry.
+		//
+		// Sub-orders are assigned independently, order is completed when all of its sub-orders are completed.
+		SubOrders []*OrderDTO `json:"sub_orders,omitempty"`
+		// Status is current status of order.
+		Status string `json:"status,omitempty" enums:"CREATED,ASSIGNED,IN_DELIVERY,COMPLETED,CANCELLED,FAILED" example:"CREATED"`
+		// CancelReason is reason which order was cancelled with.
+		CancelReason string `json:"cancel_reason,omitempty" example:"customer withdrew order"`
 	}
 	CreateOrderDTO struct {
 		Weight  float64 `json:"weight" validate:"required"`
@@ -34,12 +49,31 @@ type (
 		//
 		// String must be in HH:MM-HH:MM format where HH is hour (integer 0-23) and MM is minutes (integer 0-59).
 		WorkingHours []*datetime.TimeInterval `json:"working_hours" validate:"required" swaggertype:"array,string" example:"12:00-23:00,14:30-15:30"`
+		// Active is false for deactivated courier. Deactivated couriers are not listed and do not get orders.
+		Active bool `json:"active" example:"true"`
 	}
 	CreateCourierDTO struct {
 		CourierType  string                   `json:"courier_type" enums:"FOOT,BIKE,AUTO" validate:"required" example:"AUTO"`
 		Regions      []int32                  `json:"regions" validate:"required" example:"1,2,3"`
 		WorkingHours []*datetime.TimeInterval `json:"working_hours" swaggertype:"array,string" validate:"required" example:"12:00-23:00,14:30-15:30"`
 	}
+	// WebhookDTO is subscription of callback URL to events.
+	WebhookDTO struct {
+		WebhookID  int64    `json:"webhook_id" example:"1"`
+		URL        string   `json:"url" example:"https://partner.example/lavka/events"`
+		EventTypes []string `json:"event_types" enums:"order.created,order.assigned,order.completed,order.cancelled" example:"order.created,order.completed"`
+		// Secret is key of HMAC-SHA256 signature of deliveries. It is returned only when webhook is created.
+		Secret    string        `json:"secret,omitempty" example:"4f1c2a9e0b7d4e3f8a6c5b2d1e0f9a8b"`
+		CreatedAt datetime.Time `json:"created_at" swaggertype:"string"`
+	}
+	// APIKey is key of API client, key itself is not stored, only its hash is.
+	APIKey struct {
+		KeyID int64  `json:"key_id" example:"1"`
+		Role  string `json:"role" enums:"admin,dispatcher,courier" example:"courier"`
+		// CourierID is id of courier who owns key, it is set only for keys with courier role.
+		CourierID *int64        `json:"courier_id,omitempty" example:"1"`
+		CreatedAt datetime.Time `json:"created_at" swaggertype:"string"`
+	}
 )
 
 const (
@@ -52,6 +86,68 @@ const (
 	FootCourierTypeRatingConst
 )
 
+const (
+	FootCourierMaxWeight = 10
+	BikeCourierMaxWeight = 20
+	AutoCourierMaxWeight = 40
+
+	FootCourierMaxOrders = 2
+	BikeCourierMaxOrders = 4
+	AutoCourierMaxOrders = 7
+
+	FootCourierMaxRegions = 1
+	BikeCourierMaxRegions = 2
+	AutoCourierMaxRegions = 3
+)
+
+// MaxOrderParts is maximum count of sub-orders which one heavy order can be split into.
+const MaxOrderParts = 10
+
+// Delivery durations in minutes of first order in region and of each next order in the same region.
+const (
+	FootCourierFirstDeliveryMinutes = 25
+	BikeCourierFirstDeliveryMinutes = 12
+	AutoCourierFirstDeliveryMinutes = 8
+
+	FootCourierNextDeliveryMinutes = 10
+	BikeCourierNextDeliveryMinutes = 8
+	AutoCourierNextDeliveryMinutes = 4
+)
+
+// Parts returns count of sub-orders which order must be split into, so each of them can be carried by AUTO courier.
+func (d CreateOrderDTO) Parts() int {
+	if d.Weight <= AutoCourierMaxWeight {
+		return 1
+	}
+	return int(math.Ceil(d.Weight / AutoCourierMaxWeight))
+}
+
+// SubOrders returns parts of order which is heavier than AUTO courier can carry.
+//
+// Weight is split evenly and cost is split so that sum of costs of sub-orders is equal to cost of order. Region and
+// delivery hours are the same as of order. If order does not need to be split then nil will be returned.
+func (d CreateOrderDTO) SubOrders() []CreateOrderDTO {
+	parts := d.Parts()
+	if parts <= 1 {
+		return nil
+	}
+	res := make([]CreateOrderDTO, 0, parts)
+	cost, rest := d.Cost/int32(parts), d.Cost%int32(parts)
+	for i := 0; i < parts; i++ {
+		sub := CreateOrderDTO{
+			Weight:        d.Weight / float64(parts),
+			Regions:       d.Regions,
+			DeliveryHours: d.DeliveryHours,
+			Cost:          cost,
+		}
+		if int32(i) < rest {
+			sub.Cost++
+		}
+		res = append(res, sub)
+	}
+	return res
+}
+
 func (d *CourierDTO) EarningsConst() int32 {
 	if d == nil {
 		return unknownTypeConst
@@ -85,3 +181,93 @@ func (d *CourierDTO) RatingConst() int32 {
 		return unknownTypeConst
 	}
 }
+
+// MaxWeight returns maximum summary weight of orders that courier can carry at once.
+func (d *CourierDTO) MaxWeight() float64 {
+	if d == nil {
+		return unknownTypeConst
+	}
+
+	switch d.CourierType {
+	case FootCourierTypeString:
+		return FootCourierMaxWeight
+	case BikeCourierTypeString:
+		return BikeCourierMaxWeight
+	case AutoCourierTypeString:
+		return AutoCourierMaxWeight
+	default:
+		return unknownTypeConst
+	}
+}
+
+// MaxOrders returns maximum count of orders in one group of courier.
+func (d *CourierDTO) MaxOrders() int {
+	if d == nil {
+		return unknownTypeConst
+	}
+
+	switch d.CourierType {
+	case FootCourierTypeString:
+		return FootCourierMaxOrders
+	case BikeCourierTypeString:
+		return BikeCourierMaxOrders
+	case AutoCourierTypeString:
+		return AutoCourierMaxOrders
+	default:
+		return unknownTypeConst
+	}
+}
+
+// MaxRegions returns maximum count of distinct regions that courier can visit with one group of orders.
+func (d *CourierDTO) MaxRegions() int {
+	if d == nil {
+		return unknownTypeConst
+	}
+
+	switch d.CourierType {
+	case FootCourierTypeString:
+		return FootCourierMaxRegions
+	case BikeCourierTypeString:
+		return BikeCourierMaxRegions
+	case AutoCourierTypeString:
+		return AutoCourierMaxRegions
+	default:
+		return unknownTypeConst
+	}
+}
+
+// FirstDeliveryMinutes returns time in minutes which courier spends on delivery of first order in region.
+func (d *CourierDTO) FirstDeliveryMinutes() int {
+	if d == nil {
+		return unknownTypeConst
+	}
+
+	switch d.CourierType {
+	case FootCourierTypeString:
+		return FootCourierFirstDeliveryMinutes
+	case BikeCourierTypeString:
+		return BikeCourierFirstDeliveryMinutes
+	case AutoCourierTypeString:
+		return AutoCourierFirstDeliveryMinutes
+	default:
+		return unknownTypeConst
+	}
+}
+
+// NextDeliveryMinutes returns time in minutes which courier spends on delivery of each next order in the same region.
+func (d *CourierDTO) NextDeliveryMinutes() int {
+	if d == nil {
+		return unknownTypeConst
+	}
+
+	switch d.CourierType {
+	case FootCourierTypeString:
+		return FootCourierNextDeliveryMinutes
+	case BikeCourierTypeString:
+		return BikeCourierNextDeliveryMinutes
+	case AutoCourierTypeString:
+		return AutoCourierNextDeliveryMinutes
+	default:
+		return unknownTypeConst
+	}
+}
@@ -42,3 +42,147 @@ func TestCourierDTO_RatingConst(t *testing.T) {
 		})
 	}
 }
+
+func TestCourierDTO_MaxWeight(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier *CourierDTO
+		want    float64
+	}{
+		{"nil courier", nil, unknownTypeConst},
+		{"bad type", new(CourierDTO), unknownTypeConst},
+		{"auto", &CourierDTO{CourierType: AutoCourierTypeString}, AutoCourierMaxWeight},
+		{"bike", &CourierDTO{CourierType: BikeCourierTypeString}, BikeCourierMaxWeight},
+		{"foot", &CourierDTO{CourierType: FootCourierTypeString}, FootCourierMaxWeight},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.courier.MaxWeight())
+		})
+	}
+}
+
+func TestCourierDTO_MaxOrders(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier *CourierDTO
+		want    int
+	}{
+		{"nil courier", nil, unknownTypeConst},
+		{"bad type", new(CourierDTO), unknownTypeConst},
+		{"auto", &CourierDTO{CourierType: AutoCourierTypeString}, AutoCourierMaxOrders},
+		{"bike", &CourierDTO{CourierType: BikeCourierTypeString}, BikeCourierMaxOrders},
+		{"foot", &CourierDTO{CourierType: FootCourierTypeString}, FootCourierMaxOrders},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.courier.MaxOrders())
+		})
+	}
+}
+
+func TestCourierDTO_MaxRegions(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier *CourierDTO
+		want    int
+	}{
+		{"nil courier", nil, unknownTypeConst},
+		{"bad type", new(CourierDTO), unknownTypeConst},
+		{"auto", &CourierDTO{CourierType: AutoCourierTypeString}, AutoCourierMaxRegions},
+		{"bike", &CourierDTO{CourierType: BikeCourierTypeString}, BikeCourierMaxRegions},
+		{"foot", &CourierDTO{CourierType: FootCourierTypeString}, FootCourierMaxRegions},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.courier.MaxRegions())
+		})
+	}
+}
+
+func TestCourierDTO_FirstDeliveryMinutes(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier *CourierDTO
+		want    int
+	}{
+		{"nil courier", nil, unknownTypeConst},
+		{"bad type", new(CourierDTO), unknownTypeConst},
+		{"auto", &CourierDTO{CourierType: AutoCourierTypeString}, AutoCourierFirstDeliveryMinutes},
+		{"bike", &CourierDTO{CourierType: BikeCourierTypeString}, BikeCourierFirstDeliveryMinutes},
+		{"foot", &CourierDTO{CourierType: FootCourierTypeString}, FootCourierFirstDeliveryMinutes},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.courier.FirstDeliveryMinutes())
+		})
+	}
+}
+
+func TestCourierDTO_NextDeliveryMinutes(t *testing.T) {
+	tt := []struct {
+		name    string
+		courier *CourierDTO
+		want    int
+	}{
+		{"nil courier", nil, unknownTypeConst},
+		{"bad type", new(CourierDTO), unknownTypeConst},
+		{"auto", &CourierDTO{CourierType: AutoCourierTypeString}, AutoCourierNextDeliveryMinutes},
+		{"bike", &CourierDTO{CourierType: BikeCourierTypeString}, BikeCourierNextDeliveryMinutes},
+		{"foot", &CourierDTO{CourierType: FootCourierTypeString}, FootCourierNextDeliveryMinutes},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.courier.NextDeliveryMinutes())
+		})
+	}
+}
+
+func TestCreateOrderDTO_Parts(t *testing.T) {
+	tt := []struct {
+		name   string
+		weight float64
+		want   int
+	}{
+		{"zero", 0, 1},
+		{"light", 12, 1},
+		{"max", AutoCourierMaxWeight, 1},
+		{"heavy", AutoCourierMaxWeight + 1, 2},
+		{"twice max", 2 * AutoCourierMaxWeight, 2},
+		{"very heavy", 101, 3},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CreateOrderDTO{Weight: tc.weight}.Parts())
+		})
+	}
+}
+
+func TestCreateOrderDTO_SubOrders(t *testing.T) {
+	t.Run("light", func(t *testing.T) {
+		assert.Nil(t, CreateOrderDTO{Weight: AutoCourierMaxWeight, Cost: 10}.SubOrders())
+	})
+	t.Run("heavy", func(t *testing.T) {
+		order := CreateOrderDTO{
+			Weight:  105,
+			Regions: 3,
+			Cost:    100,
+		}
+		subs := order.SubOrders()
+		if assert.Len(t, subs, 3) {
+			var (
+				weight float64
+				cost   int32
+			)
+			for _, sub := range subs {
+				assert.LessOrEqual(t, sub.Weight, float64(AutoCourierMaxWeight))
+				assert.Equal(t, order.Regions, sub.Regions)
+				weight += sub.Weight
+				cost += sub.Cost
+			}
+			assert.InDelta(t, order.Weight, weight, 1e-9)
+			assert.Equal(t, order.Cost, cost)
+			assert.Equal(t, []int32{34, 33, 33}, []int32{subs[0].Cost, subs[1].Cost, subs[2].Cost})
+		}
+	})
+}
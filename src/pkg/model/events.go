@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+)
+
+// Types of domain events.
+const (
+	// EventCourierCreated is published for every created courier, payload is CourierDTO.
+	EventCourierCreated = "courier.created"
+	// EventOrderCreated is published for every created order with its sub-orders, payload is OrderDTO.
+	EventOrderCreated = "order.created"
+	// EventOrdersAssigned is published when assignment at date is saved, payload is OrderAssignResponse.
+	EventOrdersAssigned = "orders.assigned"
+	// EventOrderAssigned is published for every order which got ASSIGNED status, payload is OrderAssignedEvent.
+	EventOrderAssigned = "order.assigned"
+	// EventOrderCompleted is published for every order which got COMPLETED status, payload is OrderCompletedEvent.
+	EventOrderCompleted = "order.completed"
+	// EventOrderCancelled is published for every order which got CANCELLED status, payload is OrderCancelledEvent.
+	EventOrderCancelled = "order.cancelled"
+)
+
+// Event is domain event which is stored into outbox together with change that caused it.
+type Event struct {
+	// ID is increasing number of event, consumers may use it to drop events which they got twice.
+	ID        int64           `json:"id" example:"1"`
+	Type      string          `json:"type" enums:"courier.created,order.created,orders.assigned,order.assigned,order.completed,order.cancelled" example:"order.created"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt datetime.Time   `json:"created_at" swaggertype:"string"`
+}
+
+// orderEventTypes are types of events about changes of orders.
+var orderEventTypes = map[string]bool{
+	EventOrderCreated:   true,
+	EventOrderAssigned:  true,
+	EventOrderCompleted: true,
+	EventOrderCancelled: true,
+}
+
+// IsOrderEvent returns true if events of type are about changes of orders.
+func IsOrderEvent(typ string) bool {
+	return orderEventTypes[typ]
+}
+
+// OrderAssignedEvent is payload of event about order which was put into group of courier.
+type OrderAssignedEvent struct {
+	OrderID      int64  `json:"order_id" example:"1"`
+	CourierID    int64  `json:"courier_id" example:"1"`
+	GroupOrderID int64  `json:"group_order_id" example:"1"`
+	Region       int32  `json:"region" example:"1"`
+	Date         string `json:"date" example:"2023-01-01"`
+	// DeliveryTime is estimated time of delivery in HH:MM format.
+	DeliveryTime *datetime.Minute `json:"delivery_time,omitempty" swaggertype:"string" example:"12:25"`
+}
+
+// OrderCompletedEvent is payload of event about completed order.
+//
+// It has all fields of CompleteOrder, so consumers may decode it as CompleteOrder.
+type OrderCompletedEvent struct {
+	CourierID    int64         `json:"courier_id" example:"1"`
+	OrderID      int64         `json:"order_id" example:"1"`
+	CompleteTime datetime.Time `json:"complete_time" swaggertype:"string"`
+	Region       int32         `json:"region" example:"1"`
+}
+
+// OrderCancelledEvent is payload of event about cancelled order.
+type OrderCancelledEvent struct {
+	OrderID int64 `json:"order_id" example:"1"`
+	// CourierID is id of courier who had order when it was cancelled.
+	CourierID int64  `json:"courier_id,omitempty" example:"1"`
+	Region    int32  `json:"region" example:"1"`
+	Reason    string `json:"reason" example:"customer withdrew order"`
+}
+
+// SubscribeEventsRequest selects events about orders which are streamed to client.
+type SubscribeEventsRequest struct {
+	// CourierID selects only events about orders of courier if it is not zero.
+	CourierID int64
+	// Region selects only events about orders in region if it is not zero.
+	Region int32
+	// LastEventID is id of the last event which client got, if it is set then missed events are sent first.
+	LastEventID *int64
+}
+
+// Valid returns true if request has no negative ids.
+func (r *SubscribeEventsRequest) Valid() bool {
+	if r == nil {
+		return false
+	}
+	return r.CourierID >= 0 && r.Region >= 0 && (r.LastEventID == nil || *r.LastEventID >= 0)
+}
+
+// Match returns true if event about order of courier in region is selected by request.
+func (r *SubscribeEventsRequest) Match(courierID int64, region int32) bool {
+	return (r.CourierID == 0 || r.CourierID == courierID) && (r.Region == 0 || r.Region == region)
+}
+
+// NewEvent returns event of type with payload encoded into JSON.
+func NewEvent(typ string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("json: marshal %s payload: %w", typ, err)
+	}
+	return Event{Type: typ, Payload: data}, nil
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewEvent(t *testing.T) {
+	e, err := NewEvent(EventOrderCompleted, &CompleteOrder{CourierID: 1, OrderID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, EventOrderCompleted, e.Type)
+	assert.JSONEq(t, `{"courier_id":1,"order_id":2,"complete_time":"0001-01-01T00:00:00.000Z"}`, string(e.Payload))
+}
+
+func TestNewEvent_Negative(t *testing.T) {
+	_, err := NewEvent(EventOrderCreated, make(chan int))
+	assert.Error(t, err)
+}
+
+func TestSubscribeEventsRequest_Valid(t *testing.T) {
+	id, negative := int64(3), int64(-1)
+	assert.True(t, (&SubscribeEventsRequest{}).Valid())
+	assert.True(t, (&SubscribeEventsRequest{CourierID: 1, Region: 2, LastEventID: &id}).Valid())
+	assert.False(t, (*SubscribeEventsRequest)(nil).Valid())
+	assert.False(t, (&SubscribeEventsRequest{CourierID: -1}).Valid())
+	assert.False(t, (&SubscribeEventsRequest{Region: -1}).Valid())
+	assert.False(t, (&SubscribeEventsRequest{LastEventID: &negative}).Valid())
+}
+
+func TestSubscribeEventsRequest_Match(t *testing.T) {
+	assert.True(t, (&SubscribeEventsRequest{}).Match(0, 1))
+	assert.True(t, (&SubscribeEventsRequest{CourierID: 1}).Match(1, 5))
+	assert.False(t, (&SubscribeEventsRequest{CourierID: 1}).Match(0, 5))
+	assert.True(t, (&SubscribeEventsRequest{Region: 5}).Match(0, 5))
+	assert.False(t, (&SubscribeEventsRequest{Region: 5}).Match(1, 4))
+	assert.False(t, (&SubscribeEventsRequest{CourierID: 1, Region: 5}).Match(2, 5))
+}
@@ -3,4 +3,13 @@ package model
 type PaginationOpts interface {
 	Limit() int
 	Offset() int
+	// Cursor returns opaque cursor of page, empty cursor means that offset is used.
+	Cursor() string
+}
+
+type AssignOpts interface {
+	Strategy() string
+	DryRun() bool
+	Force() bool
+	Incremental() bool
 }
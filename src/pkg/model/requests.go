@@ -22,6 +22,28 @@ type (
 		OrderID      int64         `json:"order_id" validate:"required"`
 		CompleteTime datetime.Time `json:"complete_time,omitempty" swaggertype:"string" validate:"required"`
 	}
+	// CancelOrderRequest cancels orders which customers withdrew.
+	CancelOrderRequest struct {
+		OrderIDs []int64 `json:"order_ids" validate:"required" example:"1,2"`
+		Reason   string  `json:"reason" validate:"required" example:"customer withdrew order"`
+	}
+	// CreateWebhookRequest subscribes callback URL to events of provided types.
+	CreateWebhookRequest struct {
+		URL        string   `json:"url" validate:"required" example:"https://partner.example/lavka/events"`
+		EventTypes []string `json:"event_types" validate:"required" enums:"order.created,order.assigned,order.completed,order.cancelled" example:"order.created,order.completed"`
+		// Secret is key of HMAC-SHA256 signature of deliveries, random secret is generated if it is not provided.
+		Secret string `json:"secret,omitempty" example:"4f1c2a9e0b7d4e3f8a6c5b2d1e0f9a8b"`
+	}
+	// CreateAPIKeyRequest issues API key with role.
+	CreateAPIKeyRequest struct {
+		Role string `json:"role" enums:"admin,dispatcher,courier" validate:"required" example:"courier"`
+		// CourierID is id of courier who owns key, it is required for courier role and forbidden for other ones.
+		CourierID *int64 `json:"courier_id,omitempty" example:"1"`
+	}
+	// ChangeOrderStatusRequest moves order to status.
+	ChangeOrderStatusRequest struct {
+		Status string `json:"status" enums:"IN_DELIVERY,FAILED" validate:"required" example:"IN_DELIVERY"`
+	}
 	GetCourierRequest struct {
 		CourierID int64 `path:"courier_id" validate:"required"`
 	}
@@ -33,4 +55,13 @@ type (
 	CreateCourierRequest struct {
 		Couriers []CreateCourierDTO `json:"couriers" validate:"required"`
 	}
+	// UpdateCourierRequest changes provided fields of courier, fields which are not provided are kept.
+	UpdateCourierRequest struct {
+		CourierType *string `json:"courier_type,omitempty" enums:"FOOT,BIKE,AUTO" example:"BIKE"`
+		Regions     []int32 `json:"regions,omitempty" example:"1,2"`
+		// WorkingHours is string slice of strings that represents time interval.
+		//
+		// String must be in HH:MM-HH:MM format where HH is hour (integer 0-23) and MM is minutes (integer 0-59).
+		WorkingHours []*datetime.TimeInterval `json:"working_hours,omitempty" swaggertype:"array,string" example:"12:00-23:00"`
+	}
 )
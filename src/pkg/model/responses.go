@@ -6,13 +6,21 @@ import (
 
 type (
 	GroupOrders struct {
-		GroupOrderID int64      `json:"group_order_id"`
-		Orders       []OrderDTO `json:"orders" validate:"required"`
+		GroupOrderID int64 `json:"group_order_id"`
+		// DeliveryWindow is time interval in HH:MM-HH:MM format in which courier delivers group.
+		DeliveryWindow *datetime.TimeInterval `json:"delivery_window,omitempty" swaggertype:"string" example:"12:00-12:35"`
+		Orders         []OrderDTO             `json:"orders" validate:"required"`
 	}
 	CourierGroupOrders struct {
 		CourierID int64         `json:"courier_id"`
 		Orders    []GroupOrders `json:"orders"`
 	}
+	// UnassignedOrder is order which was not included into any group.
+	UnassignedOrder struct {
+		OrderID int64 `json:"order_id" example:"1"`
+		// Reason explains why order was not assigned.
+		Reason string `json:"reason" enums:"overweight,no_courier_in_region,no_hours_overlap,capacity_exhausted" example:"capacity_exhausted"`
+	}
 	CouriersCreateResponse struct {
 		Couriers []CourierDTO `json:"couriers"`
 	}
@@ -21,6 +29,59 @@ type (
 		Couriers []CourierDTO `json:"couriers"`
 		Limit    int          `json:"limit"`
 		Offset   int          `json:"offset"`
+		// NextCursor is cursor of the next page, it is empty if page is not full.
+		NextCursor string `json:"next_cursor,omitempty" example:"aWQ6MTI"`
+	}
+	GetOrdersResponse struct {
+		Orders []*OrderDTO `json:"orders"`
+		Limit  int         `json:"limit"`
+		Offset int         `json:"offset"`
+		// NextCursor is cursor of the next page, it is empty if page is not full.
+		NextCursor string `json:"next_cursor,omitempty" example:"aWQ6MTI"`
+	}
+	// OrderStatusChange is record of history of order status.
+	OrderStatusChange struct {
+		Status string `json:"status" enums:"CREATED,ASSIGNED,IN_DELIVERY,COMPLETED,CANCELLED,FAILED" example:"ASSIGNED"`
+		// Actor is who changed status: api, assignment or courier:<courier_id>.
+		Actor     string        `json:"actor" example:"assignment"`
+		ChangedAt datetime.Time `json:"changed_at" swaggertype:"string"`
+	}
+	// OrderHistoryResponse is history of order status from the oldest change to the newest one.
+	OrderHistoryResponse struct {
+		OrderID int64               `json:"order_id" example:"1"`
+		History []OrderStatusChange `json:"history"`
+	}
+	// CreateAPIKeyResponse is issued API key together with key itself.
+	CreateAPIKeyResponse struct {
+		APIKey
+		// Key is sent by client in "Authorization: Bearer <key>" header. It is returned only when key is issued.
+		Key string `json:"key" example:"9b0e6f6c1d2a4b3c8e7f5a4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b1a0"`
+	}
+	GetWebhooksResponse struct {
+		Webhooks []WebhookDTO `json:"webhooks"`
+	}
+	// WebhookDelivery is delivery of event to webhook with its attempts.
+	WebhookDelivery struct {
+		DeliveryID int64  `json:"delivery_id" example:"1"`
+		WebhookID  int64  `json:"webhook_id" example:"1"`
+		EventID    int64  `json:"event_id" example:"1"`
+		EventType  string `json:"event_type" enums:"order.created,order.assigned,order.completed,order.cancelled" example:"order.created"`
+		Status     string `json:"status" enums:"PENDING,DELIVERED,FAILED" example:"DELIVERED"`
+		// Attempts is number of attempts which were made to deliver event.
+		Attempts int32 `json:"attempts" example:"1"`
+		// LastStatusCode is status of the last response of webhook.
+		LastStatusCode int32 `json:"last_status_code,omitempty" example:"200"`
+		// LastError describes why the last attempt failed.
+		LastError string `json:"last_error,omitempty"`
+		// NextAttemptAt is time of the next attempt of pending delivery.
+		NextAttemptAt *datetime.Time `json:"next_attempt_at,omitempty" swaggertype:"string"`
+		DeliveredAt   *datetime.Time `json:"delivered_at,omitempty" swaggertype:"string"`
+		CreatedAt     datetime.Time  `json:"created_at" swaggertype:"string"`
+	}
+	GetWebhookDeliveriesResponse struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+		Limit      int               `json:"limit"`
+		Offset     int               `json:"offset"`
 	}
 	GetCourierMetaInfoResponse struct {
 		CourierID   int64   `json:"courier_id" validate:"required" example:"1"`
@@ -36,5 +97,76 @@ type (
 	OrderAssignResponse struct {
 		Date     string               `json:"date"`
 		Couriers []CourierGroupOrders `json:"couriers"`
+		// Strategy is name of strategy which was used to assign orders.
+		Strategy string `json:"strategy,omitempty" enums:"greedy,optimal" example:"greedy"`
+		// TotalCost is summary cost of delivery of all groups with grouping discount.
+		TotalCost float64 `json:"total_cost" example:"980"`
+		// Unassigned are orders which were not given to any courier.
+		Unassigned []UnassignedOrder `json:"unassigned,omitempty"`
+		// DryRun is true if assignment was only computed and was not stored.
+		DryRun bool `json:"dry_run,omitempty"`
 	}
 )
+
+// Reasons of leaving order unassigned.
+const (
+	// UnassignedReasonOverweight means that order is heavier than any courier who could deliver it can carry.
+	UnassignedReasonOverweight = "overweight"
+	// UnassignedReasonNoRegion means that no courier works in region of order.
+	UnassignedReasonNoRegion = "no_courier_in_region"
+	// UnassignedReasonNoHoursOverlap means that delivery hours of order do not overlap with working hours of couriers.
+	UnassignedReasonNoHoursOverlap = "no_hours_overlap"
+	// UnassignedReasonCapacityExhausted means that order could be delivered but couriers have no free time or capacity.
+	UnassignedReasonCapacityExhausted = "capacity_exhausted"
+)
+
+// Discounts of cost of orders, delivered in one group, in percents.
+const (
+	GroupFirstOrderCostPercent = 100
+	GroupNextOrderCostPercent  = 80
+)
+
+// GroupCost returns cost of delivery of orders with provided costs in one group.
+//
+// First order of group is paid fully and each next one is paid with discount.
+func GroupCost(costs ...int32) float64 {
+	var res float64
+	for i, cost := range costs {
+		if i == 0 {
+			res += float64(cost) * GroupFirstOrderCostPercent / 100
+		} else {
+			res += float64(cost) * GroupNextOrderCostPercent / 100
+		}
+	}
+	return res
+}
+
+// Cost returns cost of delivery of group.
+//
+// It is nilness safe function.
+func (g *GroupOrders) Cost() float64 {
+	if g == nil {
+		return 0
+	}
+	costs := make([]int32, 0, len(g.Orders))
+	for _, order := range g.Orders {
+		costs = append(costs, order.Cost)
+	}
+	return GroupCost(costs...)
+}
+
+// Cost returns summary cost of delivery of all groups.
+//
+// It is nilness safe function.
+func (resp *OrderAssignResponse) Cost() float64 {
+	if resp == nil {
+		return 0
+	}
+	var res float64
+	for _, courier := range resp.Couriers {
+		for i := range courier.Orders {
+			res += courier.Orders[i].Cost()
+		}
+	}
+	return res
+}
@@ -33,3 +33,38 @@ func TestOrderDTO_ValidJSON(t *testing.T) {
 	require.NoError(t, err)
 	assert.JSONEq(t, raw, string(got))
 }
+
+func TestGroupCost(t *testing.T) {
+	tt := []struct {
+		name  string
+		costs []int32
+		want  float64
+	}{
+		{"no orders", nil, 0},
+		{"one order", []int32{100}, 100},
+		{"many orders", []int32{100, 100, 50}, 220},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, GroupCost(tc.costs...), 1e-9)
+		})
+	}
+}
+
+func TestOrderAssignResponse_Cost(t *testing.T) {
+	assert.Zero(t, (*OrderAssignResponse)(nil).Cost())
+	assert.Zero(t, (*GroupOrders)(nil).Cost())
+
+	resp := &OrderAssignResponse{
+		Couriers: []CourierGroupOrders{
+			{Orders: []GroupOrders{
+				{Orders: []OrderDTO{{Cost: 100}, {Cost: 100}}},
+				{Orders: []OrderDTO{{Cost: 10}}},
+			}},
+			{Orders: []GroupOrders{
+				{Orders: []OrderDTO{{Cost: 50}, {Cost: 10}}},
+			}},
+		},
+	}
+	assert.InDelta(t, 180+10+58, resp.Cost(), 1e-9)
+}
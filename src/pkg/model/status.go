@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strconv"
+)
+
+// Statuses of order.
+const (
+	// OrderStatusCreated means that order waits for assignment.
+	OrderStatusCreated = "CREATED"
+	// OrderStatusAssigned means that order is put into group of courier.
+	OrderStatusAssigned = "ASSIGNED"
+	// OrderStatusInDelivery means that courier took order and delivers it.
+	OrderStatusInDelivery = "IN_DELIVERY"
+	// OrderStatusCompleted means that courier delivered order.
+	OrderStatusCompleted = "COMPLETED"
+	// OrderStatusCancelled means that order was cancelled before it was delivered.
+	OrderStatusCancelled = "CANCELLED"
+	// OrderStatusFailed means that courier could not deliver order.
+	OrderStatusFailed = "FAILED"
+)
+
+// MaxCancelReasonLength is maximum length of reason of order cancellation in bytes.
+const MaxCancelReasonLength = 256
+
+// Actors of changes of order status which are not made by courier.
+const (
+	// ActorAPI is client of API.
+	ActorAPI = "api"
+	// ActorAssignment is assignment of orders which gives orders to couriers and takes them back.
+	ActorAssignment = "assignment"
+)
+
+// orderTransitions are statuses which order can get from its current status.
+//
+// Order gets back to CREATED when assignment is replaced and order is taken from courier. COMPLETED, CANCELLED and
+// FAILED are final statuses.
+var orderTransitions = map[string][]string{
+	OrderStatusCreated:    {OrderStatusAssigned, OrderStatusCancelled},
+	OrderStatusAssigned:   {OrderStatusCreated, OrderStatusInDelivery, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusInDelivery: {OrderStatusCompleted, OrderStatusFailed},
+	OrderStatusCompleted:  {},
+	OrderStatusCancelled:  {},
+	OrderStatusFailed:     {},
+}
+
+// ValidOrderStatus returns true if status is known status of order.
+func ValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanCompleteParent returns true if order which is split into sub-orders can be completed from status.
+//
+// Parent order is never assigned and its status can not be changed directly: it waits in CREATED status until all of
+// its sub-orders are completed and then it is completed, so it skips ASSIGNED unlike other orders. Cancelled parent is
+// never completed.
+func CanCompleteParent(status string) bool {
+	return status == OrderStatusCreated
+}
+
+// CanChangeOrderStatus returns true if order with status from can get status to.
+func CanChangeOrderStatus(from, to string) bool {
+	for _, s := range orderTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CourierActor returns actor of changes of order status which are made by courier with provided id.
+func CourierActor(id int64) string {
+	return "courier:" + strconv.FormatInt(id, 10)
+}
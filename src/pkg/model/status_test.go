@@ -0,0 +1,60 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidOrderStatus(t *testing.T) {
+	for _, s := range []string{
+		OrderStatusCreated,
+		OrderStatusAssigned,
+		OrderStatusInDelivery,
+		OrderStatusCompleted,
+		OrderStatusCancelled,
+		OrderStatusFailed,
+	} {
+		assert.True(t, ValidOrderStatus(s), s)
+	}
+	for _, s := range []string{"", "created", "DONE"} {
+		assert.False(t, ValidOrderStatus(s), s)
+	}
+}
+
+func TestCanChangeOrderStatus(t *testing.T) {
+	tt := []struct {
+		from, to string
+		want     bool
+	}{
+		{OrderStatusCreated, OrderStatusAssigned, true},
+		{OrderStatusCreated, OrderStatusCancelled, true},
+		{OrderStatusCreated, OrderStatusCompleted, false},
+		{OrderStatusCreated, OrderStatusInDelivery, false},
+		{OrderStatusAssigned, OrderStatusCreated, true},
+		{OrderStatusAssigned, OrderStatusInDelivery, true},
+		{OrderStatusAssigned, OrderStatusCompleted, true},
+		{OrderStatusAssigned, OrderStatusFailed, false},
+		{OrderStatusInDelivery, OrderStatusCompleted, true},
+		{OrderStatusInDelivery, OrderStatusFailed, true},
+		{OrderStatusInDelivery, OrderStatusCancelled, false},
+		{OrderStatusCompleted, OrderStatusCreated, false},
+		{OrderStatusCancelled, OrderStatusAssigned, false},
+		{OrderStatusFailed, OrderStatusCreated, false},
+		{OrderStatusCreated, OrderStatusCreated, false},
+		{"", OrderStatusCreated, false},
+	}
+	for _, tc := range tt {
+		assert.Equal(t, tc.want, CanChangeOrderStatus(tc.from, tc.to), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestCanCompleteParent(t *testing.T) {
+	assert.True(t, CanCompleteParent(OrderStatusCreated))
+	for _, status := range []string{OrderStatusAssigned, OrderStatusCompleted, OrderStatusCancelled, OrderStatusFailed} {
+		assert.False(t, CanCompleteParent(status), status)
+	}
+}
+
+func TestCourierActor(t *testing.T) {
+	assert.Equal(t, "courier:12", CourierActor(12))
+}
@@ -3,6 +3,8 @@ package model
 import (
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/collections"
 	"golang.org/x/exp/constraints"
+	"net/url"
+	"strings"
 )
 
 var typeSet = collections.NewSet[string](FootCourierTypeString, AutoCourierTypeString, BikeCourierTypeString)
@@ -59,6 +61,27 @@ func (req *CreateCourierRequest) Valid() bool {
 	)
 }
 
+// Valid validates request.
+//
+// Request must change at least one field. It is nilness safe function.
+func (req *UpdateCourierRequest) Valid() bool {
+	if req == nil || req.CourierType == nil && req.Regions == nil && req.WorkingHours == nil {
+		return false
+	}
+	if req.CourierType != nil && !typeSet.Contain(*req.CourierType) {
+		return false
+	}
+	if req.Regions != nil && (len(req.Regions) == 0 || !collections.Distinct[int32](req.Regions...)) {
+		return false
+	}
+	for _, h := range req.WorkingHours {
+		if h == nil {
+			return false
+		}
+	}
+	return true
+}
+
 // Valid validates request.
 //
 // It is nilness safe function.
@@ -72,6 +95,10 @@ func (d CreateOrderDTO) Valid() bool {
 	}
 	ok := set.Len() == len(d.DeliveryHours) && len(d.DeliveryHours) > 0
 	ok = ok && d.Weight >= 0 && d.Regions >= 0 && d.Cost >= 0
+	// heavy order is split into sub-orders, each of them must cost something.
+	if parts := d.Parts(); parts > 1 {
+		ok = ok && parts <= MaxOrderParts && d.Cost >= int32(parts)
+	}
 	return ok
 }
 
@@ -87,3 +114,69 @@ func (req *CreateOrderRequest) Valid() bool {
 		req.Orders...,
 	)
 }
+
+// Valid validates request.
+//
+// Only statuses which courier reports can be set directly, other statuses are set by assignment, completion and
+// cancellation of orders. It is nilness safe function.
+func (req *ChangeOrderStatusRequest) Valid() bool {
+	if req == nil {
+		return false
+	}
+	return req.Status == OrderStatusInDelivery || req.Status == OrderStatusFailed
+}
+
+// Valid validates request.
+//
+// Order ids must be positive and distinct and reason must not be blank. It is nilness safe function.
+func (req *CancelOrderRequest) Valid() bool {
+	if req == nil || strings.TrimSpace(req.Reason) == "" || len(req.Reason) > MaxCancelReasonLength {
+		return false
+	}
+	orders := collections.NewSet[int64]()
+	return all[int64](
+		func(id int64) bool {
+			defer orders.Add(id)
+			return id > 0 && !orders.Contain(id)
+		},
+		req.OrderIDs...,
+	)
+}
+
+// Valid validates request.
+//
+// URL must be absolute http or https URL. Secret may be omitted, otherwise it must be long enough to be guessed hardly.
+// It is nilness safe function.
+func (req *CreateWebhookRequest) Valid() bool {
+	if req == nil || len(req.URL) > MaxWebhookURLLength || len(req.EventTypes) == 0 {
+		return false
+	}
+	if req.Secret != "" && (len(req.Secret) < MinWebhookSecretLength || len(req.Secret) > MaxWebhookSecretLength) {
+		return false
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return false
+	}
+	types := collections.NewSet[string]()
+	for _, typ := range req.EventTypes {
+		if !ValidWebhookEventType(typ) || types.Contain(typ) {
+			return false
+		}
+		types.Add(typ)
+	}
+	return true
+}
+
+// Valid validates request.
+//
+// Key with courier role must belong to courier, keys with other roles must not. It is nilness safe function.
+func (req *CreateAPIKeyRequest) Valid() bool {
+	if req == nil || !ValidRole(req.Role) {
+		return false
+	}
+	if req.Role == RoleCourier {
+		return req.CourierID != nil && *req.CourierID > 0
+	}
+	return req.CourierID == nil
+}
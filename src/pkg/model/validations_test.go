@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/datetime"
+	"strings"
 	"testing"
 )
 
@@ -247,6 +248,39 @@ func TestCreateCourierRequest_Valid(t *testing.T) {
 	}
 }
 
+func TestUpdateCourierRequest_Valid(t *testing.T) {
+	bike, unknown := BikeCourierTypeString, "unknown type"
+	tt := []struct {
+		name string
+		req  *UpdateCourierRequest
+		want assert.BoolAssertionFunc
+	}{
+		{"negative #1 - nil reference", nil, assert.False},
+		{"negative #2 - no fields", new(UpdateCourierRequest), assert.False},
+		{"negative #3 - bad courier type", &UpdateCourierRequest{CourierType: &unknown}, assert.False},
+		{"negative #4 - empty regions", &UpdateCourierRequest{Regions: []int32{}}, assert.False},
+		{"negative #5 - duplicated regions", &UpdateCourierRequest{Regions: []int32{1, 1}}, assert.False},
+		{"negative #6 - nil working hours", &UpdateCourierRequest{WorkingHours: []*datetime.TimeInterval{nil}}, assert.False},
+		{"positive #1 - courier type", &UpdateCourierRequest{CourierType: &bike}, assert.True},
+		{"positive #2 - regions", &UpdateCourierRequest{Regions: []int32{1, 2}}, assert.True},
+		{"positive #3 - no working hours", &UpdateCourierRequest{WorkingHours: []*datetime.TimeInterval{}}, assert.True},
+		{
+			"positive #4 - all fields",
+			&UpdateCourierRequest{
+				CourierType:  &bike,
+				Regions:      []int32{3},
+				WorkingHours: []*datetime.TimeInterval{testTimeInterval1(t), testTimeInterval2(t)},
+			},
+			assert.True,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want(t, tc.req.Valid())
+		})
+	}
+}
+
 func TestCreateOrderDTO_Valid(t *testing.T) {
 	tt := []struct {
 		name  string
@@ -325,6 +359,42 @@ func TestCreateOrderDTO_Valid(t *testing.T) {
 			},
 			assert.False,
 		},
+		{
+			"positive #2 - heavy order",
+			CreateOrderDTO{
+				Weight:  AutoCourierMaxWeight * 2.5,
+				Regions: 1,
+				DeliveryHours: []*datetime.TimeInterval{
+					testTimeInterval1(t),
+				},
+				Cost: 3,
+			},
+			assert.True,
+		},
+		{
+			"negative #6 - heavy order is too cheap to split",
+			CreateOrderDTO{
+				Weight:  AutoCourierMaxWeight * 2.5,
+				Regions: 1,
+				DeliveryHours: []*datetime.TimeInterval{
+					testTimeInterval1(t),
+				},
+				Cost: 2,
+			},
+			assert.False,
+		},
+		{
+			"negative #7 - too heavy order",
+			CreateOrderDTO{
+				Weight:  AutoCourierMaxWeight*MaxOrderParts + 1,
+				Regions: 1,
+				DeliveryHours: []*datetime.TimeInterval{
+					testTimeInterval1(t),
+				},
+				Cost: 1000,
+			},
+			assert.False,
+		},
 		{
 			"negative #5 - negative region",
 			CreateOrderDTO{
@@ -363,3 +433,88 @@ func TestCreateOrderRequest_Valid(t *testing.T) {
 		assert.False(t, req.Valid())
 	})
 }
+
+func TestChangeOrderStatusRequest_Valid(t *testing.T) {
+	assert.False(t, (*ChangeOrderStatusRequest)(nil).Valid())
+	for _, s := range []string{OrderStatusInDelivery, OrderStatusFailed} {
+		assert.True(t, (&ChangeOrderStatusRequest{Status: s}).Valid(), s)
+	}
+	for _, s := range []string{"", OrderStatusCreated, OrderStatusAssigned, OrderStatusCompleted, OrderStatusCancelled, "in_delivery"} {
+		assert.False(t, (&ChangeOrderStatusRequest{Status: s}).Valid(), s)
+	}
+}
+
+func TestCancelOrderRequest_Valid(t *testing.T) {
+	tt := []struct {
+		name string
+		req  *CancelOrderRequest
+		want assert.BoolAssertionFunc
+	}{
+		{"nil reference", nil, assert.False},
+		{"no orders", &CancelOrderRequest{Reason: "withdrew"}, assert.False},
+		{"no reason", &CancelOrderRequest{OrderIDs: []int64{1}}, assert.False},
+		{"blank reason", &CancelOrderRequest{OrderIDs: []int64{1}, Reason: " \t"}, assert.False},
+		{"long reason", &CancelOrderRequest{OrderIDs: []int64{1}, Reason: strings.Repeat("a", MaxCancelReasonLength+1)}, assert.False},
+		{"duplicated order", &CancelOrderRequest{OrderIDs: []int64{1, 2, 1}, Reason: "withdrew"}, assert.False},
+		{"bad order id", &CancelOrderRequest{OrderIDs: []int64{0}, Reason: "withdrew"}, assert.False},
+		{"positive", &CancelOrderRequest{OrderIDs: []int64{1, 2}, Reason: "withdrew"}, assert.True},
+		{"longest reason", &CancelOrderRequest{OrderIDs: []int64{1}, Reason: strings.Repeat("a", MaxCancelReasonLength)}, assert.True},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want(t, tc.req.Valid())
+		})
+	}
+}
+
+func TestCreateWebhookRequest_Valid(t *testing.T) {
+	const u = "http://127.0.0.1:8080/events"
+	tt := []struct {
+		name string
+		req  *CreateWebhookRequest
+		want assert.BoolAssertionFunc
+	}{
+		{"nil reference", nil, assert.False},
+		{"no events", &CreateWebhookRequest{URL: u}, assert.False},
+		{"unknown event", &CreateWebhookRequest{URL: u, EventTypes: []string{EventCourierCreated}}, assert.False},
+		{"duplicated event", &CreateWebhookRequest{URL: u, EventTypes: []string{EventOrderCreated, EventOrderCreated}}, assert.False},
+		{"no url", &CreateWebhookRequest{EventTypes: []string{EventOrderCreated}}, assert.False},
+		{"relative url", &CreateWebhookRequest{URL: "/events", EventTypes: []string{EventOrderCreated}}, assert.False},
+		{"bad scheme", &CreateWebhookRequest{URL: "ftp://partner/events", EventTypes: []string{EventOrderCreated}}, assert.False},
+		{"bad url", &CreateWebhookRequest{URL: "http://[::1", EventTypes: []string{EventOrderCreated}}, assert.False},
+		{"long url", &CreateWebhookRequest{URL: u + "?q=" + strings.Repeat("a", MaxWebhookURLLength), EventTypes: []string{EventOrderCreated}}, assert.False},
+		{"short secret", &CreateWebhookRequest{URL: u, EventTypes: []string{EventOrderCreated}, Secret: "secret"}, assert.False},
+		{"long secret", &CreateWebhookRequest{URL: u, EventTypes: []string{EventOrderCreated}, Secret: strings.Repeat("a", MaxWebhookSecretLength+1)}, assert.False},
+		{"positive", &CreateWebhookRequest{URL: u, EventTypes: []string{EventOrderCreated, EventOrderAssigned, EventOrderCompleted, EventOrderCancelled}}, assert.True},
+		{"https with secret", &CreateWebhookRequest{URL: "https://partner.example/events", EventTypes: []string{EventOrderCompleted}, Secret: strings.Repeat("a", MinWebhookSecretLength)}, assert.True},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want(t, tc.req.Valid())
+		})
+	}
+}
+
+func TestCreateAPIKeyRequest_Valid(t *testing.T) {
+	courier, zero := int64(1), int64(0)
+	tt := []struct {
+		name string
+		req  *CreateAPIKeyRequest
+		want assert.BoolAssertionFunc
+	}{
+		{"nil reference", nil, assert.False},
+		{"no role", &CreateAPIKeyRequest{}, assert.False},
+		{"unknown role", &CreateAPIKeyRequest{Role: "root"}, assert.False},
+		{"courier without courier id", &CreateAPIKeyRequest{Role: RoleCourier}, assert.False},
+		{"courier with zero courier id", &CreateAPIKeyRequest{Role: RoleCourier, CourierID: &zero}, assert.False},
+		{"dispatcher with courier id", &CreateAPIKeyRequest{Role: RoleDispatcher, CourierID: &courier}, assert.False},
+		{"admin", &CreateAPIKeyRequest{Role: RoleAdmin}, assert.True},
+		{"dispatcher", &CreateAPIKeyRequest{Role: RoleDispatcher}, assert.True},
+		{"courier", &CreateAPIKeyRequest{Role: RoleCourier, CourierID: &courier}, assert.True},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.want(t, tc.req.Valid())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+)
+
+// Statuses of delivery of event to webhook.
+const (
+	// DeliveryStatusPending means that event waits for the next attempt of delivery.
+	DeliveryStatusPending = "PENDING"
+	// DeliveryStatusDelivered means that webhook accepted event.
+	DeliveryStatusDelivered = "DELIVERED"
+	// DeliveryStatusFailed means that all attempts failed or webhook was deleted before event was delivered.
+	DeliveryStatusFailed = "FAILED"
+)
+
+// Limits of webhook subscription.
+const (
+	// MaxWebhookURLLength is maximum length of URL of webhook in bytes.
+	MaxWebhookURLLength = 2048
+	// MinWebhookSecretLength is minimum length of secret of webhook which is provided by client.
+	MinWebhookSecretLength = 16
+	// MaxWebhookSecretLength is maximum length of secret of webhook in bytes.
+	MaxWebhookSecretLength = 256
+)
+
+// ValidWebhookEventType returns true if webhook can subscribe to events of type.
+//
+// Webhooks can subscribe only to events about changes of orders.
+func ValidWebhookEventType(typ string) bool {
+	return IsOrderEvent(typ)
+}
+
+type (
+	// WebhookTask is delivery which is due together with everything needed to post it.
+	WebhookTask struct {
+		Delivery WebhookDelivery
+		URL      string
+		Secret   string
+		Event    Event
+	}
+	// WebhookAttempt is result of attempt to deliver event to webhook.
+	WebhookAttempt struct {
+		// StatusCode is status of response of webhook, it is zero if webhook was not reached.
+		StatusCode int32
+		// Error describes why attempt failed, it is empty for successful attempt.
+		Error string
+		// Status is status of delivery after attempt.
+		Status string
+		// RetryIn is delay of the next attempt of pending delivery.
+		RetryIn time.Duration
+	}
+)
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidWebhookEventType(t *testing.T) {
+	for _, typ := range []string{EventOrderCreated, EventOrderAssigned, EventOrderCompleted, EventOrderCancelled} {
+		assert.True(t, ValidWebhookEventType(typ), typ)
+	}
+	for _, typ := range []string{"", EventCourierCreated, EventOrdersAssigned, "order.*"} {
+		assert.False(t, ValidWebhookEventType(typ), typ)
+	}
+}
@@ -34,6 +34,28 @@ const (
 
 // New opens new postgres connection, configures it and return prepared client.
 func New(lc fx.Lifecycle, cfg Config, log *zap.Logger) (*Client, error) {
+	cli, err := Open(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	pool := cli.pool
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return retryer.TryWithAttemptsCtx(ctx, pool.Ping, RetryAttempts, RetryDelay)
+		},
+		OnStop: func(ctx context.Context) error {
+			pool.Close()
+			return nil
+		},
+	})
+	log.Info("created postgres client")
+	return cli, nil
+}
+
+// Open opens new postgres connection and configures it without binding it to application lifecycle.
+//
+// Caller is responsible for closing pool of returned client.
+func Open(cfg Config, log *zap.Logger) (*Client, error) {
 	var pool *pgxpool.Pool
 	log.Info("initializing postgres client with config", zap.Any("cfg", cfg))
 
@@ -59,16 +81,6 @@ func New(lc fx.Lifecycle, cfg Config, log *zap.Logger) (*Client, error) {
 		pool: pool,
 		log:  log,
 	}
-	lc.Append(fx.Hook{
-		OnStart: func(ctx context.Context) error {
-			return retryer.TryWithAttemptsCtx(ctx, pool.Ping, RetryAttempts, RetryDelay)
-		},
-		OnStop: func(ctx context.Context) error {
-			pool.Close()
-			return nil
-		},
-	})
-	log.Info("created postgres client")
 	return cli, nil
 }
 
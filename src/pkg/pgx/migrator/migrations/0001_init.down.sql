@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS orders_delivery_hours;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS courier_working_hour;
+DROP TABLE IF EXISTS courier_region;
+DROP TABLE IF EXISTS couriers;
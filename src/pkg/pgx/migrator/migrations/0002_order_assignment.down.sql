@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS order_assignment;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS order_group;
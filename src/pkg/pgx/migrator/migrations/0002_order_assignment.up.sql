@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS order_group
+(
+    id      BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
+    date    VARCHAR(10)                  NOT NULL,
+    courier BIGINT                       NOT NULL,
+    CONSTRAINT courier_fk FOREIGN KEY (courier) REFERENCES couriers MATCH FULL ON DELETE CASCADE
+);
+
+ALTER TABLE order_group
+    DROP CONSTRAINT IF EXISTS courier_date_unique;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS group_id BIGINT NULL
+        CONSTRAINT order_group_fk REFERENCES order_group (id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS order_assignment
+(
+    date       VARCHAR(10) PRIMARY KEY NOT NULL,
+    created_at TIMESTAMP               NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_group_date_idx ON order_group (date, courier);
@@ -0,0 +1,12 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS unassigned_reason;
+
+ALTER TABLE order_assignment
+    DROP COLUMN IF EXISTS strategy;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS delivery_time;
+
+ALTER TABLE order_group
+    DROP COLUMN IF EXISTS end_time,
+    DROP COLUMN IF EXISTS start_time;
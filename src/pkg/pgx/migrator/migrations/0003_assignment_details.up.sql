@@ -0,0 +1,12 @@
+ALTER TABLE order_group
+    ADD COLUMN IF NOT EXISTS start_time INT4 NULL,
+    ADD COLUMN IF NOT EXISTS end_time   INT4 NULL;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS delivery_time INT4 NULL;
+
+ALTER TABLE order_assignment
+    ADD COLUMN IF NOT EXISTS strategy VARCHAR(16) NULL;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS unassigned_reason VARCHAR(32) NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS order_group_assigned_check,
+    DROP CONSTRAINT IF EXISTS order_group_courier_fk;
+
+ALTER TABLE order_group
+    DROP CONSTRAINT IF EXISTS order_group_id_courier_unique;
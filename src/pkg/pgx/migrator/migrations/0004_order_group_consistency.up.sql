@@ -0,0 +1,30 @@
+DO
+$$
+    BEGIN
+        ALTER TABLE order_group
+            ADD CONSTRAINT order_group_id_courier_unique UNIQUE (id, courier);
+    EXCEPTION
+        WHEN duplicate_object OR duplicate_table THEN NULL;
+    END
+$$;
+
+DO
+$$
+    BEGIN
+        ALTER TABLE orders
+            ADD CONSTRAINT order_group_courier_fk FOREIGN KEY (group_id, courier)
+                REFERENCES order_group (id, courier) ON DELETE SET NULL;
+    EXCEPTION
+        WHEN duplicate_object THEN NULL;
+    END
+$$;
+
+DO
+$$
+    BEGIN
+        ALTER TABLE orders
+            ADD CONSTRAINT order_group_assigned_check CHECK (group_id IS NULL OR courier IS NOT NULL);
+    EXCEPTION
+        WHEN duplicate_object THEN NULL;
+    END
+$$;
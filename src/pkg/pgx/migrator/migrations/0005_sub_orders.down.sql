@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS orders_parent_id_idx;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT NULL
+        CONSTRAINT order_parent_fk REFERENCES orders (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS orders_parent_id_idx ON orders (parent_id);
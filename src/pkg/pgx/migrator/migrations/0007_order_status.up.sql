@@ -0,0 +1,47 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'CREATED';
+
+UPDATE orders
+SET status = CASE
+                 WHEN completed THEN 'COMPLETED'
+                 WHEN courier IS NOT NULL THEN 'ASSIGNED'
+                 ELSE 'CREATED' END
+WHERE status = 'CREATED';
+
+DO
+$$
+    BEGIN
+        ALTER TABLE orders
+            ADD CONSTRAINT orders_status_check
+                CHECK (status IN ('CREATED', 'ASSIGNED', 'IN_DELIVERY', 'COMPLETED', 'CANCELLED', 'FAILED'));
+    EXCEPTION
+        WHEN duplicate_object THEN NULL;
+    END
+$$;
+
+DO
+$$
+    BEGIN
+        ALTER TABLE orders
+            ADD CONSTRAINT orders_status_completed_check CHECK ((status = 'COMPLETED') = completed);
+    EXCEPTION
+        WHEN duplicate_object THEN NULL;
+    END
+$$;
+
+CREATE TABLE IF NOT EXISTS order_status_history
+(
+    id         BIGSERIAL PRIMARY KEY NOT NULL,
+    order_id   BIGINT                NOT NULL,
+    status     VARCHAR(16)           NOT NULL,
+    actor      TEXT                  NOT NULL,
+    changed_at TIMESTAMP             NOT NULL DEFAULT now(),
+    CONSTRAINT order_fk FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_id_idx ON order_status_history (order_id, id);
+
+INSERT INTO order_status_history(order_id, status, actor)
+SELECT o.id, o.status, 'migration'
+FROM orders o
+WHERE NOT EXISTS(SELECT * FROM order_status_history h WHERE h.order_id = o.id);
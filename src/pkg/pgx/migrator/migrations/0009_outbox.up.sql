@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id           BIGSERIAL PRIMARY KEY NOT NULL,
+    type         VARCHAR(64)           NOT NULL,
+    payload      JSONB                 NOT NULL,
+    created_at   TIMESTAMP             NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP             NULL
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE delivered_at IS NULL;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhooks
+(
+    id          BIGSERIAL PRIMARY KEY NOT NULL,
+    url         TEXT                  NOT NULL,
+    event_types VARCHAR(64)[]         NOT NULL,
+    secret      TEXT                  NOT NULL,
+    active      BOOLEAN               NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP             NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries
+(
+    id               BIGSERIAL PRIMARY KEY NOT NULL,
+    webhook_id       BIGINT                NOT NULL,
+    event_id         BIGINT                NOT NULL,
+    status           VARCHAR(16)           NOT NULL DEFAULT 'PENDING',
+    attempts         INT4                  NOT NULL DEFAULT 0,
+    last_status_code INT4                  NULL,
+    last_error       TEXT                  NULL,
+    next_attempt_at  TIMESTAMP             NOT NULL DEFAULT now(),
+    delivered_at     TIMESTAMP             NULL,
+    created_at       TIMESTAMP             NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_fk FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE,
+    CONSTRAINT event_fk FOREIGN KEY (event_id) REFERENCES outbox (id) ON DELETE CASCADE,
+    CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id         BIGSERIAL PRIMARY KEY NOT NULL,
+    key_hash   BYTEA                 NOT NULL,
+    role       VARCHAR(16)           NOT NULL,
+    courier_id BIGINT                NULL,
+    created_at TIMESTAMP             NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMP             NULL,
+    CONSTRAINT api_keys_hash_unique UNIQUE (key_hash),
+    CONSTRAINT courier_fk FOREIGN KEY (courier_id) REFERENCES couriers (id),
+    CONSTRAINT api_keys_role_check CHECK (role IN ('admin', 'dispatcher', 'courier')),
+    CONSTRAINT api_keys_courier_check CHECK ((role = 'courier') = (courier_id IS NOT NULL))
+);
@@ -2,117 +2,291 @@ package migrator
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/retryer"
+	"go.uber.org/zap"
+	"sort"
 	"time"
 )
 
 const (
 	migrationRetryAttempts = 2
 	migrationsRetryDelay   = time.Second
+	// lockKey is key of postgres advisory lock that serializes migration runs across instances.
+	lockKey int64 = 0x6d696772617465
 )
 
 var (
-	migrations = []string{
-		`CREATE TABLE IF NOT EXISTS couriers
-(
-    id           BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    courier_type TEXT                         NOT NULL,
-    CONSTRAINT couriers_courier_type_check
-        CHECK ((courier_type = 'FOOT'::TEXT) OR (courier_type = 'AUTO'::TEXT) OR
-               (courier_type = 'BIKE'::TEXT))
-);`,
-		`CREATE TABLE IF NOT EXISTS courier_region
-(
-    id         BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    region     BIGINT                       NOT NULL,
-    courier_id BIGINT                       NOT NULL,
-    CONSTRAINT courier_fk FOREIGN KEY (courier_id) REFERENCES couriers MATCH FULL
-);`,
-		`CREATE TABLE IF NOT EXISTS courier_working_hour
-(
-    id         BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    start_time INT4    DEFAULT 0::INT4      NOT NULL,
-    end_time   INT4    DEFAULT 0::INT4      NOT NULL,
-    reversed   BOOLEAN DEFAULT FALSE        NOT NULL,
-    courier_id BIGINT                       NOT NULL,
-    CONSTRAINT courier_fk FOREIGN KEY (courier_id) REFERENCES couriers
-);`,
-		`CREATE TABLE IF NOT EXISTS orders
-(
-    id             BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    weight         FLOAT8                       NOT NULL,
-    regions        INT4                         NOT NULL,
-    cost           INT4                         NOT NULL,
-    completed_time TIMESTAMP                    NULL,
-    courier        BIGINT                       NULL,
-    completed      BOOLEAN                      NOT NULL DEFAULT FALSE,
-    CONSTRAINT check_complete CHECK ( (completed AND completed_time IS NOT NULL) OR
-                                      (NOT completed AND completed_time IS NULL)),
-    CONSTRAINT courier_fk FOREIGN KEY (courier) REFERENCES couriers (id) MATCH FULL,
-    CONSTRAINT check_numerics CHECK ( cost > 0::INT4 AND regions > 0::INT4 AND weight > 0::FLOAT8 )
-);`,
-		`CREATE TABLE IF NOT EXISTS orders_delivery_hours
-(
-    id         BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    start_time INT4    DEFAULT 0::INT4      NOT NULL,
-    end_time   INT4    DEFAULT 0::INT4      NOT NULL,
-    reversed   BOOLEAN DEFAULT FALSE        NOT NULL,
-    order_id   BIGINT                       NOT NULL,
-    CONSTRAINT order_fk FOREIGN KEY (order_id) REFERENCES orders (id)
-);`,
-		`CREATE TABLE IF NOT EXISTS order_group
-(
-    id      BIGSERIAL PRIMARY KEY UNIQUE NOT NULL,
-    date    VARCHAR(10)                  NOT NULL,
-    courier BIGINT                       NOT NULL,
-    CONSTRAINT courier_fk FOREIGN KEY (courier) REFERENCES couriers MATCH FULL ON DELETE CASCADE,
-    CONSTRAINT courier_date_unique UNIQUE (date, courier)
-);`,
-	}
-	migrateDown = []string{
-		`DROP TABLE IF EXISTS order_group;`,
-		`DROP TABLE IF EXISTS orders_delivery_hours;`,
-		`DROP TABLE IF EXISTS orders;`,
-		`DROP TABLE IF EXISTS courier_working_hour;`,
-		`DROP TABLE IF EXISTS courier_region;`,
-		`DROP TABLE IF EXISTS couriers;`,
-	}
-	Migrations = len(migrations)
+	ErrNilReference   = errors.New("unexpectedly got nil reference in migrator")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrBadSteps       = errors.New("number of migrations to roll back must be positive")
+	// Migrations is number of embedded migrations.
+	Migrations int
+	// all is embedded migrations ordered by version.
+	all []Migration
 )
 
-func Migrate(cli pgx.Client) (int, error) {
-	i := 0
-	for _, migration := range migrations {
-		if err := retryer.TryWithAttempts(
-			func() error {
-				_, err := cli.P().Exec(context.Background(), migration)
-				return err
-			},
-			migrationRetryAttempts,
-			migrationsRetryDelay,
-		); err != nil {
-			return i, err
+func init() {
+	var err error
+	if all, err = load(embedded, "migrations"); err != nil {
+		panic(fmt.Sprintf("migrator: bad embedded migrations: %v", err))
+	}
+	Migrations = len(all)
+}
+
+// Status describes state of one migration in database.
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrator applies versioned migrations and tracks them in schema_migrations table.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	log        *zap.Logger
+	migrations []Migration
+}
+
+// New returns migrator over embedded migrations.
+func New(cli pgx.Client) (*Migrator, error) {
+	if cli == nil {
+		return nil, ErrNilReference
+	}
+	return &Migrator{
+		pool:       cli.P(),
+		log:        cli.L(),
+		migrations: all,
+	}, nil
+}
+
+// Latest returns version of the newest known migration or zero if there are no migrations.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies all pending migrations and returns number of applied ones.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.Goto(ctx, m.Latest())
+}
+
+// Down rolls back n most recently applied migrations and returns number of rolled back ones.
+func (m *Migrator) Down(ctx context.Context, n int) (int, error) {
+	if n <= 0 {
+		return 0, ErrBadSteps
+	}
+	return m.run(ctx, func(ctx context.Context, conn *pgxpool.Conn, applied map[int]time.Time) (int, error) {
+		versions := make([]int, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
 		}
-		i++
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		target := 0
+		if n < len(versions) {
+			target = versions[n]
+		}
+		return m.migrate(ctx, conn, applied, target)
+	})
+}
+
+// Goto migrates database up or down to version v and returns number of applied and rolled back migrations.
+//
+// Version zero rolls back all migrations.
+func (m *Migrator) Goto(ctx context.Context, v int) (int, error) {
+	if v != 0 && !m.known(v) {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, v)
 	}
-	return i, nil
+	return m.run(ctx, func(ctx context.Context, conn *pgxpool.Conn, applied map[int]time.Time) (int, error) {
+		return m.migrate(ctx, conn, applied, v)
+	})
 }
 
-func MigrateDown(cli pgx.Client) (int, error) {
-	i := 0
-	for _, migration := range migrateDown {
-		if err := retryer.TryWithAttempts(
-			func() error {
-				_, err := cli.P().Exec(context.Background(), migration)
-				return err
-			},
-			migrationRetryAttempts,
-			migrationsRetryDelay,
-		); err != nil {
-			return i, err
+// Status returns state of every known migration ordered by version.
+func (m *Migrator) Status(ctx context.Context) (res []Status, err error) {
+	_, err = m.run(ctx, func(_ context.Context, _ *pgxpool.Conn, applied map[int]time.Time) (int, error) {
+		res = status(m.migrations, applied)
+		return 0, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (m *Migrator) known(v int) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == v {
+			return true
+		}
+	}
+	return false
+}
+
+// run calls f holding advisory lock on dedicated connection.
+//
+// f gets versions of applied migrations with time they were applied at.
+func (m *Migrator) run(
+	ctx context.Context,
+	f func(ctx context.Context, conn *pgxpool.Conn, applied map[int]time.Time) (int, error),
+) (n int, err error) {
+	var conn *pgxpool.Conn
+	if conn, err = m.pool.Acquire(ctx); err != nil {
+		return 0, fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1);`, lockKey); err != nil {
+		return 0, fmt.Errorf("take migration lock: %w", err)
+	}
+	defer func() {
+		if _, unlockErr := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1);`, lockKey); unlockErr != nil {
+			m.log.Error("release migration lock", zap.Error(unlockErr))
+			// lock is owned by session, so closing connection releases it.
+			_ = conn.Conn().Close(context.Background())
+		}
+	}()
+
+	if _, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations
+(
+    version    BIGINT PRIMARY KEY NOT NULL,
+    name       TEXT               NOT NULL,
+    applied_at TIMESTAMP          NOT NULL DEFAULT now()
+);`); err != nil {
+		return 0, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var applied map[int]time.Time
+	if applied, err = m.applied(ctx, conn); err != nil {
+		return 0, err
+	}
+	return f(ctx, conn, applied)
+}
+
+// applied returns versions of applied migrations with time they were applied at.
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations;`)
+	if err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	res := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			v  int64
+			at time.Time
+		)
+		if err = rows.Scan(&v, &at); err != nil {
+			return nil, fmt.Errorf("get applied migrations: scan: %w", err)
+		}
+		res[int(v)] = at
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("get applied migrations: %w", err)
+	}
+	return res, nil
+}
+
+// migrate rolls back applied migrations newer than target and then applies pending ones up to target.
+func (m *Migrator) migrate(ctx context.Context, conn *pgxpool.Conn, applied map[int]time.Time, target int) (n int, err error) {
+	for _, mig := range rollback(m.migrations, applied, target) {
+		if err = m.apply(ctx, conn, mig, false); err != nil {
+			return n, err
+		}
+		n++
+	}
+	for _, mig := range pending(m.migrations, applied, target) {
+		if err = m.apply(ctx, conn, mig, true); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// apply runs up or down part of migration and records it in schema_migrations in one transaction.
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, mig Migration, up bool) error {
+	return retryer.TryWithAttempts(func() (err error) {
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: begin: %w", mig.Version, mig.Name, err)
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+
+		body, record, args := mig.Down, `DELETE FROM schema_migrations WHERE version = $1;`, []any{mig.Version}
+		if up {
+			body, record, args = mig.Up, `INSERT INTO schema_migrations(version, name) VALUES ($1, $2);`, []any{mig.Version, mig.Name}
+		}
+		if _, err = tx.Exec(ctx, body); err != nil {
+			return fmt.Errorf("migration %d_%s: %w", mig.Version, mig.Name, err)
 		}
-		i++
+		if _, err = tx.Exec(ctx, record, args...); err != nil {
+			return fmt.Errorf("migration %d_%s: record: %w", mig.Version, mig.Name, err)
+		}
+		if err = tx.Commit(ctx); err != nil {
+			return fmt.Errorf("migration %d_%s: commit: %w", mig.Version, mig.Name, err)
+		}
+		m.log.Info("migration done", zap.Int("version", mig.Version), zap.String("name", mig.Name), zap.Bool("up", up))
+		return nil
+	}, migrationRetryAttempts, migrationsRetryDelay)
+}
+
+// pending returns not applied migrations with version not greater than target in order they must be applied.
+func pending(migrations []Migration, applied map[int]time.Time, target int) []Migration {
+	res := make([]Migration, 0)
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok && mig.Version <= target {
+			res = append(res, mig)
+		}
+	}
+	return res
+}
+
+// rollback returns applied migrations with version greater than target in order they must be rolled back.
+func rollback(migrations []Migration, applied map[int]time.Time, target int) []Migration {
+	res := make([]Migration, 0)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[migrations[i].Version]; ok && migrations[i].Version > target {
+			res = append(res, migrations[i])
+		}
+	}
+	return res
+}
+
+// status merges known migrations with applied ones.
+func status(migrations []Migration, applied map[int]time.Time) []Status {
+	res := make([]Status, 0, len(migrations))
+	for _, mig := range migrations {
+		at, ok := applied[mig.Version]
+		res = append(res, Status{Version: mig.Version, Name: mig.Name, Applied: ok, AppliedAt: at})
+	}
+	return res
+}
+
+// Migrate applies all pending migrations and returns number of applied ones.
+func Migrate(cli pgx.Client) (int, error) {
+	m, err := New(cli)
+	if err != nil {
+		return 0, err
+	}
+	return m.Up(context.Background())
+}
+
+// MigrateDown rolls back all applied migrations and returns number of rolled back ones.
+func MigrateDown(cli pgx.Client) (int, error) {
+	m, err := New(cli)
+	if err != nil {
+		return 0, err
 	}
-	return i, nil
+	return m.Goto(context.Background(), 0)
 }
@@ -1,7 +1,9 @@
 package migrator_test
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/client"
 	"github.com/vlad-marlo/yandex-academy-enrollment/pkg/pgx/migrator"
 	"testing"
@@ -10,11 +12,19 @@ import (
 func TestMigrate_Positive(t *testing.T) {
 	cli, td := client.NewTest(t)
 	defer td()
+
+	// client is already migrated.
 	i, err := migrator.Migrate(cli)
 	assert.NoError(t, err)
-	if assert.NotEmpty(t, i) {
-		assert.Equal(t, migrator.Migrations, i)
-	}
+	assert.Zero(t, i)
+
+	i, err = migrator.MigrateDown(cli)
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Migrations, i)
+
+	i, err = migrator.Migrate(cli)
+	assert.NoError(t, err)
+	assert.Equal(t, migrator.Migrations, i)
 }
 
 func TestMigrate_Negative(t *testing.T) {
@@ -30,3 +40,74 @@ func TestMigrateDown_Negative(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, i)
 }
+
+func TestNew_Negative(t *testing.T) {
+	m, err := migrator.New(nil)
+	assert.ErrorIs(t, err, migrator.ErrNilReference)
+	assert.Nil(t, m)
+}
+
+func TestMigrator_Latest(t *testing.T) {
+	m, err := migrator.New(client.BadCli(t))
+	require.NoError(t, err)
+	assert.Equal(t, migrator.Migrations, m.Latest())
+}
+
+func TestMigrator_DownGotoStatus(t *testing.T) {
+	ctx := context.Background()
+	cli, td := client.NewTest(t)
+	defer td()
+
+	m, err := migrator.New(cli)
+	require.NoError(t, err)
+
+	status, err := m.Status(ctx)
+	require.NoError(t, err)
+	if assert.Len(t, status, migrator.Migrations) {
+		for _, s := range status {
+			assert.True(t, s.Applied, s.Name)
+			assert.False(t, s.AppliedAt.IsZero())
+		}
+	}
+
+	i, err := m.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, i)
+
+	status, err = m.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range status {
+		assert.Equal(t, s.Version <= m.Latest()-2, s.Applied, s.Name)
+	}
+
+	i, err = m.Goto(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, m.Latest()-3, i)
+
+	i, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, m.Latest()-1, i)
+
+	i, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, i)
+}
+
+func TestMigrator_Negative(t *testing.T) {
+	ctx := context.Background()
+	m, err := migrator.New(client.BadCli(t))
+	require.NoError(t, err)
+
+	_, err = m.Goto(ctx, m.Latest()+1)
+	assert.ErrorIs(t, err, migrator.ErrUnknownVersion)
+
+	_, err = m.Down(ctx, 0)
+	assert.ErrorIs(t, err, migrator.ErrBadSteps)
+
+	_, err = m.Down(ctx, 1)
+	assert.Error(t, err)
+
+	status, err := m.Status(ctx)
+	assert.Error(t, err)
+	assert.Nil(t, status)
+}
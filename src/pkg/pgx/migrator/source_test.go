@@ -0,0 +1,96 @@
+package migrator
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := load(embedded, "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version, "versions must have no gaps")
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_second.up.sql":   {Data: []byte("up 2")},
+		"m/0002_second.down.sql": {Data: []byte("down 2")},
+		"m/0001_first.up.sql":    {Data: []byte("up 1")},
+		"m/0001_first.down.sql":  {Data: []byte("down 1")},
+	}
+	migrations, err := load(fsys, "m")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "first", Up: "up 1", Down: "down 1"},
+		{Version: 2, Name: "second", Up: "up 2", Down: "down 2"},
+	}, migrations)
+}
+
+func TestLoad_Negative(t *testing.T) {
+	tt := []struct {
+		name string
+		fsys fstest.MapFS
+		err  error
+	}{
+		{
+			name: "bad name",
+			fsys: fstest.MapFS{"m/first.up.sql": {Data: []byte("up")}},
+			err:  ErrBadFileName,
+		},
+		{
+			name: "zero version",
+			fsys: fstest.MapFS{"m/0000_first.up.sql": {Data: []byte("up")}},
+			err:  ErrBadFileName,
+		},
+		{
+			name: "duplicate version",
+			fsys: fstest.MapFS{
+				"m/0001_first.up.sql":  {Data: []byte("up")},
+				"m/0001_second.up.sql": {Data: []byte("up")},
+			},
+			err: ErrDuplicateVersion,
+		},
+		{
+			name: "no down",
+			fsys: fstest.MapFS{"m/0001_first.up.sql": {Data: []byte("up")}},
+			err:  ErrMissingPart,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			migrations, err := load(tc.fsys, "m")
+			assert.ErrorIs(t, err, tc.err)
+			assert.Nil(t, migrations)
+		})
+	}
+
+	_, err := load(fstest.MapFS{}, "unknown")
+	assert.Error(t, err)
+}
+
+func TestPlan(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	applied := map[int]time.Time{1: at, 2: at, 4: at}
+
+	assert.Equal(t, []Migration{{Version: 3}}, pending(migrations, applied, 4))
+	assert.Empty(t, pending(migrations, applied, 2))
+	assert.Empty(t, rollback(migrations, applied, 4))
+	assert.Equal(t, []Migration{{Version: 4}, {Version: 2}}, rollback(migrations, applied, 1))
+	assert.Equal(t, []Migration{{Version: 4}, {Version: 2}, {Version: 1}}, rollback(migrations, applied, 0))
+
+	assert.Equal(t, []Status{
+		{Version: 1, Applied: true, AppliedAt: at},
+		{Version: 2, Applied: true, AppliedAt: at},
+		{Version: 3},
+		{Version: 4, Applied: true, AppliedAt: at},
+	}, status(migrations, applied))
+}